@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +26,8 @@ type state struct {
 	changed       bool
 	filter        drydocker.ContainerFilter
 	filterPattern string
+	//labelSelector selects the containers shown on the monitor by their labels
+	labelSelector drydocker.LabelSelector
 	sync.RWMutex
 	previousViewMode     viewMode
 	showingAllContainers bool
@@ -47,11 +50,17 @@ type Dry struct {
 	inspectedImage     types.ImageInspect
 	inspectedNetwork   types.NetworkResource
 	lastRefresh        time.Time
-	networks           []types.NetworkResource
-	orderedCids        []string
-	output             chan string
-	refreshTimerMutex  sync.Locker
-	state              *state
+	activeMonitor      *appui.Monitor
+	//collectors collect the stats of the monitored hosts whatever the view shown
+	collectors        drydocker.Collectors
+	monitorHosts      []appui.MonitorHost
+	mountsContainer   *types.Container
+	networks          []types.NetworkResource
+	orderedCids       []string
+	output            chan string
+	rawStats          *types.StatsJSON
+	refreshTimerMutex sync.Locker
+	state             *state
 	//cache is a potential replacement for state
 	cache *cache.Cache
 }
@@ -69,7 +78,7 @@ func (d *Dry) changeViewMode(newViewMode viewMode) {
 	defer d.state.Unlock()
 	//If the new view is one of the main screens, it must be
 	//considered as the view to go back to.
-	if newViewMode == Main || newViewMode == Networks || newViewMode == Images {
+	if newViewMode == Main || newViewMode == Networks || newViewMode == Images || newViewMode == Monitor {
 		d.state.previousViewMode = newViewMode
 	}
 	d.state.viewMode = newViewMode
@@ -78,6 +87,10 @@ func (d *Dry) changeViewMode(newViewMode viewMode) {
 
 //Close closes dry, releasing any resources held by it
 func (d *Dry) Close() {
+	d.state.RLock()
+	collectors := d.collectors
+	d.state.RUnlock()
+	collectors.Stop()
 	close(d.dockerEventsDone)
 	close(d.output)
 }
@@ -121,6 +134,18 @@ func (d *Dry) HistoryAt(position int) {
 	}
 }
 
+//ShowContainerMounts prepares dry to show the mounts of the given container
+func (d *Dry) ShowContainerMounts(container *types.Container) {
+	d.changeViewMode(ContainerMountsMode)
+	d.mountsContainer = container
+}
+
+//ShowRawStats prepares dry to show the given stats sample as received from the Docker API
+func (d *Dry) ShowRawStats(stats *types.StatsJSON) {
+	d.changeViewMode(RawStatsMode)
+	d.rawStats = stats
+}
+
 //History  prepares dry to show image history
 func (d *Dry) History(id string) {
 	history, err := d.dockerDaemon.History(id)
@@ -275,7 +300,7 @@ func (d *Dry) doRefresh() {
 	d.state.changed = true
 	var err error
 	switch d.state.viewMode {
-	case Main:
+	case Main, Monitor:
 		err = d.dockerDaemon.Refresh(d.state.showingAllContainers)
 		d.dockerDaemon.Sort(d.state.SortMode)
 	case Images:
@@ -395,6 +420,22 @@ func (d *Dry) Rm(id string) {
 	}
 }
 
+//SetLabelSelector sets the label selector of the containers shown on the monitor,
+//an empty selector removes it. An error is returned if the selector cannot be parsed.
+func (d *Dry) SetLabelSelector(selector string) error {
+	var s drydocker.LabelSelector
+	if selector != "" {
+		var err error
+		if s, err = drydocker.ParseLabelSelector(selector); err != nil {
+			return err
+		}
+	}
+	d.state.Lock()
+	defer d.state.Unlock()
+	d.state.labelSelector = s
+	return nil
+}
+
 //SetContainerFilter sets a filter for the container list
 func (d *Dry) SetContainerFilter(filter string) {
 	d.state.Lock()
@@ -479,7 +520,8 @@ func (d *Dry) ShowInfo() error {
 }
 
 //Sort rotates to the next sort mode.
-//SortByContainerID -> SortByImage -> SortByStatus -> SortByName -> SortByContainerID
+//SortByContainerID -> SortByImage -> SortByStatus -> SortByName -> SortByUptime ->
+//SortByUptimeNewest -> SortByContainerID
 func (d *Dry) Sort() {
 	d.state.RLock()
 	defer d.state.RUnlock()
@@ -491,6 +533,10 @@ func (d *Dry) Sort() {
 	case drydocker.SortByStatus:
 		d.state.SortMode = drydocker.SortByName
 	case drydocker.SortByName:
+		d.state.SortMode = drydocker.SortByUptime
+	case drydocker.SortByUptime:
+		d.state.SortMode = drydocker.SortByUptimeNewest
+	case drydocker.SortByUptimeNewest:
 		d.state.SortMode = drydocker.SortByContainerID
 	default:
 	}
@@ -631,6 +677,11 @@ func (d *Dry) appmessage(message string) {
 	}()
 }
 
+//Alert shows the given alert message
+func (d *Dry) Alert(message string) {
+	d.appmessage(fmt.Sprintf("<red>%s</>", message))
+}
+
 func (d *Dry) actionMessage(cid interface{}, action string) {
 	d.appmessage(fmt.Sprintf("<red>%s container with id </><white>%v</>",
 		action, cid))
@@ -643,6 +694,84 @@ func (d *Dry) errorMessage(cid interface{}, action string, err error) {
 			action, cid, err.Error()))
 }
 
+//AddMonitorHost connects to the Docker daemon defined by the given env, the
+//containers of the daemon are shown on the monitor along with the containers
+//of the daemon dry is connected to.
+func (d *Dry) AddMonitorHost(env *drydocker.Env) error {
+	daemon, err := drydocker.ConnectToDaemon(env)
+	if err != nil {
+		return err
+	}
+	d.state.Lock()
+	defer d.state.Unlock()
+	d.monitorHosts = append(d.monitorHosts, appui.MonitorHost{
+		Name:   hostName(env.DockerHost),
+		Daemon: daemon})
+	return nil
+}
+
+//CollectStats starts collecting the stats of the containers of every monitored
+//host, whatever the view shown, and returns the collectors, i.e. to export the
+//stats. Hosts must be added before. Stats are collected until dry is closed.
+func (d *Dry) CollectStats() drydocker.Collectors {
+	var collectors drydocker.Collectors
+	for _, host := range d.hostsToMonitor() {
+		collector := drydocker.NewCollector(host.Daemon)
+		collector.Start()
+		collectors = append(collectors, collector)
+	}
+	d.state.Lock()
+	d.collectors = append(d.collectors, collectors...)
+	d.state.Unlock()
+	return collectors
+}
+
+//ReloadTLS reads again the TLS certificates used to connect to the Docker
+//daemons, the monitor is shown again so its stats streams are opened with
+//the new certificates.
+func (d *Dry) ReloadTLS() {
+	for _, host := range d.hostsToMonitor() {
+		if err := host.Daemon.ReloadTLS(); err != nil {
+			d.appmessage(fmt.Sprintf("<red>Error reloading TLS certificates of %s: %s</>", host.Name, err.Error()))
+			return
+		}
+	}
+	d.Refresh()
+	d.appmessage("<white>TLS certificates reloaded</>")
+}
+
+//hostsToMonitor returns the hosts to show on the monitor, the first one is the
+//daemon dry is connected to.
+func (d *Dry) hostsToMonitor() []appui.MonitorHost {
+	d.state.RLock()
+	defer d.state.RUnlock()
+	hosts := []appui.MonitorHost{{
+		Name:   hostName(d.dockerDaemon.DockerEnv().DockerHost),
+		Daemon: d.dockerDaemon}}
+	return append(hosts, d.monitorHosts...)
+}
+
+//hostName returns the given Docker host without its protocol
+func hostName(dockerHost string) string {
+	if i := strings.Index(dockerHost, "://"); i >= 0 {
+		return dockerHost[i+3:]
+	}
+	return dockerHost
+}
+
+//monitor returns the container monitor being shown, if any
+func (d *Dry) monitor() *appui.Monitor {
+	d.state.RLock()
+	defer d.state.RUnlock()
+	return d.activeMonitor
+}
+
+func (d *Dry) setMonitor(monitor *appui.Monitor) {
+	d.state.Lock()
+	defer d.state.Unlock()
+	d.activeMonitor = monitor
+}
+
 func (d *Dry) viewMode() viewMode {
 	d.state.RLock()
 	defer d.state.RUnlock()
@@ -37,7 +37,7 @@ Visit <blue>http://moncho.github.io/dry/</> for more information.
 
 
 <yellow>Container list keybinds</>
-	<white>F1</>        Cycles through containers sort modes (by Id | by Image | by Status | by Name)
+	<white>F1</>        Cycles through containers sort modes (by Id | by Image | by Status | by Name | by Uptime | by Uptime, newest first)
 	<white>F2</>        Toggles showing all containers (default shows just running)
 	<white>F3</>        Filters containers by its name	
 	<white>F5</>        Refreshes container list
@@ -50,6 +50,32 @@ Visit <blue>http://moncho.github.io/dry/</> for more information.
 	<white>Crtl+t</>    Stops selected container (noop if it is not running)
 	<white>Enter</>     Returns low-level information of the selected container
 
+<yellow>Monitor mode keybinds</>
+	<white>Enter</>     Shows or hides the detail of the selected container: mounts, networks, ports and recent events
+	<white>v</>         Shows the mounts of the selected container, with full paths
+	<white>c</>         Copies the ID of the selected container to the clipboard
+	<white>e</>         Switches the container IDs shown between short, medium and full
+	<white>n</>         Writes a note about the selected container, shown on its detail
+	<white>p</>         Pins the selected container to the top, or unpins it
+	<white>o</>         Shows or collapses the stopped containers when showing all containers
+	<white>r</>         Switches the network and block IO columns between totals and rates per second
+	<white>i</>         Groups containers by image, showing the totals of each image
+	<white>f</>         Hides infrastructure containers, such as pause containers, groups them after the rest or shows them
+	<white>u</>         Shows together the containers sharing a PID namespace
+	<white>j</>         Shows the last stats of the selected container as received from Docker, in JSON
+	<white>w</>         Shows the selected container full screen, Left and Right switch the container shown
+	<white>x</>         Compares two containers side by side, press it on each container
+	<white>z</>         Resets the statistics accumulated, such as averages
+	<white>d</>         Dismisses the selected container if it has stopped
+	<white>g</>         Shows diagnostics of the stats streams, such as how many are open
+	<white>/</>         Jumps to the first container whose name matches the text typed, Esc cancels
+	<white>F2</>        Toggles showing all containers, stopped ones are collapsed on a single row
+	<white>F3</>        Filters containers by its name
+	<white>l</>         Filters containers by its labels, i.e. env=prod,team!=infra,!deprecated
+	<white>Ctrl+k</>    Kills the selected container
+	<white>Ctrl+r</>    Restarts selected container
+	<white>Ctrl+t</>    Stops selected container
+
 <yellow>Image list keybinds</>
 	<white>F1</>        Cycles through images sort modes (by Repo | by Id | by Creation date | by Size)
 	<white>F5</>        Refresh the image list
@@ -85,7 +111,8 @@ const (
 		"<b>[m]:<darkgrey>Monitor mode</> <b>[2]:<darkgrey>Images</> <b>[3]:<darkgrey>Networks</> <blue>|</> <b>[Enter]:<darkgrey>Commands</></>"
 
 	monitorMapping = commonMappings +
-		"<b>[1]:<darkgrey>Containers</> <b>[2]:<darkgrey>Images</> <b>[3]:<darkgrey>Networks</> <blue>"
+		"<b>[1]:<darkgrey>Containers</> <b>[2]:<darkgrey>Images</> <b>[3]:<darkgrey>Networks</> <blue>|</> " +
+		"<b>[F1]:<darkgrey>Sort</> <b>[Enter]:<darkgrey>Detail</> <b>[v]:<darkgrey>Mounts</> <b>[c]:<darkgrey>Copy ID</> <b>[p]:<darkgrey>Pin</> <b>[w]:<darkgrey>Watch</> <b>[x]:<darkgrey>Compare</> <b>[i]:<darkgrey>By image</> <b>[z]:<darkgrey>Reset stats</> <b>[d]:<darkgrey>Dismiss</> <b>[/]:<darkgrey>Search</> <b>[F2]:<darkgrey>Show all</> <b>[o]:<darkgrey>Stopped</> <b>[F3]:<darkgrey>Filter(By Name)</> <b>[l]:<darkgrey>Filter(By Labels)</> <b>[Ctrl+K]:<darkgrey>Kill</> <b>[Ctrl+R]:<darkgrey>Restart</> <b>[Ctrl+T]:<darkgrey>Stop</>"
 
 	imagesKeyMappings = commonMappings +
 		"<b>[F1]:<darkgrey>Sort</> <b>[F5]:<darkgrey>Refresh</> <blue>|</> " +
@@ -33,6 +33,21 @@ func (f *focusTracker) flip() {
 	f.focus = !f.focus
 }
 
+//quitKeys are the keys that quit dry if the view shown has the focus, Ctrl+C
+//quits dry no matter what
+var quitKeys = []monitorKey{{ch: 'q'}, {ch: 'Q'}}
+
+//isQuitKey returns true if the given key event is one of the quit keys
+func isQuitKey(event termbox.Event) bool {
+	pressed := eventKey(event)
+	for _, k := range quitKeys {
+		if k == pressed {
+			return true
+		}
+	}
+	return false
+}
+
 //RenderLoop renders dry until it quits
 func RenderLoop(dry *Dry, screen *ui.Screen) {
 	if ok, _ := dry.Ok(); !ok {
@@ -151,7 +166,7 @@ loop:
 			break loop
 		case termbox.EventKey:
 			//Ctrl+C breaks the loop (and exits dry) no matter what
-			if event.Key == termbox.KeyCtrlC || (focus.hasFocus() && (event.Ch == 'q' || event.Ch == 'Q')) {
+			if event.Key == termbox.KeyCtrlC || (focus.hasFocus() && isQuitKey(event)) {
 				break loop
 			} else {
 				select {
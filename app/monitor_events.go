@@ -1,31 +1,111 @@
 package app
 
-import "github.com/nsf/termbox-go"
+import (
+	"fmt"
+
+	"github.com/moncho/dry/appui"
+	drydocker "github.com/moncho/dry/docker"
+	"github.com/nsf/termbox-go"
+)
 
 type monitorScreenEventHandler struct {
 	baseEventHandler
+	//search is the search in progress, if any
+	search *monitorSearch
 }
 
-func (h *monitorScreenEventHandler) handle(event termbox.Event) {
-	ignored := false
+//monitorSearch is an incremental search of a container by its name, while
+//it is in progress key events edit the search text.
+type monitorSearch struct {
+	text string
+	//selected is the row selected before the search started, it is selected
+	//again if the search is cancelled
+	selected int
+}
 
-	switch event.Key {
-	case termbox.KeyArrowUp:
-		//To avoid the base handler handling this
-		ignored = true
-	case termbox.KeyArrowDown:
-		//To avoid the base handler handling this
-		ignored = true
-	case termbox.KeyArrowLeft:
-		//To avoid the base handler handling this
-		ignored = true
-	case termbox.KeyArrowRight:
-		//To avoid the base handler handling this
-		ignored = true
-	}
-	if !ignored {
+//edit edits the search text with the given key event, true is returned
+//if the text changed.
+func (s *monitorSearch) edit(event termbox.Event) bool {
+	switch {
+	case event.Key == termbox.KeyBackspace || event.Key == termbox.KeyBackspace2:
+		if runes := []rune(s.text); len(runes) > 0 {
+			s.text = string(runes[:len(runes)-1])
+			return true
+		}
+	case event.Key == termbox.KeySpace:
+		s.text += " "
+		return true
+	case event.Ch != 0:
+		s.text += string(event.Ch)
+		return true
+	}
+	return false
+}
+
+func (h *monitorScreenEventHandler) handle(event termbox.Event) {
+	monitor := h.dry.monitor()
+	if monitor == nil {
 		h.baseEventHandler.handle(event)
-	} else {
+		return
+	}
+	if h.search != nil {
+		h.handleSearch(event, monitor)
+		return
+	}
+	if action, ok := monitorActionFor(event); ok {
+		action(h, monitor)
+		return
+	}
+	h.baseEventHandler.handle(event)
+}
+
+//onSelectedContainer asks for confirmation and then executes the given action
+//on the container of the selected row.
+func (h *monitorScreenEventHandler) onSelectedContainer(monitor *appui.Monitor, action string, f func(id string)) {
+	container := monitor.SelectedContainer()
+	if container == nil {
 		h.setFocus(true)
+		return
+	}
+	//The monitor is not rendered while the confirmation is asked, it
+	//is rendered again afterwards.
+	if cancelMonitorWidget != nil {
+		cancelMonitorWidget()
+	}
+	name := drydocker.ContainerName(container)
+	if confirmation, err := appui.ReadLine(
+		"Container " + name + " will be " + action + ". Do you want to continue? (y/N) "); err == nil {
+		if confirmation == "Y" || confirmation == "y" {
+			f(container.ID)
+		}
+	}
+	h.screen.ClearAndFlush()
+	h.setFocus(true)
+	h.renderChan <- struct{}{}
+}
+
+//handleSearch handles the given key event while a search is in progress, Enter
+//ends the search keeping the selected row and Esc cancels it.
+func (h *monitorScreenEventHandler) handleSearch(event termbox.Event, monitor *appui.Monitor) {
+	switch event.Key {
+	case termbox.KeyEsc:
+		monitor.ClearSearch()
+		monitor.Select(h.search.selected)
+		h.search = nil
+		monitor.SetKeyHints(monitorKeyHints()...)
+		h.dry.appmessage("<white>Search cancelled</>")
+	case termbox.KeyEnter:
+		monitor.ClearSearch()
+		h.search = nil
+		monitor.SetKeyHints(monitorKeyHints()...)
+	default:
+		if h.search.edit(event) {
+			if monitor.Search(h.search.text) {
+				h.dry.appmessage(fmt.Sprintf("<white>Search: %s</>", h.search.text))
+			} else {
+				h.dry.appmessage(fmt.Sprintf("<red>Search: %s (no match)</>", h.search.text))
+			}
+		}
 	}
+	h.setFocus(true)
 }
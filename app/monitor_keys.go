@@ -0,0 +1,419 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/appui"
+	drydocker "github.com/moncho/dry/docker"
+	"github.com/moncho/dry/terminal"
+	"github.com/moncho/dry/ui/termui"
+	"github.com/nsf/termbox-go"
+)
+
+//monitorAction is an action of the monitor view, it is executed on the monitor
+//being shown.
+type monitorAction func(h *monitorScreenEventHandler, monitor *appui.Monitor)
+
+//monitorKey is a key of the monitor view, either a special key or a character
+type monitorKey struct {
+	key termbox.Key
+	ch  rune
+}
+
+//monitorActions are the actions of the monitor view, by name
+var monitorActions = map[string]monitorAction{
+	"scroll-up": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.ScrollUp()
+		h.setFocus(true)
+	},
+	"scroll-down": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.ScrollDown()
+		h.setFocus(true)
+	},
+	"watch": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		if monitor.Watching() {
+			monitor.StopWatching()
+		} else {
+			monitor.Watch()
+		}
+		h.setFocus(true)
+	},
+	"watch-previous": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.WatchPrevious()
+		h.setFocus(true)
+	},
+	"watch-next": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.WatchNext()
+		h.setFocus(true)
+	},
+	"compare": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		if monitor.Comparing() {
+			monitor.StopComparing()
+		} else if !monitor.CompareSelected() && monitor.SelectedContainer() != nil {
+			h.dry.appmessage("<white>Select the container to compare with and press x</>")
+		}
+		h.setFocus(true)
+	},
+	"none": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		h.setFocus(true)
+	},
+	"detail": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.ToggleSelectedDetail()
+		h.setFocus(true)
+	},
+	"filter": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		if cancelMonitorWidget != nil {
+			cancelMonitorWidget()
+		}
+		if filter, err := appui.ReadLine("Show containers named (leave empty to remove the filter) >>> "); err == nil {
+			h.dry.SetContainerFilter(filter)
+		}
+		h.screen.ClearAndFlush()
+		h.setFocus(true)
+		h.renderChan <- struct{}{}
+	},
+	"label-filter": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		if cancelMonitorWidget != nil {
+			cancelMonitorWidget()
+		}
+		if selector, err := appui.ReadLine("Show containers with labels, i.e. env=prod,team!=infra (leave empty to remove the filter) >>> "); err == nil {
+			if err := h.dry.SetLabelSelector(selector); err != nil {
+				h.dry.appmessage(fmt.Sprintf("<red>%s</>", err.Error()))
+			}
+		}
+		h.screen.ClearAndFlush()
+		h.setFocus(true)
+		h.renderChan <- struct{}{}
+	},
+	"note": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		container := monitor.SelectedContainer()
+		if container == nil {
+			h.setFocus(true)
+			return
+		}
+		if cancelMonitorWidget != nil {
+			cancelMonitorWidget()
+		}
+		name := drydocker.ContainerName(container)
+		if note, err := appui.ReadLine("Note about " + name + " (leave empty to remove it) >>> "); err == nil {
+			if err := monitor.SetSelectedNote(note); err != nil {
+				h.dry.appmessage(fmt.Sprintf("<red>%s</>", err.Error()))
+			}
+		}
+		h.screen.ClearAndFlush()
+		h.setFocus(true)
+		h.renderChan <- struct{}{}
+	},
+	"show-all": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		if cancelMonitorWidget != nil {
+			cancelMonitorWidget()
+		}
+		h.dry.ToggleShowAllContainers()
+		h.setFocus(true)
+		h.renderChan <- struct{}{}
+	},
+	"sort": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		//rows follow the order of the containers, the monitor is rebuilt
+		if cancelMonitorWidget != nil {
+			cancelMonitorWidget()
+		}
+		h.dry.Sort()
+		h.setFocus(true)
+		h.renderChan <- struct{}{}
+	},
+	"expand-stopped": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.ToggleStoppedExpanded()
+		h.setFocus(true)
+	},
+	"kill": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		h.onSelectedContainer(monitor, "killed", h.dry.Kill)
+	},
+	"restart": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		h.onSelectedContainer(monitor, "restarted", h.dry.RestartContainer)
+	},
+	"stop": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		h.onSelectedContainer(monitor, "stopped", h.dry.StopContainer)
+	},
+	"pin": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.TogglePinSelected()
+		h.setFocus(true)
+	},
+	"copy-id": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		if container := monitor.SelectedContainer(); container != nil {
+			if err := terminal.CopyToClipboard(container.ID); err == nil {
+				h.dry.appmessage("<white>Container ID copied to the clipboard</>")
+			} else {
+				h.dry.appmessage(fmt.Sprintf("<white>Container ID: %s</>", container.ID))
+			}
+		}
+		h.setFocus(true)
+	},
+	"dismiss": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.DismissSelected()
+		h.setFocus(true)
+	},
+	"diagnostics": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		h.dry.appmessage(fmt.Sprintf("<white>%s</>", monitor.Diagnostics()))
+		h.setFocus(true)
+	},
+	"search": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		h.search = &monitorSearch{selected: monitor.Selected()}
+		monitor.SetKeyHints(searchKeyHints...)
+		h.dry.appmessage("<white>Search: </>")
+		h.setFocus(true)
+	},
+	"io-rates": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.ToggleIODisplay()
+		h.setFocus(true)
+	},
+	"id-length": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		display := monitor.ToggleIDDisplay()
+		h.dry.appmessage(fmt.Sprintf("<white>Showing %s container IDs</>", display))
+		h.setFocus(true)
+	},
+	"group-by-image": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.ToggleGroupByImage()
+		h.setFocus(true)
+	},
+	"infra": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		if cancelMonitorWidget != nil {
+			cancelMonitorWidget()
+		}
+		display := monitor.ToggleInfraDisplay()
+		h.dry.appmessage(fmt.Sprintf("<white>Infrastructure containers %s</>", display))
+		h.setFocus(true)
+		h.renderChan <- struct{}{}
+	},
+	"group-by-pid-namespace": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.ToggleGroupByPIDNamespace()
+		h.setFocus(true)
+	},
+	"raw-stats": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		if monitor.SelectedContainer() == nil {
+			h.setFocus(true)
+			return
+		}
+		if cancelMonitorWidget != nil {
+			cancelMonitorWidget()
+		}
+		var raw *types.StatsJSON
+		if stats := monitor.SelectedStats(); stats != nil {
+			raw = stats.Stats
+		}
+		h.dry.ShowRawStats(raw)
+		h.setFocus(false)
+		go appui.Less(renderDry(h.dry), h.screen, h.keyboardQueueForView, h.closeViewChan)
+	},
+	"reset-stats": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		monitor.ResetStats()
+		h.setFocus(true)
+	},
+	"mounts": func(h *monitorScreenEventHandler, monitor *appui.Monitor) {
+		container := monitor.SelectedContainer()
+		if container == nil {
+			h.setFocus(true)
+			return
+		}
+		if cancelMonitorWidget != nil {
+			cancelMonitorWidget()
+		}
+		h.dry.ShowContainerMounts(container)
+		h.setFocus(false)
+		go appui.Less(renderDry(h.dry), h.screen, h.keyboardQueueForView, h.closeViewChan)
+	},
+}
+
+//monitorKeyBindings maps keys to the name of the monitor action they trigger,
+//keys with no action are handled as in any other view.
+var monitorKeyBindings = defaultMonitorKeyBindings()
+
+func defaultMonitorKeyBindings() map[monitorKey]string {
+	bindings := map[monitorKey]string{
+		{key: termbox.KeyArrowUp}:    "scroll-up",
+		{key: termbox.KeyArrowDown}:  "scroll-down",
+		{key: termbox.KeyArrowLeft}:  "watch-previous",
+		{key: termbox.KeyArrowRight}: "watch-next",
+		{key: termbox.KeyF1}:         "sort",
+		{key: termbox.KeyEnter}:      "detail",
+		{key: termbox.KeyF2}:         "show-all",
+		{key: termbox.KeyF3}:         "filter",
+		{key: termbox.KeyCtrlK}:      "kill",
+		{key: termbox.KeyCtrlR}:      "restart",
+		{key: termbox.KeyCtrlT}:      "stop",
+		{ch: '/'}:                    "search",
+	}
+	for ch, action := range map[rune]string{
+		'p': "pin",
+		'r': "io-rates",
+		'c': "copy-id",
+		'd': "dismiss",
+		'e': "id-length",
+		'g': "diagnostics",
+		'f': "infra",
+		'i': "group-by-image",
+		'j': "raw-stats",
+		'l': "label-filter",
+		'o': "expand-stopped",
+		'n': "note",
+		'u': "group-by-pid-namespace",
+		'z': "reset-stats",
+		'v': "mounts",
+		'w': "watch",
+		'x': "compare",
+	} {
+		bindings[monitorKey{ch: ch}] = action
+		bindings[monitorKey{ch: ch - 'a' + 'A'}] = action
+	}
+	return bindings
+}
+
+//hintedMonitorActions are the monitor actions shown as key hints, in order,
+//with the text shown for each of them
+var hintedMonitorActions = []struct{ action, hint string }{
+	{"sort", "sort"},
+	{"detail", "detail"},
+	{"filter", "filter"},
+	{"search", "search"},
+	{"expand-stopped", "expand"},
+	{"stop", "stop"},
+}
+
+//searchKeyHints are the key hints shown while a search is in progress
+var searchKeyHints = []termui.KeyHint{{Key: "enter", Action: "done"}, {Key: "esc", Action: "cancel"}}
+
+//monitorKeyHints returns the key hints of the monitor, the keys currently bound
+//to the hinted actions, followed by the key that quits dry. Actions with no key
+//bound are not hinted.
+func monitorKeyHints() []termui.KeyHint {
+	var hints []termui.KeyHint
+	for _, hinted := range hintedMonitorActions {
+		var keys []monitorKey
+		for k, action := range monitorKeyBindings {
+			if action == hinted.action {
+				keys = append(keys, k)
+			}
+		}
+		if key, ok := hintedKey(keys); ok {
+			hints = append(hints, termui.KeyHint{Key: key, Action: hinted.hint})
+		}
+	}
+	if key, ok := hintedKey(quitKeys); ok {
+		hints = append(hints, termui.KeyHint{Key: key, Action: "quit"})
+	}
+	return hints
+}
+
+//hintedKey returns the name of the key, of the given ones, shown on a hint.
+//Lower case keys are preferred, i.e. "p" over "P".
+func hintedKey(keys []monitorKey) (string, bool) {
+	if len(keys) == 0 {
+		return "", false
+	}
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.String())
+	}
+	sort.Slice(names, func(i, j int) bool {
+		li, lj := names[i] == strings.ToLower(names[i]), names[j] == strings.ToLower(names[j])
+		if li != lj {
+			return li
+		}
+		return names[i] < names[j]
+	})
+	return names[0], true
+}
+
+//eventKey returns the key of the given key event
+func eventKey(event termbox.Event) monitorKey {
+	if event.Ch != 0 {
+		return monitorKey{ch: event.Ch}
+	}
+	return monitorKey{key: event.Key}
+}
+
+//String returns the name of this key, as accepted by parseMonitorKey
+func (k monitorKey) String() string {
+	if k.ch != 0 {
+		return string(k.ch)
+	}
+	switch k.key {
+	case termbox.KeyArrowUp:
+		return "up"
+	case termbox.KeyArrowDown:
+		return "down"
+	case termbox.KeyArrowLeft:
+		return "left"
+	case termbox.KeyArrowRight:
+		return "right"
+	case termbox.KeyEnter:
+		return "enter"
+	}
+	if k.key >= termbox.KeyCtrlA && k.key <= termbox.KeyCtrlZ {
+		return "ctrl+" + string(rune('a'+k.key-termbox.KeyCtrlA))
+	}
+	if k.key <= termbox.KeyF1 && k.key >= termbox.KeyF12 {
+		return "f" + strconv.Itoa(int(termbox.KeyF1-k.key)+1)
+	}
+	return fmt.Sprintf("key %d", k.key)
+}
+
+//BindMonitorKey binds the given key to the monitor action with the given name.
+//Keys are characters, i.e. "p", or key names: up, down, left, right, enter,
+//f1 to f12 and ctrl+a to ctrl+z. An error is returned if the key or the action
+//are not known.
+func BindMonitorKey(key, action string) error {
+	if _, ok := monitorActions[action]; !ok {
+		return fmt.Errorf("Unknown monitor action: %s", action)
+	}
+	k, err := parseMonitorKey(key)
+	if err != nil {
+		return err
+	}
+	monitorKeyBindings[k] = action
+	return nil
+}
+
+//parseMonitorKey returns the monitorKey with the given name
+func parseMonitorKey(name string) (monitorKey, error) {
+	if runes := []rune(name); len(runes) == 1 {
+		return monitorKey{ch: runes[0]}, nil
+	}
+	lower := strings.ToLower(name)
+	switch lower {
+	case "up":
+		return monitorKey{key: termbox.KeyArrowUp}, nil
+	case "down":
+		return monitorKey{key: termbox.KeyArrowDown}, nil
+	case "left":
+		return monitorKey{key: termbox.KeyArrowLeft}, nil
+	case "right":
+		return monitorKey{key: termbox.KeyArrowRight}, nil
+	case "enter":
+		return monitorKey{key: termbox.KeyEnter}, nil
+	}
+	if strings.HasPrefix(lower, "ctrl+") && len(lower) == len("ctrl+")+1 {
+		if c := lower[len("ctrl+")]; c >= 'a' && c <= 'z' {
+			return monitorKey{key: termbox.KeyCtrlA + termbox.Key(c-'a')}, nil
+		}
+	}
+	if strings.HasPrefix(lower, "f") {
+		if n, err := strconv.Atoi(lower[1:]); err == nil && n >= 1 && n <= 12 {
+			return monitorKey{key: termbox.KeyF1 - termbox.Key(n-1)}, nil
+		}
+	}
+	return monitorKey{}, fmt.Errorf("Unknown key: %s", name)
+}
+
+//monitorActionFor returns the monitor action bound to the key of the given event
+func monitorActionFor(event termbox.Event) (monitorAction, bool) {
+	name, ok := monitorKeyBindings[eventKey(event)]
+	if !ok {
+		return nil, false
+	}
+	action, ok := monitorActions[name]
+	return action, ok
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moncho/dry/ui/termui"
+	"github.com/nsf/termbox-go"
+)
+
+func TestParseMonitorKey(t *testing.T) {
+	var tests = []struct {
+		name     string
+		expected monitorKey
+	}{
+		{"p", monitorKey{ch: 'p'}},
+		{"enter", monitorKey{key: termbox.KeyEnter}},
+		{"Up", monitorKey{key: termbox.KeyArrowUp}},
+		{"ctrl+k", monitorKey{key: termbox.KeyCtrlK}},
+		{"f3", monitorKey{key: termbox.KeyF3}},
+		{"f12", monitorKey{key: termbox.KeyF12}},
+	}
+	for _, test := range tests {
+		if key, err := parseMonitorKey(test.name); err != nil || key != test.expected {
+			t.Errorf("Unexpected key for %s: %v, error: %v", test.name, key, err)
+		}
+	}
+	for _, name := range []string{"", "ctrl+1", "f13", "space bar"} {
+		if _, err := parseMonitorKey(name); err == nil {
+			t.Errorf("Invalid key %q was parsed", name)
+		}
+	}
+}
+
+func TestBindMonitorKey(t *testing.T) {
+	defer func() { monitorKeyBindings = defaultMonitorKeyBindings() }()
+
+	if _, ok := monitorActionFor(termbox.Event{Ch: 'y'}); ok {
+		t.Error("Unbound key has an action")
+	}
+	if err := BindMonitorKey("y", "kill"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := monitorActionFor(termbox.Event{Ch: 'y'}); !ok {
+		t.Error("Bound key has no action")
+	}
+	if err := BindMonitorKey("y", "nope"); err == nil {
+		t.Error("A key was bound to an unknown action")
+	}
+	if _, ok := monitorActionFor(termbox.Event{Key: termbox.KeyCtrlK}); !ok {
+		t.Error("Default key has no action")
+	}
+}
+
+func TestMonitorSearchEdit(t *testing.T) {
+	s := &monitorSearch{}
+	for _, event := range []termbox.Event{{Ch: 'w'}, {Ch: 'e'}, {Key: termbox.KeySpace}, {Ch: 'x'}, {Key: termbox.KeyBackspace2}} {
+		if !s.edit(event) {
+			t.Errorf("Search text did not change on %+v", event)
+		}
+	}
+	if s.text != "we " {
+		t.Errorf("Unexpected search text: %q", s.text)
+	}
+	if s.edit(termbox.Event{Key: termbox.KeyArrowUp}) {
+		t.Error("Search text changed on a key with no text")
+	}
+	s.text = ""
+	if s.edit(termbox.Event{Key: termbox.KeyBackspace}) {
+		t.Error("Empty search text changed on backspace")
+	}
+}
+
+func TestMonitorKeyString(t *testing.T) {
+	for _, name := range []string{"p", "enter", "up", "ctrl+k", "f3", "f12"} {
+		key, err := parseMonitorKey(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.String() != name {
+			t.Errorf("Unexpected name of key %s: %s", name, key)
+		}
+	}
+}
+
+func TestMonitorKeyHints(t *testing.T) {
+	defer func() { monitorKeyBindings = defaultMonitorKeyBindings() }()
+
+	expected := "f1: sort, enter: detail, f3: filter, /: search, o: expand, ctrl+t: stop, q: quit"
+	if hints := hintsText(monitorKeyHints()); hints != expected {
+		t.Errorf("Unexpected key hints. Expected: %s, got: %s", expected, hints)
+	}
+	if err := BindMonitorKey("s", "stop"); err != nil {
+		t.Fatal(err)
+	}
+	delete(monitorKeyBindings, monitorKey{ch: '/'})
+	expected = "f1: sort, enter: detail, f3: filter, o: expand, ctrl+t: stop, q: quit"
+	if hints := hintsText(monitorKeyHints()); hints != expected {
+		t.Errorf("Key hints do not follow the key bindings. Expected: %s, got: %s", expected, hints)
+	}
+
+	defer func(keys []monitorKey) { quitKeys = keys }(quitKeys)
+	quitKeys = []monitorKey{{key: termbox.KeyCtrlQ}}
+	if hints := monitorKeyHints(); hints[len(hints)-1].Key != "ctrl+q" {
+		t.Errorf("Quit hint does not follow the quit keys: %s", hintsText(hints))
+	}
+}
+
+func TestIsQuitKey(t *testing.T) {
+	if !isQuitKey(termbox.Event{Ch: 'q'}) || !isQuitKey(termbox.Event{Ch: 'Q'}) {
+		t.Error("q does not quit")
+	}
+	if isQuitKey(termbox.Event{Ch: 'w'}) || isQuitKey(termbox.Event{Key: termbox.KeyEnter}) {
+		t.Error("Unexpected quit key")
+	}
+}
+
+//hintsText returns the given key hints as text
+func hintsText(hints []termui.KeyHint) string {
+	var text []string
+	for _, hint := range hints {
+		text = append(text, hint.Key+": "+hint.Action)
+	}
+	return strings.Join(text, ", ")
+}
@@ -23,11 +23,13 @@ const (
 	Networks
 	EventsMode
 	HelpMode
+	ContainerMountsMode
 	ImageHistoryMode
 	InfoMode
 	InspectImageMode
 	InspectNetworkMode
 	InspectMode
+	RawStatsMode
 )
 
 const (
@@ -124,12 +126,49 @@ func Render(d *Dry, screen *ui.Screen, statusBar *ui.StatusBar) {
 		}
 	case Monitor:
 		{
-			monitor := appui.NewMonitor(screen, d.dockerDaemon, viewStartingLine)
+			options := *appui.DefaultStatsRowOptions
+			options.ShowStopped = d.state.showingAllContainers
+			options.GroupStopped = d.state.showingAllContainers
+			previous := d.monitor()
+			hints := monitorKeyHints()
+			if previous != nil {
+				options.StoppedExpanded = previous.StoppedExpanded()
+				options.IODisplay = previous.IODisplay()
+				options.InfraDisplay = previous.InfraDisplay()
+				options.IDDisplay = previous.IDDisplay()
+				options.GroupByImage = previous.GroupByImage()
+				options.GroupByPIDNamespace = previous.GroupByPIDNamespace()
+				//hints follow the mode of the monitor, i.e. while searching
+				if previousHints := previous.KeyHints(); previousHints != nil {
+					hints = previousHints
+				}
+			}
+			monitor := appui.NewMultiHostMonitorWithOptions(screen, d.hostsToMonitor(), d.state.filterPattern, &options, viewStartingLine)
+			if previous != nil {
+				monitor.SetPins(previous.Pins())
+				//the selected container might be on another position on the new monitor
+				if selected := previous.SelectedContainer(); selected == nil || !monitor.SelectContainer(selected.ID) {
+					monitor.Select(previous.Selected())
+				}
+			}
+			if d.state.labelSelector != nil {
+				monitor.SetLabelSelector(d.state.labelSelector)
+			}
+			monitor.SetKeyHints(hints...)
+			d.setMonitor(monitor)
 			ctx, cancel := context.WithCancel(context.Background())
 			monitor.RenderLoop(ctx)
 			keymap = monitorMapping
 			what = "Containers"
 			count = monitor.ContainerCount()
+			if d.state.filterPattern != "" {
+				titleInfo = titleInfo + fmt.Sprintf(
+					"<b><blue> | Container name filter: </><yellow>%s</></> ", d.state.filterPattern)
+			}
+			if d.state.labelSelector != nil {
+				titleInfo = titleInfo + fmt.Sprintf(
+					"<b><blue> | Label selector: </><yellow>%s</></> ", d.state.labelSelector)
+			}
 			cancelMonitorWidget = cancel
 
 		}
@@ -159,6 +198,10 @@ func renderDry(d *Dry) ui.Renderer {
 	switch d.viewMode() {
 	case EventsMode:
 		output = appui.NewDockerEventsRenderer(d.dockerDaemon.EventLog().Events())
+	case ContainerMountsMode:
+		output = appui.NewContainerMountsRenderer(d.mountsContainer)
+	case RawStatsMode:
+		output = appui.NewRawStatsRenderer(d.rawStats)
 	case ImageHistoryMode:
 		output = appui.NewDockerImageHistoryRenderer(d.imageHistory)
 	case InspectMode:
@@ -0,0 +1,218 @@
+package appui
+
+import (
+	"fmt"
+	"time"
+
+	units "github.com/docker/go-units"
+	termui "github.com/gizak/termui"
+	drytermui "github.com/moncho/dry/ui/termui"
+)
+
+//AggregateStatsRow is a Grid row showing the totals of the containers shown on
+//a monitor, including the network and block IO rates of the whole host.
+type AggregateStatsRow struct {
+	rows []*ContainerStatsRow
+	//title is shown followed by the number of containers counted
+	title string
+	//label, if set, replaces the title with the number of containers counted
+	label      string
+	Title      *drytermui.ParColumn
+	CPU        *drytermui.ParColumn
+	Memory     *drytermui.ParColumn
+	Net        *drytermui.ParColumn
+	Block      *drytermui.ParColumn
+	X, Y       int
+	Width      int
+	Height     int
+	columns    []*drytermui.ParColumn
+	visible    []bool
+	separators []int
+	//capacity, if known, is used to show the share of the host used by the rows
+	capacity hostCapacity
+}
+
+//hostCapacity is the number of CPUs and the memory, in bytes, of the hosts
+//whose containers are counted on a row
+type hostCapacity struct {
+	cpus   int
+	memory int64
+}
+
+//hostShareGaugeWidth is the width of the gauges showing the share of the host used
+const hostShareGaugeWidth = 5
+
+//aggregateColumnPriorities are the priorities of the aggregate row columns
+var aggregateColumnPriorities = []int{5, 4, 3, 2, 1}
+
+//NewAggregateStatsRow creates a row showing the totals of the given rows
+func NewAggregateStatsRow(rows []*ContainerStatsRow) *AggregateStatsRow {
+	row := &AggregateStatsRow{
+		rows:   rows,
+		title:  "TOTAL",
+		Title:  drytermui.NewThemedParColumn(DryTheme, "TOTAL"),
+		CPU:    drytermui.NewThemedParColumn(DryTheme, "-"),
+		Memory: drytermui.NewThemedParColumn(DryTheme, "-"),
+		Net:    drytermui.NewThemedParColumn(DryTheme, "-"),
+		Block:  drytermui.NewThemedParColumn(DryTheme, "-"),
+		Height: 1,
+	}
+	row.columns = []*drytermui.ParColumn{row.Title, row.CPU, row.Memory, row.Net, row.Block}
+	for _, c := range row.columns {
+		c.Height = 1
+	}
+	return row
+}
+
+//ioFormat returns the format of the network and block IO values of the rows counted
+func (row *AggregateStatsRow) ioFormat() ByteFormat {
+	if len(row.rows) == 0 {
+		return nil
+	}
+	return row.rows[0].ioFormat
+}
+
+//separator returns the separator of the columns of the rows counted
+func (row *AggregateStatsRow) separator() columnSeparator {
+	if len(row.rows) == 0 {
+		return 0
+	}
+	return row.rows[0].separator
+}
+
+//newRemainderRow creates a row showing the totals of the given rows, which
+//are not shown on a grid with a maximum number of rows.
+func newRemainderRow(rows []*ContainerStatsRow) *AggregateStatsRow {
+	row := NewAggregateStatsRow(rows)
+	row.label = fmt.Sprintf("+%d more", len(rows))
+	return row
+}
+
+//newImageRow creates a row showing the totals of the given rows, which are
+//the containers of the given image.
+func newImageRow(image string, rows []*ContainerStatsRow, capacity hostCapacity) *AggregateStatsRow {
+	row := NewAggregateStatsRow(rows)
+	row.title = image
+	row.capacity = capacity
+	return row
+}
+
+//imageRows groups the given rows by the image of their containers, it returns
+//a row with the totals of each image, following the order of the given rows.
+//If the given host capacity is known, rows show the share of the host used by each image.
+func imageRows(rows []*ContainerStatsRow, capacity hostCapacity) []*AggregateStatsRow {
+	var images []string
+	byImage := make(map[string][]*ContainerStatsRow)
+	for _, row := range rows {
+		image := row.container.Image
+		if _, ok := byImage[image]; !ok {
+			images = append(images, image)
+		}
+		byImage[image] = append(byImage[image], row)
+	}
+	var imageRows []*AggregateStatsRow
+	for _, image := range images {
+		imageRows = append(imageRows, newImageRow(image, byImage[image], capacity))
+	}
+	return imageRows
+}
+
+//GetHeight returns this AggregateStatsRow height
+func (row *AggregateStatsRow) GetHeight() int {
+	return row.Height
+}
+
+//SetX sets the x position of this AggregateStatsRow
+func (row *AggregateStatsRow) SetX(x int) {
+	row.X = x
+}
+
+//SetY sets the y position of this AggregateStatsRow
+func (row *AggregateStatsRow) SetY(y int) {
+	for _, col := range row.columns {
+		col.SetY(y)
+	}
+	row.Y = y
+}
+
+//SetWidth sets the width of this AggregateStatsRow
+func (row *AggregateStatsRow) SetWidth(width int) {
+	row.Width = width
+	x := row.X
+	separator := row.separator()
+	cw, visible := layoutColumns(width, aggregateColumnPriorities, separator.gap())
+	row.visible = visible
+	row.separators = nil
+	for i, col := range row.columns {
+		if !visible[i] {
+			continue
+		}
+		if x > row.X && separator != 0 {
+			row.separators = append(row.separators, separator.position(x))
+		}
+		col.SetX(x)
+		col.SetWidth(cw)
+		x += cw + separator.gap()
+	}
+}
+
+//Buffer returns this AggregateStatsRow data as a termui.Buffer
+func (row *AggregateStatsRow) Buffer() termui.Buffer {
+	row.update(time.Now())
+	buf := drytermui.NewSizedBuffer(row.Width, row.Height)
+	for i, col := range row.columns {
+		if row.visible != nil && !row.visible[i] {
+			continue
+		}
+		buf.Merge(col.Buffer())
+	}
+	row.separator().render(&buf, row.Y, row.separators)
+	return buf
+}
+
+//update sums the last sample of the rows with recent samples, rows of containers
+//that are gone are not counted.
+func (row *AggregateStatsRow) update(now time.Time) {
+	var cpu, mem float64
+	var rates IORates
+	running := 0
+	for _, r := range row.rows {
+		stats, rowRates, ok := r.sample(now)
+		if !ok {
+			continue
+		}
+		running++
+		cpu += stats.CPUPercentage
+		mem += stats.Memory
+		rates.add(rowRates)
+	}
+	if row.label != "" {
+		row.Title.Text = row.label
+	} else {
+		row.Title.Text = fmt.Sprintf("%s (%d)", row.title, running)
+	}
+	row.CPU.Text = fmt.Sprintf("%.2f%%", cpu)
+	row.Memory.Text = units.BytesSize(mem)
+	if row.capacity.cpus > 0 {
+		row.CPU.Text += " " + hostShareText(cpu, float64(row.capacity.cpus)*DefaultCPUGaugeScale)
+	}
+	if row.capacity.memory > 0 {
+		row.Memory.Text += " " + hostShareText(mem, float64(row.capacity.memory))
+	}
+	format := row.ioFormat()
+	row.Net.Text = rateText(rates.NetworkRx, rates.NetworkTx, format)
+	row.Block.Text = rateText(rates.BlockRead, rates.BlockWrite, format)
+}
+
+//hostShareText shows, as a gauge followed by a percentage, the share of the given
+//capacity that is used
+func hostShareText(usage, capacity float64) string {
+	share := 0
+	if capacity > 0 {
+		share = int(usage * 100 / capacity)
+	}
+	gauge := drytermui.NewThemedMicroGauge(DryTheme)
+	gauge.Width = hostShareGaugeWidth
+	gauge.Percent = share
+	return fmt.Sprintf("%s %d%%", gauge.Text(), share)
+}
@@ -0,0 +1,99 @@
+package appui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestAggregateStatsRow(t *testing.T) {
+	now := time.Now()
+	running := &ContainerStatsRow{
+		latest:      &docker.Stats{CPUPercentage: 10, Memory: 1024},
+		lastUpdated: now,
+		rates:       IORates{NetworkRx: 1024, BlockWrite: 2048},
+	}
+	another := &ContainerStatsRow{
+		latest:      &docker.Stats{CPUPercentage: 5, Memory: 1024},
+		lastUpdated: now,
+		rates:       IORates{NetworkRx: 1024},
+	}
+	gone := &ContainerStatsRow{
+		latest:      &docker.Stats{CPUPercentage: 50, Memory: 1024},
+		lastUpdated: now.Add(-time.Minute),
+		rates:       IORates{NetworkRx: 1024},
+	}
+	row := NewAggregateStatsRow([]*ContainerStatsRow{running, another, gone})
+	row.update(now)
+
+	if row.Title.Text != "TOTAL (2)" {
+		t.Errorf("Unexpected title. Expected: %s, got: %s", "TOTAL (2)", row.Title.Text)
+	}
+	if row.CPU.Text != "15.00%" {
+		t.Errorf("Unexpected total CPU. Expected: %s, got: %s", "15.00%", row.CPU.Text)
+	}
+	if row.Memory.Text != "2 KiB" {
+		t.Errorf("Unexpected total memory. Expected: %s, got: %s", "2 KiB", row.Memory.Text)
+	}
+	if row.Net.Text != "2 KiB/s / 0 B/s" {
+		t.Errorf("Unexpected total network rates. Expected: %s, got: %s", "2 KiB/s / 0 B/s", row.Net.Text)
+	}
+	if row.Block.Text != "0 B/s / 2 KiB/s" {
+		t.Errorf("Unexpected total block IO rates. Expected: %s, got: %s", "0 B/s / 2 KiB/s", row.Block.Text)
+	}
+}
+
+func TestImageRows(t *testing.T) {
+	now := time.Now()
+	newRow := func(image string, cpu float64) *ContainerStatsRow {
+		return &ContainerStatsRow{
+			container:   &types.Container{Image: image},
+			latest:      &docker.Stats{CPUPercentage: cpu, Memory: 1024},
+			lastUpdated: now,
+		}
+	}
+	rows := imageRows([]*ContainerStatsRow{
+		newRow("postgres:15", 10), newRow("nginx", 1), newRow("postgres:15", 20)}, hostCapacity{})
+
+	if len(rows) != 2 {
+		t.Fatalf("Unexpected number of image rows: %d", len(rows))
+	}
+	rows[0].update(now)
+	if rows[0].Title.Text != "postgres:15 (2)" || rows[0].CPU.Text != "30.00%" || rows[0].Memory.Text != "2 KiB" {
+		t.Errorf("Unexpected totals of the first image: %s, %s, %s", rows[0].Title.Text, rows[0].CPU.Text, rows[0].Memory.Text)
+	}
+	rows[1].update(now)
+	if rows[1].Title.Text != "nginx (1)" {
+		t.Errorf("Unexpected title of the second image: %s", rows[1].Title.Text)
+	}
+}
+
+func TestImageRowsHostShare(t *testing.T) {
+	now := time.Now()
+	capacity := hostCapacity{cpus: 2, memory: 4096}
+	running := &ContainerStatsRow{
+		container:   &types.Container{Image: "postgres:15"},
+		latest:      &docker.Stats{CPUPercentage: 100, Memory: 1024},
+		lastUpdated: now,
+	}
+	gone := &ContainerStatsRow{
+		container:   &types.Container{Image: "nginx"},
+		latest:      &docker.Stats{CPUPercentage: 100, Memory: 1024},
+		lastUpdated: now.Add(-time.Minute),
+	}
+	rows := imageRows([]*ContainerStatsRow{running, gone}, capacity)
+
+	rows[0].update(now)
+	if rows[0].CPU.Text != "100.00% ██▄   50%" {
+		t.Errorf("Unexpected CPU share of the first image: %q", rows[0].CPU.Text)
+	}
+	if rows[0].Memory.Text != "1 KiB █▂    25%" {
+		t.Errorf("Unexpected memory share of the first image: %q", rows[0].Memory.Text)
+	}
+	rows[1].update(now)
+	if rows[1].CPU.Text != "0.00%       0%" {
+		t.Errorf("Unexpected CPU share of an image with no running containers: %q", rows[1].CPU.Text)
+	}
+}
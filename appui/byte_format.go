@@ -0,0 +1,32 @@
+package appui
+
+import (
+	"fmt"
+
+	units "github.com/docker/go-units"
+)
+
+//ByteFormat formats a number of bytes, or bytes per second, to be shown on
+//the network and block IO columns. A nil ByteFormat uses units.BytesSize.
+type ByteFormat func(float64) string
+
+//decimalAbbrs are the abbreviations of the decimal units used by
+//units.HumanSize, the vendored go-units does not export them.
+var decimalAbbrs = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
+
+//HumanSizeWithPrecision returns a ByteFormat that shows sizes on decimal units
+//with the given number of significant digits, as units.HumanSizeWithPrecision
+//does, so small values are not rounded to whole units.
+func HumanSizeWithPrecision(digits int) ByteFormat {
+	format := fmt.Sprintf("%%.%dg %%s", digits)
+	return func(size float64) string {
+		return units.CustomSize(format, size, 1000.0, decimalAbbrs)
+	}
+}
+
+func (f ByteFormat) size(v float64) string {
+	if f == nil {
+		return units.BytesSize(v)
+	}
+	return f(v)
+}
@@ -0,0 +1,22 @@
+package appui
+
+import "testing"
+
+func TestByteFormat(t *testing.T) {
+	tests := []struct {
+		format   ByteFormat
+		value    float64
+		expected string
+	}{
+		{nil, 0, "0 B"},
+		{nil, 1500, "1.465 KiB"},
+		{HumanSizeWithPrecision(3), 120, "120 B"},
+		{HumanSizeWithPrecision(3), 1234, "1.23 kB"},
+		{HumanSizeWithPrecision(4), 987654, "987.7 kB"},
+	}
+	for i, test := range tests {
+		if size := test.format.size(test.value); size != test.expected {
+			t.Errorf("Test %d: unexpected size %s, expected %s", i, size, test.expected)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package appui
+
+import (
+	termui "github.com/gizak/termui"
+)
+
+//columnSeparator is the character shown between the columns of the monitor
+//rows, no separator is shown if it is zero.
+type columnSeparator rune
+
+//gap returns the number of cells between two columns, a separator is
+//surrounded by columnSpacing on each side.
+func (s columnSeparator) gap() int {
+	if s == 0 {
+		return columnSpacing
+	}
+	return 2*columnSpacing + 1
+}
+
+//position returns where the separator is shown for a column starting at x
+func (s columnSeparator) position(x int) int {
+	return x - columnSpacing - 1
+}
+
+//render shows the separator at the given positions of the given buffer
+func (s columnSeparator) render(buf *termui.Buffer, y int, positions []int) {
+	if s == 0 {
+		return
+	}
+	for _, x := range positions {
+		buf.Set(x, y, termui.Cell{
+			Ch: rune(s),
+			Fg: termui.Attribute(DryTheme.Footer),
+			Bg: termui.Attribute(DryTheme.Bg)})
+	}
+}
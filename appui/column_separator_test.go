@@ -0,0 +1,47 @@
+package appui
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestColumnSeparator(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	options := &StatsRowOptions{ColumnSeparator: '|'}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, options)
+	header := options.header()
+	row.SetWidth(100)
+	header.SetWidth(100)
+
+	if len(row.separators) != len(row.columns)-1 {
+		t.Fatalf("Unexpected number of separators. Expected: %d, got: %d", len(row.columns)-1, len(row.separators))
+	}
+	if len(header.separators) != len(row.separators) {
+		t.Fatalf("Header and row separators do not match: %v, %v", header.separators, row.separators)
+	}
+	for i, x := range row.separators {
+		if header.separators[i] != x {
+			t.Errorf("Header and row separators are not aligned: %v, %v", header.separators, row.separators)
+			break
+		}
+	}
+	buf := row.Buffer()
+	for _, x := range row.separators {
+		if c := buf.At(x, 0); c.Ch != '|' {
+			t.Errorf("No separator shown at %d, got: %q", x, c.Ch)
+		}
+	}
+	//Columns do not overlap with the separators
+	w, _ := layoutColumns(100, row.priorities, columnSeparator('|').gap())
+	if last := row.separators[len(row.separators)-1] + columnSpacing + 1 + w; last > 100 {
+		t.Errorf("Columns do not fit with separators, the last column ends at %d", last)
+	}
+
+	plain := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{})
+	plain.SetWidth(100)
+	if len(plain.separators) != 0 {
+		t.Errorf("Separators are shown by default: %v", plain.separators)
+	}
+}
@@ -0,0 +1,59 @@
+package appui
+
+import (
+	termui "github.com/gizak/termui"
+	drytermui "github.com/moncho/dry/ui/termui"
+)
+
+//columnState is the state of the value shown on a column of a row
+type columnState int
+
+const (
+	//columnPending means that the source of the column value is still loading
+	columnPending columnState = iota
+	//columnReady means that the column shows its value
+	columnReady
+	//columnNotApplicable means that there is no value for the column
+	columnNotApplicable
+)
+
+const (
+	pendingText       = "…"
+	notApplicableText = "-"
+)
+
+//setColumnState sets the state of the given column, pending and not applicable
+//columns show a placeholder instead of a value.
+func (row *ContainerStatsRow) setColumnState(column termui.Bufferer, state columnState) {
+	row.states[column] = state
+	var text string
+	switch state {
+	case columnPending:
+		text = pendingText
+	case columnNotApplicable:
+		text = notApplicableText
+	default:
+		return
+	}
+	switch c := column.(type) {
+	case *drytermui.ParColumn:
+		c.Text = text
+	case *drytermui.GaugeColumn:
+		c.Label = text
+	}
+}
+
+//columnState returns the state of the given column
+func (row *ContainerStatsRow) columnState(column termui.Bufferer) columnState {
+	return row.states[column]
+}
+
+//statsColumns returns the columns whose value comes from the stats samples
+func (row *ContainerStatsRow) statsColumns() []termui.Bufferer {
+	columns := []termui.Bufferer{
+		row.CPU, row.CPUAverage, row.CPUSplit, row.Memory, row.HostMemory, row.Net, row.Block, row.IOPS, row.Pids, row.FDs, row.Updated}
+	for _, d := range row.derivedColumns {
+		columns = append(columns, row.Derived[d.Name])
+	}
+	return columns
+}
@@ -0,0 +1,32 @@
+package appui
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestColumnStates(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: make(chan *docker.Stats)},
+		&StatsRowOptions{ShowOpenFDs: true})
+
+	if row.Net.Text != pendingText || row.CPU.Label != pendingText || row.columnState(row.Pids) != columnPending {
+		t.Errorf("Columns are not pending before the first sample, net: %s, cpu: %s", row.Net.Text, row.CPU.Label)
+	}
+	row.apply(&docker.Stats{PidsCurrent: 2, OpenFDs: -1})
+	if row.columnState(row.Pids) != columnReady || row.Pids.Text != "2" {
+		t.Errorf("Pids column is not ready after a sample, pids: %s", row.Pids.Text)
+	}
+	if row.columnState(row.FDs) != columnNotApplicable || row.FDs.Text != notApplicableText {
+		t.Errorf("FDs column is not marked as not applicable, fds: %s", row.FDs.Text)
+	}
+
+	stopped := NewContainerStatsRow(&docker.StatsChannel{
+		Container: &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited"}})
+	if stopped.Net.Text != notApplicableText || stopped.columnState(stopped.CPU) != columnNotApplicable {
+		t.Errorf("Columns of a stopped container are not marked as not applicable, net: %s", stopped.Net.Text)
+	}
+}
@@ -27,7 +27,7 @@ func NewContainerInfo(container *types.Container) (string, int) {
 	}
 	lines := len(container.Command) / maxWidth
 	data := [][]string{
-		[]string{ui.Blue("Container Name:"), ui.Yellow(container.Names[0]), ui.Blue("ID:"), ui.Yellow(docker.TruncateID(container.ID)), ui.Blue("Status:"), status},
+		[]string{ui.Blue("Container Name:"), ui.Yellow(docker.ContainerName(container)), ui.Blue("ID:"), ui.Yellow(docker.TruncateID(container.ID)), ui.Blue("Status:"), status},
 		[]string{ui.Blue("Image:"), ui.Yellow(container.Image), ui.Blue("Created:"), ui.Yellow(docker.DurationForHumans(container.Created) + " ago")},
 		[]string{ui.Blue("Command:"), ui.Yellow(container.Command)},
 		[]string{ui.Blue("Port mapping:"), ui.Yellow(docker.DisplayablePorts(container.Ports))},
@@ -0,0 +1,151 @@
+package appui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	units "github.com/docker/go-units"
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/ui"
+)
+
+//comparedMetric is a metric shown on a comparison of two containers
+type comparedMetric struct {
+	name   string
+	value  func(s *docker.Stats, rates IORates) float64
+	format func(v float64) string
+}
+
+func percentage(v float64) string { return fmt.Sprintf("%.2f%%", v) }
+func bytesSize(v float64) string  { return units.BytesSize(v) }
+func byteRate(v float64) string   { return units.BytesSize(v) + "/s" }
+
+//comparedMetrics are the metrics shown on a comparison, in order
+var comparedMetrics = []comparedMetric{
+	{"CPU", func(s *docker.Stats, r IORates) float64 { return s.CPUPercentage }, percentage},
+	{"MEM", func(s *docker.Stats, r IORates) float64 { return s.Memory }, bytesSize},
+	{"MEM %", func(s *docker.Stats, r IORates) float64 { return s.MemoryPercentage }, percentage},
+	{"NET RX", func(s *docker.Stats, r IORates) float64 { return r.NetworkRx }, byteRate},
+	{"NET TX", func(s *docker.Stats, r IORates) float64 { return r.NetworkTx }, byteRate},
+	{"BLOCK READ", func(s *docker.Stats, r IORates) float64 { return r.BlockRead }, byteRate},
+	{"BLOCK WRITE", func(s *docker.Stats, r IORates) float64 { return r.BlockWrite }, byteRate},
+	{"PIDS", func(s *docker.Stats, r IORates) float64 { return float64(s.PidsCurrent) },
+		func(v float64) string { return strconv.Itoa(int(v)) }},
+}
+
+//containerComparison shows the stats of two containers side by side, with the
+//difference of the second container with respect to the first one, the baseline.
+type containerComparison struct {
+	baseline  *ContainerStatsRow
+	candidate *ContainerStatsRow
+	X, Y      int
+	Width     int
+	Height    int
+}
+
+func newContainerComparison(baseline, candidate *ContainerStatsRow, x, y, width, height int) *containerComparison {
+	return &containerComparison{
+		baseline:  baseline,
+		candidate: candidate,
+		X:         x,
+		Y:         y,
+		Width:     width,
+		Height:    height,
+	}
+}
+
+//lines returns the lines of the comparison, a header followed by a line per
+//metric. Each line has the metric name, the value of both containers and the
+//difference, in percentage, of the candidate with respect to the baseline.
+func (c *containerComparison) lines(now time.Time) [][]string {
+	lines := [][]string{{"", containerName(c.baseline), containerName(c.candidate), "DIFF"}}
+	base, baseRates, baseOk := c.baseline.sample(now)
+	cand, candRates, candOk := c.candidate.sample(now)
+	for _, m := range comparedMetrics {
+		line := []string{m.name, "-", "-", "-"}
+		if baseOk {
+			line[1] = m.format(m.value(base, baseRates))
+		}
+		if candOk {
+			line[2] = m.format(m.value(cand, candRates))
+		}
+		if baseOk && candOk {
+			if diff, ok := percentDiff(m.value(base, baseRates), m.value(cand, candRates)); ok {
+				line[3] = fmt.Sprintf("%+.1f%%", diff)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+//Buffer returns the content of this comparison as a termui.Buffer
+func (c *containerComparison) Buffer() termui.Buffer {
+	buf := termui.NewBuffer()
+	columnWidth := c.Width / 4
+	for i, line := range c.lines(time.Now()) {
+		if i >= c.Height {
+			break
+		}
+		for j, text := range line {
+			p := ui.NewPar(text, DryTheme)
+			p.Border = false
+			if i == 0 || j == 0 {
+				p.TextFgColor = termui.Attribute(DryTheme.Key)
+			}
+			p.X, p.Y, p.Width, p.Height = c.X+j*columnWidth, c.Y+i, columnWidth, 1
+			buf.Merge(p.Buffer())
+		}
+	}
+	return buf
+}
+
+//percentDiff returns the difference between the given values as a percentage
+//of the first one, false is returned if the first value is zero.
+func percentDiff(base, other float64) (float64, bool) {
+	if base == 0 {
+		return 0, false
+	}
+	return (other - base) / base * 100, true
+}
+
+//containerName returns the name of the container of the given row
+func containerName(row *ContainerStatsRow) string {
+	return docker.NewContainerFormatter(row.container, true).Names()
+}
+
+//CompareSelected marks the container on the selected row to be compared, once
+//two containers are marked they are shown side by side instead of the rows.
+//True is returned once the comparison is shown.
+func (m *Monitor) CompareSelected() bool {
+	m.Lock()
+	defer m.Unlock()
+	if m.selectedRow >= len(m.rows) {
+		return false
+	}
+	row := m.rows[m.selectedRow]
+	if m.compared == nil || m.compared == row {
+		m.compared = row
+		return false
+	}
+	m.comparison = newContainerComparison(m.compared, row, m.Grid.X, m.Grid.Y, m.Grid.Width, m.Grid.Height)
+	m.compared = nil
+	return true
+}
+
+//StopComparing shows the rows again after two containers were compared
+func (m *Monitor) StopComparing() {
+	m.Lock()
+	defer m.Unlock()
+	m.comparison = nil
+	m.compared = nil
+}
+
+//Comparing returns true if two containers are being compared
+func (m *Monitor) Comparing() bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.comparison != nil
+}
@@ -0,0 +1,49 @@
+package appui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moncho/dry/docker"
+)
+
+func TestPercentDiff(t *testing.T) {
+	if diff, ok := percentDiff(50, 75); !ok || diff != 50 {
+		t.Errorf("Unexpected difference: %f", diff)
+	}
+	if diff, ok := percentDiff(50, 25); !ok || diff != -50 {
+		t.Errorf("Unexpected difference: %f", diff)
+	}
+	if _, ok := percentDiff(0, 25); ok {
+		t.Error("A difference with respect to zero was calculated")
+	}
+}
+
+func TestMonitorCompare(t *testing.T) {
+	m := newTestMonitor("canary", "baseline", "db")
+	m.Select(1)
+	if m.CompareSelected() || m.Comparing() {
+		t.Fatal("Comparison started with a single container")
+	}
+	m.Select(0)
+	if !m.CompareSelected() || !m.Comparing() {
+		t.Fatal("Comparison did not start")
+	}
+	now := time.Now()
+	m.comparison.baseline.update(&docker.Stats{CPUPercentage: 20, PidsCurrent: 4}, now)
+	m.comparison.candidate.update(&docker.Stats{CPUPercentage: 30, PidsCurrent: 4}, now)
+	lines := m.comparison.lines(now)
+	if header := lines[0]; header[1] != "baseline" || header[2] != "canary" {
+		t.Errorf("Unexpected comparison header: %v", header)
+	}
+	if cpu := lines[1]; cpu[1] != "20.00%" || cpu[2] != "30.00%" || cpu[3] != "+50.0%" {
+		t.Errorf("Unexpected CPU comparison: %v", cpu)
+	}
+	if net := lines[4]; net[3] != "-" {
+		t.Errorf("Unexpected comparison of zero values: %v", net)
+	}
+	m.StopComparing()
+	if m.Comparing() {
+		t.Error("Containers are still compared")
+	}
+}
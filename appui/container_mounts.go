@@ -0,0 +1,39 @@
+package appui
+
+import (
+	"bytes"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/ui"
+	"github.com/olekukonko/tablewriter"
+)
+
+//containerMountsRenderer renders the mounts of a container, with no truncation
+type containerMountsRenderer struct {
+	container *types.Container
+}
+
+//NewContainerMountsRenderer creates a renderer for the mounts of the given container
+func NewContainerMountsRenderer(container *types.Container) ui.Renderer {
+	return &containerMountsRenderer{container: container}
+}
+
+//Render the mounts of a container
+func (r *containerMountsRenderer) Render() string {
+	if r.container == nil || len(r.container.Mounts) == 0 {
+		return ui.White("The container has no mounts")
+	}
+	buffer := new(bytes.Buffer)
+
+	table := tablewriter.NewWriter(buffer)
+	table.SetHeader([]string{"TYPE", "NAME", "SOURCE", "DESTINATION", "MODE"})
+	table.SetBorder(false)
+	table.SetColumnSeparator(" ")
+	table.SetAutoWrapText(false)
+
+	for _, m := range r.container.Mounts {
+		table.Append([]string{string(m.Type), m.Name, m.Source, m.Destination, mountMode(m)})
+	}
+	table.Render()
+	return ui.White(buffer.String())
+}
@@ -0,0 +1,181 @@
+package appui
+
+import (
+	"fmt"
+
+	units "github.com/docker/go-units"
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/ui"
+)
+
+//watchHistorySize is the number of CPU samples shown on the sparkline of a watch
+const watchHistorySize = 120
+
+//containerWatch is a full screen view of a single container, it shows
+//the stats received by the container row with large gauges, a sparkline
+//of the CPU usage, the network IO of each interface and the process list.
+type containerWatch struct {
+	row        *ContainerStatsRow
+	cpuHistory []int
+	last       *docker.Stats
+	cpuColor   gaugeColor
+	memColor   gaugeColor
+	X, Y       int
+	Width      int
+	Height     int
+}
+
+func newContainerWatch(row *ContainerStatsRow, x, y, width, height int) *containerWatch {
+	return &containerWatch{
+		row:    row,
+		X:      x,
+		Y:      y,
+		Width:  width,
+		Height: height,
+	}
+}
+
+//update adds the CPU usage of the given sample to the history if the sample
+//has not been seen yet
+func (w *containerWatch) update(stats *docker.Stats) {
+	if stats == nil || stats == w.last {
+		return
+	}
+	w.last = stats
+	//Sparklines take integers, CPU usage is kept in hundredths of percentage points
+	w.cpuHistory = append(w.cpuHistory, int(stats.CPUPercentage*100))
+	if len(w.cpuHistory) > watchHistorySize {
+		w.cpuHistory = w.cpuHistory[len(w.cpuHistory)-watchHistorySize:]
+	}
+}
+
+//Buffer returns the content of this watch as a termui.Buffer
+func (w *containerWatch) Buffer() termui.Buffer {
+	stats := w.row.Latest()
+	w.update(stats)
+	buf := termui.NewBuffer()
+	y := w.Y
+
+	cf := docker.NewContainerFormatter(w.row.container, true)
+	title := ui.NewPar(fmt.Sprintf("%s  %s  %s", cf.Names(), cf.ShortImage(), cf.ID()), DryTheme)
+	title.Border = false
+	title.X, title.Y, title.Width, title.Height = w.X, y, w.Width, 1
+	buf.Merge(title.Buffer())
+	y++
+	if stats == nil {
+		return buf
+	}
+
+	cpu := w.gauge(" CPU ", int(scaleCPU(stats.CPUPercentage, w.row.CPUScale())), fmt.Sprintf("%.2f%%", stats.CPUPercentage), &w.cpuColor, y)
+	buf.Merge(cpu.Buffer())
+	y += cpu.Height
+
+	mem := w.gauge(" MEMORY ", int(stats.MemoryPercentage),
+		fmt.Sprintf("%s / %s", units.BytesSize(stats.Memory), units.BytesSize(stats.MemoryLimit)), &w.memColor, y)
+	buf.Merge(mem.Buffer())
+	y += mem.Height
+
+	line := termui.NewSparkline()
+	line.Data = w.cpuHistory
+	line.Height = 3
+	line.LineColor = termui.Attribute(DryTheme.Info)
+	sparklines := termui.NewSparklines(line)
+	sparklines.BorderLabel = " CPU HISTORY "
+	sparklines.Bg = termui.Attribute(DryTheme.Bg)
+	sparklines.X, sparklines.Y, sparklines.Width, sparklines.Height = w.X, y, w.Width, line.Height+2
+	buf.Merge(sparklines.Buffer())
+	y += sparklines.Height
+
+	network := ui.NewPar(networkInterfacesText(stats, true, w.row.ioFormat), DryTheme)
+	network.BorderLabel = " NETWORK "
+	network.X, network.Y, network.Width, network.Height = w.X, y, w.Width, 3
+	buf.Merge(network.Buffer())
+	y += network.Height
+
+	if height := w.Y + w.Height - y; (stats.ProcessList != nil || stats.TopUnsupported) && height > minimumHeight {
+		top, _ := processListBufferer(stats, w.X, y, height, w.Width)
+		buf.Merge(top.Buffer())
+	}
+	return buf
+}
+
+//gauge returns a bordered gauge, placed at the given line, for the given percentage
+func (w *containerWatch) gauge(title string, percent int, label string, color *gaugeColor, y int) *termui.Gauge {
+	if percent > 100 {
+		percent = 100
+	} else if percent < 0 {
+		percent = 0
+	}
+	g := termui.NewGauge()
+	g.BorderLabel = title
+	g.Percent = percent
+	g.Label = label
+	g.BarColor = color.color(percent)
+	g.PercentColorHighlighted = labelColor(g.BarColor)
+	g.Bg = termui.Attribute(DryTheme.Bg)
+	g.PercentColor = termui.Attribute(DryTheme.Fg)
+	g.X, g.Y, g.Width, g.Height = w.X, y, w.Width, 3
+	return g
+}
+
+//Watch shows the container on the selected row full screen, instead of the rows
+func (m *Monitor) Watch() {
+	m.Lock()
+	defer m.Unlock()
+	m.watchSelected()
+}
+
+//StopWatching shows the rows again after a container was watched
+func (m *Monitor) StopWatching() {
+	m.Lock()
+	defer m.Unlock()
+	m.watch = nil
+}
+
+//Watching returns true if a container is being watched
+func (m *Monitor) Watching() bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.watch != nil
+}
+
+//WatchNext watches the container on the row following the one being watched
+func (m *Monitor) WatchNext() {
+	m.moveWatch(1)
+}
+
+//WatchPrevious watches the container on the row before the one being watched
+func (m *Monitor) WatchPrevious() {
+	m.moveWatch(-1)
+}
+
+//moveWatch selects the row at the given distance from the selected row and,
+//if a container is being watched, watches the container on the new row.
+func (m *Monitor) moveWatch(delta int) {
+	m.Lock()
+	defer m.Unlock()
+	if m.watch == nil {
+		return
+	}
+	pos := m.selectedRow + delta
+	if pos < 0 || pos >= len(m.rows) || pos >= m.Grid.ShownRowCount() {
+		return
+	}
+	m.selectedRow = pos
+	m.highlightSelectedRow()
+	m.Grid.Offset = pos
+	m.Grid.Align()
+	m.watchSelected()
+}
+
+func (m *Monitor) watchSelected() {
+	if m.selectedRow >= len(m.rows) {
+		return
+	}
+	row := m.rows[m.selectedRow]
+	if m.watch != nil && m.watch.row == row {
+		return
+	}
+	m.watch = newContainerWatch(row, m.Grid.X, m.Grid.Y, m.Grid.Width, m.Grid.Height)
+}
@@ -0,0 +1,74 @@
+package appui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestMonitorWatch(t *testing.T) {
+	m := newTestMonitor("web", "cache", "db")
+	m.Select(1)
+	m.WatchNext()
+	if m.Watching() {
+		t.Error("Moving the watch started watching a container")
+	}
+	m.Watch()
+	if !m.Watching() || m.watch.row != m.rows[1] {
+		t.Fatal("Selected container is not watched")
+	}
+	m.WatchNext()
+	if m.watch.row != m.rows[2] || m.Selected() != 2 {
+		t.Errorf("Next container is not watched, selected: %d", m.Selected())
+	}
+	m.WatchNext()
+	if m.watch.row != m.rows[2] {
+		t.Error("Watch moved past the last container")
+	}
+	m.WatchPrevious()
+	m.WatchPrevious()
+	if m.watch.row != m.rows[0] {
+		t.Error("Previous container is not watched")
+	}
+	m.StopWatching()
+	if m.Watching() {
+		t.Error("Container is still watched")
+	}
+}
+
+func TestContainerWatchBuffer(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"/web"}, Image: "nginx", Status: "Up 1 second"}
+	row := NewContainerStatsRow(&docker.StatsChannel{Container: container})
+	w := newContainerWatch(row, 0, 0, 80, 30)
+	for _, cpu := range []float64{10, 20, 20} {
+		row.latest = &docker.Stats{CPUPercentage: cpu, Memory: 1024, MemoryLimit: 2048, MemoryPercentage: 50}
+		w.Buffer()
+	}
+	row.latest = nil
+	w.update(nil)
+	if len(w.cpuHistory) != 3 || w.cpuHistory[1] != 2000 {
+		t.Errorf("Unexpected CPU history: %v", w.cpuHistory)
+	}
+	row.latest = w.last
+	content := watchContent(w)
+	for _, expected := range []string{"web", "20.00%", "1 KiB / 2 KiB", "CPU HISTORY", "NETWORK"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Watch does not show %q", expected)
+		}
+	}
+}
+
+func watchContent(w *containerWatch) string {
+	buf := w.Buffer()
+	var lines []string
+	for y := buf.Area.Min.Y; y < buf.Area.Max.Y; y++ {
+		var line []rune
+		for x := buf.Area.Min.X; x < buf.Area.Max.X; x++ {
+			line = append(line, buf.At(x, y).Ch)
+		}
+		lines = append(lines, string(line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,177 @@
+package appui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/moncho/dry/docker"
+)
+
+//statsFields are the fields of a stats sample that can be used on expressions
+var statsFields = map[string]func(s *docker.Stats) float64{
+	"cpu":         func(s *docker.Stats) float64 { return s.CPUPercentage },
+	"mem":         func(s *docker.Stats) float64 { return s.Memory },
+	"mem_limit":   func(s *docker.Stats) float64 { return s.MemoryLimit },
+	"mem_percent": func(s *docker.Stats) float64 { return s.MemoryPercentage },
+	"net_rx":      func(s *docker.Stats) float64 { return s.NetworkRx },
+	"net_tx":      func(s *docker.Stats) float64 { return s.NetworkTx },
+	"block_read":  func(s *docker.Stats) float64 { return s.BlockRead },
+	"block_write": func(s *docker.Stats) float64 { return s.BlockWrite },
+	"pids":        func(s *docker.Stats) float64 { return float64(s.PidsCurrent) },
+}
+
+//expression is an arithmetic expression over the fields of a stats sample
+type expression interface {
+	eval(s *docker.Stats) float64
+}
+
+type number float64
+
+func (n number) eval(s *docker.Stats) float64 { return float64(n) }
+
+type field string
+
+func (f field) eval(s *docker.Stats) float64 { return statsFields[string(f)](s) }
+
+type negation struct{ e expression }
+
+func (n negation) eval(s *docker.Stats) float64 { return -n.e.eval(s) }
+
+type binary struct {
+	op          byte
+	left, right expression
+}
+
+func (b binary) eval(s *docker.Stats) float64 {
+	l, r := b.left.eval(s), b.right.eval(s)
+	switch b.op {
+	case '+':
+		return l + r
+	case '-':
+		return l - r
+	case '*':
+		return l * r
+	}
+	return l / r
+}
+
+//parseExpression parses the given expression. Expressions are made of numbers,
+//the fields on statsFields, the +, -, * and / operators, and parentheses.
+func parseExpression(s string) (expression, error) {
+	p := &expressionParser{input: s}
+	e, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("Unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	return e, nil
+}
+
+//expressionParser is a recursive descent parser of the grammar:
+//
+//	expr   = term {("+" | "-") term}
+//	term   = factor {("*" | "/") factor}
+//	factor = number | field | "(" expr ")" | "-" factor
+type expressionParser struct {
+	input string
+	pos   int
+}
+
+func (p *expressionParser) expr() (expression, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == '+' || op == '-'; op = p.peek() {
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op, left, right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) term() (expression, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == '*' || op == '/'; op = p.peek() {
+		p.pos++
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op, left, right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) factor() (expression, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, fmt.Errorf("Unexpected end of expression")
+	case c == '(':
+		p.pos++
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("Missing ')' at position %d", p.pos)
+		}
+		p.pos++
+		return e, nil
+	case c == '-':
+		p.pos++
+		e, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return negation{e}, nil
+	case c == '.' || unicode.IsDigit(rune(c)):
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] == '.' || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		n, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number %q", p.input[start:p.pos])
+		}
+		return number(n), nil
+	case c == '_' || unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] == '_' || unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		name := strings.ToLower(p.input[start:p.pos])
+		if _, ok := statsFields[name]; !ok {
+			return nil, fmt.Errorf("Unknown field %q", name)
+		}
+		return field(name), nil
+	}
+	return nil, fmt.Errorf("Unexpected %q at position %d", c, p.pos)
+}
+
+//peek returns the next non-space character, 0 if there are no more characters
+func (p *expressionParser) peek() byte {
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *expressionParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
@@ -0,0 +1,41 @@
+package appui
+
+import (
+	"testing"
+
+	"github.com/moncho/dry/docker"
+)
+
+func TestParseExpression(t *testing.T) {
+	stats := &docker.Stats{CPUPercentage: 50, Memory: 1024, PidsCurrent: 4, NetworkRx: 10, NetworkTx: 30}
+	var tests = []struct {
+		expression string
+		expected   float64
+	}{
+		{"mem / pids", 256},
+		{"net_rx + net_tx * 2", 70},
+		{"(net_rx + net_tx) * 2", 80},
+		{"-cpu + 100", 50},
+		{" cpu/2 ", 25},
+		{"MEM / 1024", 1},
+		{"1.5 * 2", 3},
+	}
+	for _, test := range tests {
+		e, err := parseExpression(test.expression)
+		if err != nil {
+			t.Errorf("Error parsing %s: %s", test.expression, err.Error())
+			continue
+		}
+		if v := e.eval(stats); v != test.expected {
+			t.Errorf("Unexpected value of %s. Expected: %f, got: %f", test.expression, test.expected, v)
+		}
+	}
+}
+
+func TestParseInvalidExpression(t *testing.T) {
+	for _, expression := range []string{"", "mem /", "memory / pids", "(cpu + 1", "cpu + 1)", "cpu $ 2", "1..2"} {
+		if _, err := parseExpression(expression); err == nil {
+			t.Errorf("Invalid expression %q was accepted", expression)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package appui
+
+import (
+	termui "github.com/gizak/termui"
+	drytermui "github.com/moncho/dry/ui/termui"
+)
+
+//microGaugeWidth is the width under which gauges are rendered as micro gauges
+const microGaugeWidth = 10
+
+//gaugeCell renders a gauge as a GaugeColumn or, if it is too narrow for it, as
+//a MicroGauge showing the same percentage.
+type gaugeCell struct {
+	gauge *drytermui.GaugeColumn
+	micro *drytermui.MicroGauge
+}
+
+func newGaugeCell(gauge *drytermui.GaugeColumn) *gaugeCell {
+	return &gaugeCell{gauge: gauge, micro: drytermui.NewThemedMicroGauge(DryTheme)}
+}
+
+func (c *gaugeCell) useMicro() bool {
+	return c.gauge.Width < microGaugeWidth
+}
+
+func (c *gaugeCell) GetHeight() int {
+	return c.gauge.GetHeight()
+}
+
+func (c *gaugeCell) SetX(x int) {
+	c.gauge.SetX(x)
+	c.micro.SetX(x)
+}
+
+func (c *gaugeCell) SetY(y int) {
+	c.gauge.SetY(y)
+	c.micro.SetY(y)
+}
+
+func (c *gaugeCell) SetWidth(w int) {
+	c.gauge.SetWidth(w)
+	c.micro.SetWidth(w)
+}
+
+func (c *gaugeCell) Buffer() termui.Buffer {
+	if c.useMicro() {
+		c.micro.Percent = c.gauge.Percent
+		c.micro.BarColor = c.gauge.BarColor
+		return c.micro.Buffer()
+	}
+	return c.gauge.Buffer()
+}
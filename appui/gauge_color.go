@@ -0,0 +1,45 @@
+package appui
+
+import (
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/ui"
+)
+
+//DefaultColorMargin is the default margin, in percentage points, that a value
+//has to move below a threshold to go back to the color of the lower level.
+const DefaultColorMargin = 5
+
+//colorThresholds are the percentages above which the gauge color changes, the
+//color of each level is defined by the GaugeLevels of the theme.
+var colorThresholds = ui.DefaultPercentThresholds
+
+//gaugeColor remembers the color level of a gauge so that the color does not
+//flip each time a value hovering at a threshold crosses it. A level goes up as
+//soon as its threshold is exceeded and goes down once the value is margin points
+//below it.
+type gaugeColor struct {
+	level  int
+	margin int
+}
+
+//color returns the color of the gauge for the given percentage
+func (g *gaugeColor) color(n int) termui.Attribute {
+	for g.level < len(colorThresholds) && n > colorThresholds[g.level] {
+		g.level++
+	}
+	for g.level > 0 && n <= colorThresholds[g.level-1]-g.margin {
+		g.level--
+	}
+	return termui.Attribute(DryTheme.GaugeLevels[g.level])
+}
+
+//labelColor returns the color of the part of a gauge label drawn over the
+//bar of the given color, so the label is legible whatever the bar color.
+//On 256 colors mode termbox renders attribute a as the palette color a-1, the
+//contrast is calculated for the palette color actually rendered.
+func labelColor(bar termui.Attribute) termui.Attribute {
+	if bar == termui.ColorDefault {
+		return termui.Attribute(ui.Color231) + 1
+	}
+	return termui.Attribute(ui.ContrastColor(ui.Color(bar-1))) + 1
+}
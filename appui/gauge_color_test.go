@@ -0,0 +1,80 @@
+package appui
+
+import (
+	"testing"
+
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/ui"
+)
+
+func TestGaugeColorHysteresis(t *testing.T) {
+	red := termui.Attribute(ui.Color161)
+	amber := termui.Attribute(ui.Color131)
+	green := termui.Attribute(ui.Color23)
+	g := &gaugeColor{margin: 5}
+	var tests = []struct {
+		percent  int
+		expected termui.Attribute
+	}{
+		{50, green},
+		{91, red},
+		{89, red},
+		{86, red},
+		{84, amber},
+		{71, amber},
+		{68, amber},
+		{64, green},
+		{95, red},
+		{10, green},
+	}
+	for _, test := range tests {
+		if c := g.color(test.percent); c != test.expected {
+			t.Errorf("Unexpected color for %d%%. Expected: %d, got: %d", test.percent, test.expected, c)
+		}
+	}
+}
+
+func TestGaugeColorWithNoMargin(t *testing.T) {
+	g := &gaugeColor{}
+	for _, n := range []int{0, 71, 91, 90, 70, 5} {
+		if c := g.color(n); c != percentileToColor(n) {
+			t.Errorf("Color with no margin differs from the threshold color for %d%%: %d", n, c)
+		}
+	}
+}
+
+func TestGaugeColorFollowsTheme(t *testing.T) {
+	defer SetActiveTheme("dark256")
+	if err := SetActiveTheme("colorblind256"); err != nil {
+		t.Fatal(err)
+	}
+	g := &gaugeColor{margin: DefaultColorMargin}
+	for i, n := range []int{10, 80, 95} {
+		if c := g.color(n); c != termui.Attribute(ColorBlind256.GaugeLevels[i]) {
+			t.Errorf("Gauge color at %d%% is not taken from the theme, got: %d", n, c)
+		}
+	}
+}
+
+func TestGaugeLabelColorContrastsWithBar(t *testing.T) {
+	//Attributes are rendered as the palette color before them: 17 is
+	//black (palette 16) and 232 is white (palette 231)
+	var tests = []struct {
+		bar      termui.Attribute
+		expected termui.Attribute
+	}{
+		{termui.ColorDefault, 232},
+		{termui.Attribute(ui.ColorYellow), 17},
+		{termui.Attribute(ui.ColorGreen), 232},
+		{termui.Attribute(ui.Color23), 232},
+		{termui.Attribute(ui.Color131), 232},
+		{termui.Attribute(ui.Color214), 17},
+		{termui.Attribute(ui.Color231), 17},
+		{termui.Attribute(ui.Color233), 232},
+	}
+	for _, test := range tests {
+		if label := labelColor(test.bar); label != test.expected {
+			t.Errorf("Unexpected label color over bar %d. Expected: %d, got: %d", test.bar, test.expected, label)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package appui
+
+import "fmt"
+
+//IDDisplay decides how long the container IDs shown on the monitor are
+type IDDisplay int
+
+const (
+	//IDShort shows the usual truncated IDs, 12 characters long
+	IDShort IDDisplay = iota
+	//IDMedium shows IDs 24 characters long
+	IDMedium
+	//IDFull shows full IDs
+	IDFull
+)
+
+//idDisplayNames are the ID displays by name
+var idDisplayNames = map[string]IDDisplay{
+	"short":  IDShort,
+	"medium": IDMedium,
+	"full":   IDFull,
+}
+
+//ParseIDDisplay returns the ID display with the given name: short, medium or full
+func ParseIDDisplay(name string) (IDDisplay, error) {
+	if d, ok := idDisplayNames[name]; ok {
+		return d, nil
+	}
+	return IDShort, fmt.Errorf("Unknown container ID display: %s", name)
+}
+
+//next returns the display that follows this one: short, medium, full and short again
+func (d IDDisplay) next() IDDisplay {
+	return (d + 1) % (IDFull + 1)
+}
+
+//length returns the number of characters of the IDs shown, 0 if IDs are shown in full
+func (d IDDisplay) length() int {
+	switch d {
+	case IDMedium:
+		return 24
+	case IDFull:
+		return 0
+	}
+	return 12
+}
+
+//width returns the width the ID column needs to show the IDs, 0 if the
+//column shares the available width with the others, as short IDs do.
+func (d IDDisplay) width() int {
+	switch d {
+	case IDMedium:
+		return d.length()
+	case IDFull:
+		return 64
+	}
+	return 0
+}
+
+//text returns the given ID as shown with this display
+func (d IDDisplay) text(id string) string {
+	if l := d.length(); l > 0 && len(id) > l {
+		return id[:l]
+	}
+	return id
+}
+
+func (d IDDisplay) String() string {
+	switch d {
+	case IDMedium:
+		return "medium"
+	case IDFull:
+		return "full"
+	}
+	return "short"
+}
@@ -0,0 +1,80 @@
+package appui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestIDDisplayText(t *testing.T) {
+	id := strings.Repeat("0123456789abcdef", 4)
+	tests := []struct {
+		display  IDDisplay
+		expected string
+	}{
+		{IDShort, id[:12]},
+		{IDMedium, id[:24]},
+		{IDFull, id},
+	}
+	for _, test := range tests {
+		if text := test.display.text(id); text != test.expected {
+			t.Errorf("Unexpected %s ID. Expected: %s, got: %s", test.display, test.expected, text)
+		}
+	}
+	if text := IDMedium.text("CID"); text != "CID" {
+		t.Errorf("Unexpected ID shorter than the display length: %s", text)
+	}
+}
+
+func TestParseIDDisplay(t *testing.T) {
+	for name, expected := range idDisplayNames {
+		if display, err := ParseIDDisplay(name); err != nil || display != expected {
+			t.Errorf("Unexpected display parsing %s: %s, %v", name, display, err)
+		}
+	}
+	if _, err := ParseIDDisplay("long"); err == nil {
+		t.Error("Unknown ID display was parsed")
+	}
+}
+
+func TestLayoutSizedColumns(t *testing.T) {
+	priorities := []int{8, 10, 5, 9}
+	widths, visible := layoutSizedColumns(100, priorities, []int{64, 0, 0, 0}, columnSpacing)
+	expected := []int{64, 10, 10, 10}
+	for i := range expected {
+		if widths[i] != expected[i] || !visible[i] {
+			t.Fatalf("Unexpected layout. Expected widths: %v, got: %v, visible: %v", expected, widths, visible)
+		}
+	}
+	unsized, _ := layoutColumns(100, priorities, columnSpacing)
+	widths, _ = layoutSizedColumns(100, priorities, nil, columnSpacing)
+	for _, w := range widths {
+		if w != unsized {
+			t.Errorf("Columns with no size are not laid out as layoutColumns does. Expected: %d, got: %v", unsized, widths)
+		}
+	}
+}
+
+func TestStatsRowSetIDDisplay(t *testing.T) {
+	id := strings.Repeat("0123456789abcdef", 4)
+	container := &types.Container{ID: id, Names: []string{"/web"}, Status: "Exited"}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{})
+	row.SetWidth(160)
+	shortWidth := row.ID.Width
+	if row.ID.Text != id[:12] {
+		t.Errorf("Unexpected short ID: %s", row.ID.Text)
+	}
+	row.SetIDDisplay(IDFull)
+	if row.ID.Text != id {
+		t.Errorf("Unexpected full ID: %s", row.ID.Text)
+	}
+	if row.ID.Width != 64 || row.Name.Width >= shortWidth {
+		t.Errorf("ID column was not widened, ID width: %d, name width: %d", row.ID.Width, row.Name.Width)
+	}
+	row.SetIDDisplay(IDShort)
+	if row.ID.Width != shortWidth {
+		t.Errorf("ID column width was not restored. Expected: %d, got: %d", shortWidth, row.ID.Width)
+	}
+}
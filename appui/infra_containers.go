@@ -0,0 +1,46 @@
+package appui
+
+import "fmt"
+
+//InfraDisplay decides how the monitor shows the infrastructure containers, as
+//identified by the InfraClassifier of its options
+type InfraDisplay int
+
+const (
+	//InfraHidden does not show infrastructure containers, their stats are not collected
+	InfraHidden InfraDisplay = iota
+	//InfraGrouped shows infrastructure containers after the application containers
+	InfraGrouped
+	//InfraShown shows infrastructure containers as any other container
+	InfraShown
+)
+
+//infraDisplayNames are the infra displays by name
+var infraDisplayNames = map[string]InfraDisplay{
+	"hide":  InfraHidden,
+	"group": InfraGrouped,
+	"show":  InfraShown,
+}
+
+//ParseInfraDisplay returns the infra display with the given name: hide, group or show
+func ParseInfraDisplay(name string) (InfraDisplay, error) {
+	if d, ok := infraDisplayNames[name]; ok {
+		return d, nil
+	}
+	return InfraHidden, fmt.Errorf("Unknown infrastructure containers display: %s", name)
+}
+
+//next returns the display that follows this one: hidden, grouped, shown and hidden again
+func (d InfraDisplay) next() InfraDisplay {
+	return (d + 1) % (InfraShown + 1)
+}
+
+func (d InfraDisplay) String() string {
+	switch d {
+	case InfraGrouped:
+		return "grouped"
+	case InfraShown:
+		return "shown"
+	}
+	return "hidden"
+}
@@ -0,0 +1,14 @@
+package appui
+
+import "testing"
+
+func TestParseInfraDisplay(t *testing.T) {
+	for name, expected := range map[string]InfraDisplay{"hide": InfraHidden, "group": InfraGrouped, "show": InfraShown} {
+		if d, err := ParseInfraDisplay(name); err != nil || d != expected {
+			t.Errorf("Unexpected display for %s: %s, %v", name, d, err)
+		}
+	}
+	if _, err := ParseInfraDisplay("collapse"); err == nil {
+		t.Error("No error parsing an unknown display")
+	}
+}
@@ -2,60 +2,878 @@ package appui
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types"
+	gizaktermui "github.com/gizak/termui"
 	"github.com/moncho/dry/docker"
 	"github.com/moncho/dry/ui"
 	"github.com/moncho/dry/ui/termui"
 )
 
+//defaultRefreshRate is the time between two renders of the monitor. Rendering
+//is independent of how often stats are collected, on each render the last
+//sample received of each container is shown.
+const defaultRefreshRate = 250 * time.Millisecond
+
+//reconnectInterval is the time between two checks of a Docker daemon that could not be
+//reached, or that had no running containers
+var reconnectInterval = 5 * time.Second
+
+//statsPollInterval is the time between two stats samples polled on a host, see
+//StatsRowOptions.MaxStreams
+var statsPollInterval = docker.StatsInterval
+
+//writableLayerRefreshInterval is the time between two retrievals of the size of the
+//writable layer of the containers of a host, sizes are expensive to calculate
+var writableLayerRefreshInterval = time.Minute
+
+//MonitorHost is a Docker daemon monitored by a Monitor, Name identifies
+//the host on the rows of its containers.
+type MonitorHost struct {
+	Name   string
+	Daemon docker.ContainerDaemon
+}
+
+//monitoredHost is the state of a host being monitored
+type monitoredHost struct {
+	MonitorHost
+	//connected is false if the Docker daemon could not be reached
+	connected bool
+	lastPing  time.Time
+	//probing is true while the daemon is being checked again, see reconnect
+	probing bool
+	//reload is true if the stats streams of the host have to be opened again,
+	//see restartStreams
+	reload   bool
+	channels []*docker.StatsChannel
+	rows     []*ContainerStatsRow
+	//sizesLoaded is when the writable layer sizes were last retrieved, loadingSizes
+	//is true while they are being retrieved
+	sizesLoaded  time.Time
+	loadingSizes bool
+}
+
+//hostLoad is the result of checking a host, see probeHost
+type hostLoad struct {
+	connected bool
+	channels  []*docker.StatsChannel
+	rows      []*ContainerStatsRow
+}
+
 //Monitor is a self-refreshing ui component that shows monitoring information about docker
 //containers.
 type Monitor struct {
 	*termui.Grid
-	screen         *ui.Screen
+	screen        *ui.Screen
+	hosts         []*monitoredHost
+	options       *StatsRowOptions
+	filterPattern string
+	//labelSelector, if set, hides the rows of the containers whose labels do not match it
+	labelSelector  docker.LabelSelector
 	containerCount int
-	openChannels   []*docker.StatsChannel
+	rows           []*ContainerStatsRow
+	//containerRows are the rows in the order in which containers were found
+	containerRows []*ContainerStatsRow
+	//pins are the IDs or names of the containers whose rows are shown first
+	pins        []string
+	selectedRow int
+	refreshRate time.Duration
+	//watch, if set, is shown instead of the rows
+	watch *containerWatch
+	//compared is the row marked to be compared with another one, once the
+	//other row is marked the comparison is shown instead of the rows
+	compared   *ContainerStatsRow
+	comparison *containerComparison
+	//stoppedRow, if set, is the row summarizing the stopped containers
+	stoppedRow *stoppedContainersRow
+	//timelines are the subscriptions to the lifecycle events of the containers
+	//whose detail is shown
+	timelines map[*ContainerStatsRow]chan<- struct{}
+	//keyHints, if shown, are the hints of the keys that can be used
+	keyHints *termui.KeyHints
+	//stopped is true once the stats channels have been closed, see Stop
+	stopped bool
+	//watchdog decides when every stats stream is opened again, see restartStreams
+	watchdog *docker.Watchdog
+	sync.RWMutex
 }
 
 //NewMonitor creates a new Monitor component that will render itself on the given screen
-//at the given position and with the given width.
-func NewMonitor(screen *ui.Screen, daemon docker.ContainerDaemon, y int) *Monitor {
+//at the given position and with the given width. If a filter pattern is given, only the
+//containers whose name matches the pattern are monitored.
+func NewMonitor(screen *ui.Screen, daemon docker.ContainerDaemon, filterPattern string, y int) *Monitor {
+	return NewMultiHostMonitor(screen, []MonitorHost{{Daemon: daemon}}, filterPattern, y)
+}
+
+//NewMultiHostMonitor creates a new Monitor component that shows, on a single grid, the
+//containers of the given hosts. If there is more than one host, a column with the host
+//of each container is shown.
+func NewMultiHostMonitor(screen *ui.Screen, hosts []MonitorHost, filterPattern string, y int) *Monitor {
+	return NewMultiHostMonitorWithOptions(screen, hosts, filterPattern, DefaultStatsRowOptions, y)
+}
+
+//NewMultiHostMonitorWithOptions creates a new multi host Monitor, see NewMultiHostMonitor,
+//whose rows are created with the given options.
+func NewMultiHostMonitorWithOptions(screen *ui.Screen, hosts []MonitorHost, filterPattern string, options *StatsRowOptions, y int) *Monitor {
 	height := screen.Height - MainScreenHeaderSize - MainScreenFooterSize - 2
 	g := termui.NewGrid(0, y, height, screen.Width)
-	containers := daemon.ContainerStore().Filter(docker.ContainerFilters.ByRunningState(true))
-	g.AddRows(DefaultMonitorTableHeader)
-	var channels []*docker.StatsChannel
+	m := newMonitorWithOptions(g, hosts, filterPattern, options)
+	m.screen = screen
+	m.load()
+	return m
+}
+
+func newMonitor(g *termui.Grid, hosts []MonitorHost, filterPattern string) *Monitor {
+	return newMonitorWithOptions(g, hosts, filterPattern, DefaultStatsRowOptions)
+}
+
+func newMonitorWithOptions(g *termui.Grid, hosts []MonitorHost, filterPattern string, rowOptions *StatsRowOptions) *Monitor {
+	options := *rowOptions
+	options.ShowHost = options.ShowHost || len(hosts) > 1
+	g.Theme = DryTheme
+	g.AlternateRows = options.AlternateRowShading
+	g.MaxRows = options.MaxRows
+	g.ScrollPolicy = options.ScrollPolicy
+	g.Remainder = func(hidden []gizaktermui.GridBufferer) gizaktermui.GridBufferer {
+		var rows []*ContainerStatsRow
+		for _, row := range hidden {
+			if r, ok := row.(*ContainerStatsRow); ok {
+				rows = append(rows, r)
+			}
+		}
+		return newRemainderRow(rows)
+	}
+	g.SetHeader(options.header())
+	m := &Monitor{
+		Grid:          g,
+		options:       &options,
+		filterPattern: filterPattern,
+		refreshRate:   defaultRefreshRate,
+		watchdog:      docker.NewWatchdog(options.Watchdog)}
+	for _, host := range hosts {
+		m.hosts = append(m.hosts, &monitoredHost{MonitorHost: host})
+	}
+	if options.ShowKeyHints {
+		m.keyHints = termui.NewKeyHints(DryTheme)
+		g.SetHints(m.keyHints)
+	}
+	return m
+}
+
+//SetKeyHints sets the key hints shown, they are expected to change with the
+//mode of the monitor, i.e. while searching. It does nothing if hints are not shown.
+func (m *Monitor) SetKeyHints(hints ...termui.KeyHint) {
+	m.Lock()
+	defer m.Unlock()
+	if m.keyHints != nil {
+		m.keyHints.SetHints(hints...)
+	}
+}
+
+//KeyHints returns the key hints shown, nil if hints are not shown
+func (m *Monitor) KeyHints() []termui.KeyHint {
+	m.RLock()
+	defer m.RUnlock()
+	if m.keyHints == nil {
+		return nil
+	}
+	return m.keyHints.Hints()
+}
+
+//load loads every host, see loadHost, and shows the rows of their containers.
+func (m *Monitor) load() {
+	for _, host := range m.hosts {
+		m.loadHost(host)
+	}
+	m.showRows()
+}
+
+//loadHost checks the given host, see probeHost, and shows the result.
+func (m *Monitor) loadHost(host *monitoredHost) {
+	host.lastPing = time.Now()
+	m.installHost(host, probeHost(host.MonitorHost, m.options, m.filterPattern))
+}
+
+//probeHost checks that the Docker daemon of the given host is reachable and, if it is,
+//creates a row for each running container, or for every container if stopped containers
+//are shown, opening a stats channel for each one of them. Over the maximum number of
+//streams, if set, the channels of running containers are polled.
+//If the daemon cannot be reached, the host has no rows. It talks to the daemon, so it
+//must not be called holding the lock of the monitor.
+func probeHost(host MonitorHost, options *StatsRowOptions, filterPattern string) hostLoad {
+	if err := host.Daemon.Ping(); err != nil {
+		return hostLoad{}
+	}
+	filter := docker.ContainerFilters.ByRunningState(true)
+	if options.ShowStopped {
+		filter = docker.ContainerFilters.Unfiltered()
+	}
+	containers := host.Daemon.ContainerStore().Filter(filter)
+	if filterPattern != "" {
+		containers = filterContainers(containers, docker.ContainerFilters.ByName(filterPattern))
+	}
+	if options.InfraDisplay == InfraHidden && options.InfraClassifier != nil {
+		//no stats stream is opened for hidden containers
+		containers = filterContainers(containers, docker.ContainerFilters.ByInfra(options.InfraClassifier, false))
+	}
+	var cpuScale float64
+	if options.CPUGaugeHostScale {
+		cpuScale = float64(host.Daemon.HostCPUs()) * DefaultCPUGaugeScale
+	}
+	var hostMemory int64
+	if options.ShowHostMemory {
+		hostMemory = host.Daemon.HostMemory()
+	}
+	var poller *docker.StatsPoller
+	if options.MaxStreams > 0 &&
+		len(filterContainers(containers, docker.ContainerFilters.ByRunningState(true))) > options.MaxStreams {
+		poller = docker.NewStatsPoller(host.Daemon, statsPollInterval)
+	}
+	load := hostLoad{connected: true}
+	streams := 0
+	var polled []*ContainerStatsRow
 	for _, c := range containers {
-		statsChan := daemon.OpenChannel(c)
-		g.AddRows(NewContainerStatsRow(statsChan))
-		channels = append(channels, statsChan)
+		poll := poller != nil && streams >= options.MaxStreams && docker.IsContainerRunning(c)
+		var statsChan *docker.StatsChannel
+		if poll {
+			statsChan = poller.OpenChannel(c)
+		} else {
+			statsChan = host.Daemon.OpenChannel(c)
+			if docker.IsContainerRunning(c) {
+				streams++
+			}
+		}
+		row := NewContainerStatsRowWithOptions(statsChan, options)
+		if poll {
+			polled = append(polled, row)
+		}
+		row.SetHost(host.Name)
+		if cpuScale > 0 {
+			row.SetCPUScale(cpuScale)
+		}
+		if hostMemory > 0 {
+			row.SetHostMemory(hostMemory)
+		}
+		load.rows = append(load.rows, row)
+		load.channels = append(load.channels, statsChan)
+	}
+	for _, row := range polled {
+		row.setPolled(len(polled), statsPollInterval)
+	}
+	return load
+}
+
+//installHost replaces the rows and channels of the given host with the given ones,
+//loading the information of the containers shown that stats samples do not have.
+//The channels replaced are closed.
+func (m *Monitor) installHost(host *monitoredHost, load hostLoad) {
+	closeChannels(host.channels)
+	host.connected = load.connected
+	host.channels = load.channels
+	host.rows = load.rows
+	rows := load.rows
+	if m.options.GroupByPIDNamespace {
+		go m.loadPIDNamespaces(host.Daemon, rows)
+	} else if m.options.ShowLimits || m.options.ShowRestartPolicy || m.options.ShowCPUSet || m.options.ShowStartedAt || m.options.ShowNoFile {
+		go loadLimits(host.Daemon, rows)
+	}
+	if m.options.ShowWritableLayer {
+		m.loadWritableLayerSizes(host, time.Now())
+	}
+}
+
+//refreshWritableLayerSizes retrieves again the writable layer sizes of the hosts
+//whose sizes were retrieved more than writableLayerRefreshInterval ago.
+func (m *Monitor) refreshWritableLayerSizes(now time.Time) {
+	m.Lock()
+	defer m.Unlock()
+	if !m.options.ShowWritableLayer {
+		return
+	}
+	for _, host := range m.hosts {
+		if host.Daemon != nil && host.connected && !host.loadingSizes &&
+			now.Sub(host.sizesLoaded) >= writableLayerRefreshInterval {
+			m.loadWritableLayerSizes(host, now)
+		}
+	}
+}
+
+//loadWritableLayerSizes retrieves, on the background, the writable layer sizes of
+//the containers of the given host.
+func (m *Monitor) loadWritableLayerSizes(host *monitoredHost, now time.Time) {
+	host.loadingSizes = true
+	host.sizesLoaded = now
+	rows := host.rows
+	go func() {
+		loadWritableLayerSizes(host.Daemon, rows)
+		m.Lock()
+		defer m.Unlock()
+		host.loadingSizes = false
+	}()
+}
+
+//loadLimits shows on each of the given rows the resource limits, and the restart
+//policy, of its container
+func loadLimits(daemon docker.ContainerDaemon, rows []*ContainerStatsRow) {
+	for _, row := range rows {
+		if limits, err := daemon.ContainerLimits(row.container.ID); err == nil {
+			row.SetLimits(limits)
+		} else {
+			row.limitsUnavailable()
+		}
+	}
+}
+
+//loadPIDNamespaces loads the limits, including the PID namespace, of the containers
+//of the given rows, then arranges the rows so containers sharing a namespace are
+//shown together.
+func (m *Monitor) loadPIDNamespaces(daemon docker.ContainerDaemon, rows []*ContainerStatsRow) {
+	loadLimits(daemon, rows)
+	m.Lock()
+	defer m.Unlock()
+	m.arrangeRows()
+}
+
+//loadWritableLayerSizes shows on each of the given rows the size of the writable
+//layer of its container
+func loadWritableLayerSizes(daemon docker.ContainerDaemon, rows []*ContainerStatsRow) {
+	sizes, err := daemon.ContainerSizes()
+	for _, row := range rows {
+		if size, ok := sizes[row.container.ID]; err == nil && ok {
+			row.SetWritableLayerSize(size)
+		} else {
+			row.writableLayerUnavailable()
+		}
+	}
+}
+
+//showRows shows the rows of every host, in host order. If a label selector is
+//set, only the rows of the containers it selects are shown.
+func (m *Monitor) showRows() {
+	var rows []*ContainerStatsRow
+	connected := false
+	listed := make(map[string]bool)
+	for _, host := range m.hosts {
+		connected = connected || host.connected
+		for _, row := range host.rows {
+			listed[row.container.ID] = true
+			if m.labelSelector == nil || m.labelSelector.Matches(row.container.Labels) {
+				rows = append(rows, row)
+			}
+		}
+	}
+	switch {
+	case !connected:
+		m.Grid.EmptyMessage = "Cannot connect to the Docker daemon, retrying..."
+	case m.labelSelector != nil:
+		m.Grid.EmptyMessage = fmt.Sprintf("No running containers match the label selector '%s'", m.labelSelector)
+	case m.filterPattern != "":
+		m.Grid.EmptyMessage = fmt.Sprintf("No running containers match the filter '%s'", m.filterPattern)
+	default:
+		m.Grid.EmptyMessage = "There are no running containers, waiting for containers to start..."
+	}
+	if len(rows) > 0 {
+		m.Grid.SetFooter(NewAggregateStatsRow(rows))
+	} else {
+		m.Grid.SetFooter(nil)
+	}
+	//the histories of containers that are gone are not kept
+	m.options.Histories.retain(listed)
+	m.containerCount = len(rows)
+	m.containerRows = rows
+	m.arrangeRows()
+}
+
+//SetLabelSelector shows only the containers whose labels match the given
+//selector, a nil selector shows every container.
+func (m *Monitor) SetLabelSelector(selector docker.LabelSelector) {
+	m.Lock()
+	defer m.Unlock()
+	m.labelSelector = selector
+	m.showRows()
+}
+
+//reconnect loads again the hosts whose Docker daemon could not be reached, or
+//that had no containers to show, and were checked more than reconnectInterval ago,
+//and the hosts whose streams have to be opened again, see restartStreams.
+//Daemons are checked without holding the lock of the monitor, so an unreachable
+//host does not block the monitor, the rows are swapped in once the check is done.
+func (m *Monitor) reconnect(now time.Time) {
+	m.Lock()
+	var hosts []*monitoredHost
+	var idle []bool
+	for _, host := range m.hosts {
+		if host.Daemon == nil || host.probing {
+			continue
+		}
+		hostIdle := host.connected && len(host.rows) == 0
+		if !host.reload && (now.Sub(host.lastPing) < reconnectInterval || (host.connected && !hostIdle)) {
+			continue
+		}
+		//reloaded hosts are checked again as if they were idle, the
+		//containers running might have changed
+		hostIdle = hostIdle || host.reload
+		host.probing = true
+		host.lastPing = now
+		hosts = append(hosts, host)
+		idle = append(idle, hostIdle)
+	}
+	options := *m.options
+	filterPattern := m.filterPattern
+	m.Unlock()
+	if len(hosts) == 0 {
+		return
+	}
+
+	loads := make([]*hostLoad, len(hosts))
+	for i, host := range hosts {
+		if idle[i] {
+			//The containers of the host are checked again, the first
+			//container that starts is shown without a manual refresh
+			if err := host.Daemon.Refresh(options.ShowStopped); err != nil {
+				continue
+			}
+		}
+		load := probeHost(host.MonitorHost, &options, filterPattern)
+		loads[i] = &load
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	reconnected := false
+	for i, host := range hosts {
+		host.probing = false
+		load := loads[i]
+		if load == nil {
+			continue
+		}
+		if m.stopped {
+			closeChannels(load.channels)
+			continue
+		}
+		shown := len(host.rows) > 0
+		m.installHost(host, *load)
+		//the streams are open again, a failed check is retried instead
+		host.reload = false
+		//Displays toggled during the check are applied to the new rows
+		for _, row := range host.rows {
+			row.SetIODisplay(m.options.IODisplay)
+			row.SetIDDisplay(m.options.IDDisplay)
+		}
+		reconnected = reconnected || shown || (host.connected && (!idle[i] || len(host.rows) > 0))
+	}
+	if reconnected {
+		m.showRows()
+	}
+}
+
+//closeChannels closes the given stats channels
+func closeChannels(channels []*docker.StatsChannel) {
+	for _, c := range channels {
+		if c.Done != nil {
+			close(c.Done)
+		}
+	}
+}
+
+//checkEndedStreams inspects the containers whose stats channel has been closed
+//since the last check, containers killed for running out of memory are marked
+//on their rows. Streams that failed are counted by the watchdog, see restartStreams.
+//Hosts with no Docker daemon, i.e. stats dumps, are not checked.
+func (m *Monitor) checkEndedStreams(now time.Time) {
+	m.Lock()
+	var daemons []docker.ContainerDaemon
+	var rows []*ContainerStatsRow
+	restart := false
+	for _, host := range m.hosts {
+		if host.Daemon == nil {
+			continue
+		}
+		channels := make(map[string]*docker.StatsChannel, len(host.channels))
+		for _, channel := range host.channels {
+			channels[channel.Container.ID] = channel
+		}
+		for _, row := range host.rows {
+			if !row.uncheckedEnd() {
+				continue
+			}
+			daemons = append(daemons, host.Daemon)
+			rows = append(rows, row)
+			if streamFailed(channels[row.container.ID], row) && m.watchdog.Failed(now) {
+				restart = true
+			}
+		}
+	}
+	if restart && !m.stopped {
+		m.restartStreams()
+	}
+	m.Unlock()
+	for i, row := range rows {
+		cjson, err := daemons[i].Inspect(row.container.ID)
+		if err == nil && cjson.ContainerJSONBase != nil && cjson.State != nil && cjson.State.OOMKilled {
+			row.MarkOOMKilled()
+		}
+	}
+}
+
+//streamFailed returns true if the stats stream of the given channel, shown on the
+//given row, ended with an error or without sending a sample
+func streamFailed(channel *docker.StatsChannel, row *ContainerStatsRow) bool {
+	if channel == nil {
+		return false
+	}
+	select {
+	case err := <-channel.Errors:
+		if err != nil {
+			return true
+		}
+	default:
+	}
+	return row.LastUpdated().IsZero()
+}
+
+//restartStreams closes, as a last resort, the stats channels of every host and
+//opens them again, once too many streams have failed, see StatsRowOptions.Watchdog.
+//Hosts are loaded again on the background, see reconnect.
+//Must be called with the monitor locked.
+func (m *Monitor) restartStreams() {
+	log.Warnf("%d stats streams failed in %s, opening every stats stream again",
+		m.options.Watchdog.MaxFailures, m.options.Watchdog.Window)
+	for _, host := range m.hosts {
+		if host.Daemon != nil {
+			host.reload = true
+		}
+	}
+}
+
+//DismissSelected removes the selected row from the monitor if the stats of its
+//container are no longer being received, i.e. the container has stopped.
+func (m *Monitor) DismissSelected() {
+	m.Lock()
+	defer m.Unlock()
+	if m.selectedRow >= len(m.rows) {
+		return
+	}
+	dismissed := m.rows[m.selectedRow]
+	if !dismissed.StreamEnded() {
+		return
+	}
+	for _, host := range m.hosts {
+		var rows []*ContainerStatsRow
+		for _, row := range host.rows {
+			if row != dismissed {
+				rows = append(rows, row)
+			}
+		}
+		host.rows = rows
+	}
+	m.showRows()
+}
+
+//Diagnostics returns the state of the stats channels opened by the monitor and the
+//number of goroutines running to receive stats: one per stream being read and one
+//per row receiving samples.
+func (m *Monitor) Diagnostics() docker.Diagnostics {
+	m.RLock()
+	defer m.RUnlock()
+	var d docker.Diagnostics
+	for _, host := range m.hosts {
+		for _, channel := range host.channels {
+			state := channel.State()
+			d.Streams = append(d.Streams, docker.StreamDiagnostics{ContainerID: channel.Container.ID, State: state})
+			if channel.Stats != nil {
+				d.OpenChannels++
+			}
+			if state != docker.StreamEnded {
+				d.Goroutines++
+			}
+		}
+		for _, row := range host.rows {
+			if docker.IsContainerRunning(row.container) && !row.StreamEnded() {
+				d.Goroutines++
+			}
+		}
+	}
+	return d
+}
+
+//Stop closes the stats channels opened on every host, it is safe to call it
+//more than once.
+func (m *Monitor) Stop() {
+	m.Lock()
+	defer m.Unlock()
+	m.stopped = true
+	for _, host := range m.hosts {
+		closeChannels(host.channels)
+		host.channels = nil
+	}
+	for row, done := range m.timelines {
+		close(done)
+		delete(m.timelines, row)
+	}
+}
+
+//Buffer returns the content of this monitor as a termui.Buffer
+func (m *Monitor) Buffer() gizaktermui.Buffer {
+	m.RLock()
+	defer m.RUnlock()
+	if m.comparison != nil {
+		return m.comparison.Buffer()
+	}
+	if m.watch != nil {
+		return m.watch.Buffer()
 	}
-	g.Align()
-	return &Monitor{g, screen, len(containers), channels}
+	return m.Grid.Buffer()
 }
 
 //ContainerCount returns the number of containers known by this Monitor.
 func (m *Monitor) ContainerCount() int {
+	m.RLock()
+	defer m.RUnlock()
 	return m.containerCount
 }
 
+//SetRefreshRate sets the time between renders of this monitor, it has to
+//be set before the render loop is started.
+func (m *Monitor) SetRefreshRate(rate time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.refreshRate = rate
+}
+
+//ScrollDown moves the selection to the next row.
+func (m *Monitor) ScrollDown() {
+	m.Select(m.Selected() + 1)
+}
+
+//ScrollUp moves the selection to the previous row.
+func (m *Monitor) ScrollUp() {
+	m.Select(m.Selected() - 1)
+}
+
+//Select selects the row at the given position, positions out of bounds
+//are ignored.
+func (m *Monitor) Select(pos int) {
+	m.Lock()
+	defer m.Unlock()
+	if pos < 0 || pos >= m.selectableRowCount() {
+		return
+	}
+	m.selectedRow = pos
+	m.highlightSelectedRow()
+	m.Grid.Offset = pos
+	m.Grid.Align()
+}
+
+//SelectContainer selects the row of the container with the given ID, false is
+//returned if no row shows it.
+func (m *Monitor) SelectContainer(id string) bool {
+	m.Lock()
+	defer m.Unlock()
+	for i, row := range m.rows {
+		if row.container.ID == id {
+			m.selectedRow = i
+			m.highlightSelectedRow()
+			m.Grid.Offset = i
+			m.Grid.Align()
+			return true
+		}
+	}
+	return false
+}
+
+//Pin pins the container with the given ID or name, pinned containers are shown
+//first, in the order in which they were pinned.
+func (m *Monitor) Pin(idOrName string) {
+	m.Lock()
+	defer m.Unlock()
+	for _, pin := range m.pins {
+		if pin == idOrName {
+			return
+		}
+	}
+	m.pins = append(m.pins, idOrName)
+	m.arrangeRows()
+}
+
+//Unpin unpins the container with the given ID or name
+func (m *Monitor) Unpin(idOrName string) {
+	m.Lock()
+	defer m.Unlock()
+	var pins []string
+	for _, pin := range m.pins {
+		if pin != idOrName {
+			pins = append(pins, pin)
+		}
+	}
+	m.pins = pins
+	m.arrangeRows()
+}
+
+//Pins returns the IDs or names of the pinned containers, in pin order
+func (m *Monitor) Pins() []string {
+	m.RLock()
+	defer m.RUnlock()
+	return append([]string(nil), m.pins...)
+}
+
+//SetPins replaces the pinned containers with the given ones
+func (m *Monitor) SetPins(pins []string) {
+	m.Lock()
+	defer m.Unlock()
+	m.pins = append([]string(nil), pins...)
+	m.arrangeRows()
+}
+
+//TogglePinSelected pins the container on the selected row or, if
+//it is already pinned, unpins it.
+func (m *Monitor) TogglePinSelected() {
+	c := m.SelectedContainer()
+	if c == nil {
+		return
+	}
+	for _, pin := range m.Pins() {
+		if containerMatches(c, pin) {
+			m.Unpin(pin)
+			return
+		}
+	}
+	m.Pin(c.ID)
+}
+
+//ToggleSelectedDetail shows, or hides, the detail of the selected row. The
+//lifecycle events of the container are followed while its detail is shown.
+func (m *Monitor) ToggleSelectedDetail() {
+	m.Lock()
+	defer m.Unlock()
+	if m.selectedRow < len(m.rows) {
+		row := m.rows[m.selectedRow]
+		expanded := !row.Expanded()
+		row.SetExpanded(expanded)
+		if expanded {
+			m.followTimeline(row)
+		} else {
+			m.unfollowTimeline(row)
+		}
+		m.Grid.Align()
+	}
+}
+
+//followTimeline subscribes to the lifecycle events of the container of the given row
+func (m *Monitor) followTimeline(row *ContainerStatsRow) {
+	host := m.hostOf(row)
+	if host == nil || host.Daemon == nil {
+		return
+	}
+	timeline, done, err := host.Daemon.ContainerTimeline(row.container)
+	if err != nil {
+		return
+	}
+	if m.timelines == nil {
+		m.timelines = make(map[*ContainerStatsRow]chan<- struct{})
+	}
+	m.timelines[row] = done
+	row.SetTimeline(timeline)
+}
+
+//unfollowTimeline stops following the lifecycle events of the container of the given row
+func (m *Monitor) unfollowTimeline(row *ContainerStatsRow) {
+	if done, ok := m.timelines[row]; ok {
+		close(done)
+		delete(m.timelines, row)
+	}
+	row.SetTimeline(nil)
+}
+
+//hostOf returns the host of the container of the given row
+func (m *Monitor) hostOf(row *ContainerStatsRow) *monitoredHost {
+	for _, host := range m.hosts {
+		for _, r := range host.rows {
+			if r == row {
+				return host
+			}
+		}
+	}
+	return nil
+}
+
+//ResetStats clears the statistics accumulated by every row of this monitor,
+//starting a new measurement window.
+func (m *Monitor) ResetStats() {
+	m.RLock()
+	defer m.RUnlock()
+	for _, row := range m.containerRows {
+		row.ResetStats()
+	}
+}
+
+//Snapshot returns the last stats sample of each container with recent stats
+func (m *Monitor) Snapshot() []docker.StatsSnapshot {
+	m.RLock()
+	defer m.RUnlock()
+	now := time.Now()
+	var snapshots []docker.StatsSnapshot
+	for _, row := range m.containerRows {
+		if stats, _, ok := row.sample(now); ok {
+			snapshots = append(snapshots, docker.StatsSnapshot{Container: row.container, Stats: stats})
+		}
+	}
+	return snapshots
+}
+
+//Selected returns the position of the selected row.
+func (m *Monitor) Selected() int {
+	m.RLock()
+	defer m.RUnlock()
+	return m.selectedRow
+}
+
+//SelectedContainer returns the container shown on the selected row,
+//nil if there are no rows.
+func (m *Monitor) SelectedContainer() *types.Container {
+	m.RLock()
+	defer m.RUnlock()
+	if m.selectedRow < len(m.rows) {
+		return m.rows[m.selectedRow].container
+	}
+	return nil
+}
+
+//SelectedStats returns the last stats sample received of the container on
+//the selected row, nil if there is none.
+func (m *Monitor) SelectedStats() *docker.Stats {
+	m.RLock()
+	defer m.RUnlock()
+	if m.selectedRow < len(m.rows) {
+		return m.rows[m.selectedRow].Latest()
+	}
+	return nil
+}
+
 //RenderLoop makes this monitor to render itself until stopped.
 func (m *Monitor) RenderLoop(ctx context.Context) {
 
 	go func() {
-		refreshTimer := time.NewTicker(500 * time.Millisecond)
+		m.RLock()
+		refreshTimer := time.NewTicker(m.refreshRate)
+		m.RUnlock()
 		defer refreshTimer.Stop()
-		defer func() {
-			for _, c := range m.openChannels {
-				c.Done <- struct{}{}
-			}
-		}()
+		defer m.Stop()
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case <-refreshTimer.C:
+			case now := <-refreshTimer.C:
+				//Hosts are checked on the background, see reconnect
+				go m.reconnect(now)
+				m.refreshWritableLayerSizes(now)
+				m.checkEndedStreams(now)
 				m.screen.RenderBufferer(m)
 				m.screen.Flush()
 			}
@@ -63,3 +881,285 @@ func (m *Monitor) RenderLoop(ctx context.Context) {
 	}()
 
 }
+
+//selectableRowCount returns the number of rows that can be selected, every row
+//shown but the summary of stopped containers
+func (m *Monitor) selectableRowCount() int {
+	shown := m.Grid.ShownRowCount()
+	if m.stoppedRow != nil && shown > len(m.rows) {
+		return len(m.rows)
+	}
+	return shown
+}
+
+//arrangeRows orders the rows, pinned rows are placed first, following the pin order,
+//then the rest of rows in the order in which containers were found. The selected
+//container is kept selected.
+func (m *Monitor) arrangeRows() {
+	if m.options != nil && m.options.GroupByImage {
+		m.arrangeImageRows()
+		return
+	}
+	var selected *types.Container
+	if m.selectedRow < len(m.rows) {
+		selected = m.rows[m.selectedRow].container
+	}
+	var rows []*ContainerStatsRow
+	pinned := make(map[*ContainerStatsRow]bool)
+	for _, pin := range m.pins {
+		for _, row := range m.containerRows {
+			if !pinned[row] && containerMatches(row.container, pin) {
+				pinned[row] = true
+				rows = append(rows, row)
+				break
+			}
+		}
+	}
+	containerRows := m.containerRows
+	if m.options != nil && m.options.GroupByPIDNamespace {
+		containerRows = groupByPIDNamespace(containerRows)
+	}
+	var stopped, infra []*ContainerStatsRow
+	for _, row := range containerRows {
+		row.Pinned(pinned[row])
+		switch {
+		case pinned[row]:
+		case m.options != nil && m.options.GroupStopped && !row.running():
+			stopped = append(stopped, row)
+		case m.options != nil && m.options.InfraDisplay != InfraShown && m.options.InfraClassifier.IsInfra(row.container):
+			if m.options.InfraDisplay == InfraGrouped {
+				infra = append(infra, row)
+			}
+		default:
+			rows = append(rows, row)
+		}
+	}
+	rows = append(rows, infra...)
+	if len(stopped) > 0 && m.options.StoppedExpanded {
+		for _, row := range stopped {
+			row.markStopped()
+		}
+		rows = append(rows, stopped...)
+	}
+	m.rows = rows
+	m.Grid.Clear()
+	for i, row := range rows {
+		m.Grid.AddRows(row)
+		if row.container == selected {
+			m.selectedRow = i
+		}
+	}
+	m.stoppedRow = nil
+	if len(stopped) > 0 {
+		//The summary row is the last one and cannot be selected
+		m.stoppedRow = newStoppedContainersRow(len(stopped), m.options.StoppedExpanded)
+		m.Grid.AddRows(m.stoppedRow)
+	}
+	if shown := m.selectableRowCount(); m.selectedRow >= shown && shown > 0 {
+		m.selectedRow = shown - 1
+	}
+	m.highlightSelectedRow()
+	m.Grid.Offset = m.selectedRow
+	m.Grid.Align()
+}
+
+//arrangeImageRows shows a row per image with the totals of its containers,
+//container rows are not shown so no container is selected.
+func (m *Monitor) arrangeImageRows() {
+	m.rows = nil
+	m.stoppedRow = nil
+	m.Grid.Clear()
+	for _, row := range imageRows(m.containerRows, m.hostCapacity()) {
+		m.Grid.AddRows(row)
+	}
+	m.selectedRow = 0
+	m.Grid.Offset = 0
+	m.Grid.Align()
+}
+
+//hostCapacity returns the capacity of the connected hosts
+func (m *Monitor) hostCapacity() hostCapacity {
+	var capacity hostCapacity
+	for _, host := range m.hosts {
+		if !host.connected {
+			continue
+		}
+		capacity.cpus += host.Daemon.HostCPUs()
+		capacity.memory += host.Daemon.HostMemory()
+	}
+	return capacity
+}
+
+//SetSelectedNote sets, and persists, the note about the container of the selected
+//row, an empty note removes it
+func (m *Monitor) SetSelectedNote(note string) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.options == nil || m.options.Notes == nil {
+		return errors.New("Container notes are not available")
+	}
+	if m.selectedRow >= len(m.rows) {
+		return nil
+	}
+	row := m.rows[m.selectedRow]
+	if err := m.options.Notes.SetNote(row.container, note); err != nil {
+		return err
+	}
+	row.SetNote(m.options.Notes.Note(row.container))
+	//The height of the row changes if its detail is shown
+	m.Grid.Align()
+	return nil
+}
+
+//ToggleStoppedExpanded shows the rows of the stopped containers, if they are
+//collapsed on a single row, or collapses them again.
+func (m *Monitor) ToggleStoppedExpanded() {
+	m.Lock()
+	defer m.Unlock()
+	m.options.StoppedExpanded = !m.options.StoppedExpanded
+	m.arrangeRows()
+}
+
+//StoppedExpanded returns true if the rows of the stopped containers are shown
+func (m *Monitor) StoppedExpanded() bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.options.StoppedExpanded
+}
+
+//ToggleGroupByImage shows a row per image, with the totals of its containers,
+//instead of a row per container, or the other way around.
+func (m *Monitor) ToggleGroupByImage() {
+	m.Lock()
+	defer m.Unlock()
+	m.options.GroupByImage = !m.options.GroupByImage
+	m.arrangeRows()
+}
+
+//GroupByImage returns true if a row per image is shown
+func (m *Monitor) GroupByImage() bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.options.GroupByImage
+}
+
+//ToggleInfraDisplay changes how infrastructure containers are shown: hidden,
+//grouped after the application containers or as any other container. Rows of
+//containers that were hidden are not created until the monitor is loaded again.
+func (m *Monitor) ToggleInfraDisplay() InfraDisplay {
+	m.Lock()
+	defer m.Unlock()
+	m.options.InfraDisplay = m.options.InfraDisplay.next()
+	return m.options.InfraDisplay
+}
+
+//InfraDisplay returns how infrastructure containers are shown
+func (m *Monitor) InfraDisplay() InfraDisplay {
+	m.RLock()
+	defer m.RUnlock()
+	return m.options.InfraDisplay
+}
+
+//ToggleGroupByPIDNamespace shows together the containers sharing a PID namespace,
+//or shows containers in the usual order again.
+func (m *Monitor) ToggleGroupByPIDNamespace() {
+	m.Lock()
+	defer m.Unlock()
+	m.options.GroupByPIDNamespace = !m.options.GroupByPIDNamespace
+	if m.options.GroupByPIDNamespace {
+		for _, host := range m.hosts {
+			go m.loadPIDNamespaces(host.Daemon, host.rows)
+		}
+	}
+	m.arrangeRows()
+}
+
+//GroupByPIDNamespace returns true if the containers sharing a PID namespace
+//are shown together
+func (m *Monitor) GroupByPIDNamespace() bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.options.GroupByPIDNamespace
+}
+
+//ToggleIODisplay switches the network and block IO columns of every container row
+//between totals and rates, the header shows the mode. The totals row always shows rates.
+func (m *Monitor) ToggleIODisplay() {
+	m.Lock()
+	defer m.Unlock()
+	if m.options.IODisplay == IOTotals {
+		m.options.IODisplay = IOPerSecond
+	} else {
+		m.options.IODisplay = IOTotals
+	}
+	m.Grid.SetHeader(m.options.header())
+	for _, host := range m.hosts {
+		for _, row := range host.rows {
+			row.SetIODisplay(m.options.IODisplay)
+		}
+	}
+	m.Grid.Align()
+}
+
+//ToggleIDDisplay changes how long the container IDs shown are: short, medium
+//or full, the ID column widens to fit them. It returns the new display.
+func (m *Monitor) ToggleIDDisplay() IDDisplay {
+	m.Lock()
+	defer m.Unlock()
+	m.options.IDDisplay = m.options.IDDisplay.next()
+	m.Grid.SetHeader(m.options.header())
+	for _, host := range m.hosts {
+		for _, row := range host.rows {
+			row.SetIDDisplay(m.options.IDDisplay)
+		}
+	}
+	m.Grid.Align()
+	return m.options.IDDisplay
+}
+
+//IDDisplay returns how long the container IDs shown are
+func (m *Monitor) IDDisplay() IDDisplay {
+	m.RLock()
+	defer m.RUnlock()
+	return m.options.IDDisplay
+}
+
+//IODisplay returns how the network and block IO columns are shown
+func (m *Monitor) IODisplay() IODisplay {
+	m.RLock()
+	defer m.RUnlock()
+	return m.options.IODisplay
+}
+
+//containerMatches returns true if the given container has the given ID, or
+//ID prefix, or name
+func containerMatches(c *types.Container, idOrName string) bool {
+	if idOrName == "" {
+		return false
+	}
+	if strings.HasPrefix(c.ID, idOrName) {
+		return true
+	}
+	for _, name := range c.Names {
+		if strings.TrimPrefix(name, "/") == strings.TrimPrefix(idOrName, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+func filterContainers(containers []*types.Container, filter docker.ContainerFilter) []*types.Container {
+	var filtered []*types.Container
+	for _, c := range containers {
+		if filter(c) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func (m *Monitor) highlightSelectedRow() {
+	for i, row := range m.rows {
+		row.Highlighted(i == m.selectedRow)
+	}
+}
@@ -0,0 +1,31 @@
+package appui
+
+import (
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/ui"
+	"github.com/moncho/dry/ui/termui"
+)
+
+//NewStatsDumpMonitor creates a Monitor that, instead of monitoring a Docker daemon,
+//replays the samples of the given stats dump, a row for each container on the dump.
+func NewStatsDumpMonitor(screen *ui.Screen, dump *docker.StatsDump, y int) *Monitor {
+	height := screen.Height - MainScreenHeaderSize - MainScreenFooterSize - 2
+	g := termui.NewGrid(0, y, height, screen.Width)
+	m := newStatsDumpMonitor(g, dump)
+	m.screen = screen
+	return m
+}
+
+func newStatsDumpMonitor(g *termui.Grid, dump *docker.StatsDump) *Monitor {
+	m := newMonitor(g, []MonitorHost{{Name: "dump"}}, "")
+	host := m.hosts[0]
+	host.connected = true
+	for _, c := range dump.Containers {
+		statsChan := dump.OpenChannel(c)
+		host.channels = append(host.channels, statsChan)
+		host.rows = append(host.rows, NewContainerStatsRowWithOptions(statsChan, m.options))
+	}
+	m.showRows()
+	m.Grid.EmptyMessage = "There are no container stats on the dump"
+	return m
+}
@@ -4,24 +4,35 @@ import ui "github.com/gizak/termui"
 
 const (
 	columnSpacing = 1
+	//minColumnWidth is the minimum width of a column, if the available width
+	//does not allow to show every column with at least this width, columns
+	//are dropped.
+	minColumnWidth = 6
 )
 
 //DefaultMonitorTableHeader is the default header for the container monitor table
-var DefaultMonitorTableHeader ui.GridBufferer = newMonitorTableHeader()
+var DefaultMonitorTableHeader ui.GridBufferer = DefaultStatsRowOptions.header()
 
 type monitorTableHeader struct {
 	x, y          int
 	height, width int
 	pars          []*ui.Par
+	columns       []statsColumn
+	priorities    []int
+	sizes         []int
+	visible       []bool
+	separator     columnSeparator
+	separators    []int
 }
 
-func newMonitorTableHeader() *monitorTableHeader {
-	fields := []string{"CONTAINER", "NAME", "CPU", "MEM", "NET RX/TX", "BLOCK I/O", "PIDS"}
+func newMonitorTableHeader(columns ...statsColumn) *monitorTableHeader {
 	ch := &monitorTableHeader{}
 	ch.height = 1
-	for _, f := range fields {
-		ch.addPar(f)
+	for _, c := range columns {
+		ch.addPar(c.title)
 	}
+	ch.columns = columns
+	ch.priorities = priorities(columns)
 	return ch
 }
 
@@ -33,11 +44,19 @@ func (ch *monitorTableHeader) SetWidth(w int) {
 	x := ch.x
 	ch.width = w
 	//Set width on each par
-	iw := calcItemWidth(w, len(ch.pars))
-	for _, col := range ch.pars {
+	widths, visible := layoutSizedColumns(w, ch.priorities, ch.sizes, ch.separator.gap())
+	ch.visible = visible
+	ch.separators = nil
+	for i, col := range ch.pars {
+		if !visible[i] {
+			continue
+		}
+		if x > ch.x && ch.separator != 0 {
+			ch.separators = append(ch.separators, ch.separator.position(x))
+		}
 		col.SetX(x)
-		col.SetWidth(iw)
-		x += iw + columnSpacing
+		col.SetWidth(widths[i])
+		x += widths[i] + ch.separator.gap()
 	}
 }
 
@@ -54,9 +73,13 @@ func (ch *monitorTableHeader) SetY(y int) {
 
 func (ch *monitorTableHeader) Buffer() ui.Buffer {
 	buf := ui.NewBuffer()
-	for _, p := range ch.pars {
+	for i, p := range ch.pars {
+		if ch.visible != nil && !ch.visible[i] {
+			continue
+		}
 		buf.Merge(p.Buffer())
 	}
+	ch.separator.render(&buf, ch.y, ch.separators)
 	return buf
 }
 
@@ -70,7 +93,74 @@ func (ch *monitorTableHeader) addPar(s string) {
 	ch.pars = append(ch.pars, p)
 }
 
-func calcItemWidth(width, items int) int {
-	spacing := columnSpacing * items
+func calcItemWidth(width, items, gap int) int {
+	spacing := gap * items
 	return (width - spacing) / items
 }
+
+//layoutColumns decides, given the available width and the priority of each
+//column, which columns are visible and the width of each of them. Columns are
+//dropped, lowest priority first, until the remaining ones fit with at least
+//minColumnWidth or there is only one left. Columns are separated by the given
+//number of cells.
+func layoutColumns(width int, priorities []int, gap int) (int, []bool) {
+	visible := make([]bool, len(priorities))
+	for i := range visible {
+		visible[i] = true
+	}
+	count := len(priorities)
+	if count == 0 {
+		return 0, visible
+	}
+	for count > 1 && calcItemWidth(width, count, gap) < minColumnWidth {
+		lowest := -1
+		for i, p := range priorities {
+			if visible[i] && (lowest == -1 || p < priorities[lowest]) {
+				lowest = i
+			}
+		}
+		visible[lowest] = false
+		count--
+	}
+	itemWidth := calcItemWidth(width, count, gap)
+	if itemWidth < 1 {
+		itemWidth = 1
+	}
+	return itemWidth, visible
+}
+
+//layoutSizedColumns is layoutColumns for columns that might need a given width.
+//Sized columns, those with a non-zero size, get it, as long as there is room for
+//it, and the others share the remaining width as layoutColumns decides.
+//It returns the width of each column and whether it is visible.
+func layoutSizedColumns(width int, priorities []int, sizes []int, gap int) ([]int, []bool) {
+	widths := make([]int, len(priorities))
+	var flexible []int
+	remaining := width
+	for i, p := range priorities {
+		if i < len(sizes) && sizes[i] > 0 {
+			widths[i] = sizes[i]
+			if widths[i] > remaining {
+				widths[i] = remaining
+			}
+			remaining -= widths[i] + gap
+			continue
+		}
+		flexible = append(flexible, p)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	itemWidth, flexibleVisible := layoutColumns(remaining, flexible, gap)
+	visible := make([]bool, len(priorities))
+	for i, f := 0, 0; i < len(priorities); i++ {
+		if i < len(sizes) && sizes[i] > 0 {
+			visible[i] = widths[i] > 0
+			continue
+		}
+		widths[i] = itemWidth
+		visible[i] = flexibleVisible[f]
+		f++
+	}
+	return widths, visible
+}
@@ -0,0 +1,31 @@
+package appui
+
+import "testing"
+
+func TestLayoutColumns(t *testing.T) {
+	priorities := []int{8, 10, 5, 9, 7, 6, 4, 3}
+	tests := []struct {
+		width         int
+		expectedWidth int
+		visible       []bool
+	}{
+		{160, 19, []bool{true, true, true, true, true, true, true, true}},
+		{56, 6, []bool{true, true, true, true, true, true, true, true}},
+		{55, 6, []bool{true, true, true, true, true, true, true, false}},
+		{30, 6, []bool{true, true, false, true, true, false, false, false}},
+		{6, 5, []bool{false, true, false, false, false, false, false, false}},
+		{0, 1, []bool{false, true, false, false, false, false, false, false}},
+	}
+	for _, test := range tests {
+		width, visible := layoutColumns(test.width, priorities, columnSpacing)
+		if width != test.expectedWidth {
+			t.Errorf("Unexpected column width for a width of %d. Expected: %d, got: %d", test.width, test.expectedWidth, width)
+		}
+		for i := range visible {
+			if visible[i] != test.visible[i] {
+				t.Errorf("Unexpected column visibility for a width of %d. Expected: %v, got: %v", test.width, test.visible, visible)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package appui
+
+import "github.com/moncho/dry/docker"
+
+//Search selects the first row, in the order in which rows are shown, of a
+//container whose name contains the given text, the names of every matching
+//row are underlined. If no row matches, the selection does not change and
+//false is returned.
+func (m *Monitor) Search(text string) bool {
+	m.Lock()
+	defer m.Unlock()
+	if text == "" {
+		m.markSearchMatches(nil)
+		return true
+	}
+	matches := docker.ContainerFilters.ByName(text)
+	m.markSearchMatches(matches)
+	shown := m.Grid.ShownRowCount()
+	for i, row := range m.rows {
+		if i < shown && matches(row.container) {
+			m.selectedRow = i
+			m.highlightSelectedRow()
+			m.Grid.Offset = i
+			m.Grid.Align()
+			return true
+		}
+	}
+	return false
+}
+
+//ClearSearch removes the marks of the last search from the rows
+func (m *Monitor) ClearSearch() {
+	m.Lock()
+	defer m.Unlock()
+	m.markSearchMatches(nil)
+}
+
+//markSearchMatches marks the rows of the containers accepted by the given
+//filter, a nil filter unmarks every row.
+func (m *Monitor) markSearchMatches(matches docker.ContainerFilter) {
+	for _, row := range m.rows {
+		row.SearchMatch(matches != nil && matches(row.container))
+	}
+}
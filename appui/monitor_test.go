@@ -0,0 +1,884 @@
+package appui
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	gizaktermui "github.com/gizak/termui"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/mocks"
+	"github.com/moncho/dry/ui/termui"
+)
+
+func newTestMonitor(names ...string) *Monitor {
+	g := termui.NewGrid(0, 0, 20, 100)
+	var rows []*ContainerStatsRow
+	for _, name := range names {
+		c := &types.Container{ID: name + "ID", Names: []string{"/" + name}, Status: "Exited"}
+		row := NewContainerStatsRow(&docker.StatsChannel{Container: c})
+		g.AddRows(row)
+		rows = append(rows, row)
+	}
+	return &Monitor{Grid: g, rows: rows, containerRows: rows}
+}
+
+func rowNames(m *Monitor) []string {
+	var names []string
+	for _, row := range m.rows {
+		names = append(names, docker.ContainerName(row.container))
+	}
+	return names
+}
+
+func TestMonitorSelection(t *testing.T) {
+	m := newTestMonitor("web", "db", "cache")
+	if selected := m.SelectedContainer(); docker.ContainerName(selected) != "web" {
+		t.Errorf("The first row is not selected by default, got: %s", docker.ContainerName(selected))
+	}
+	m.ScrollDown()
+	m.ScrollDown()
+	m.ScrollDown()
+	if m.Selected() != 2 {
+		t.Errorf("Selection moved past the last row: %d", m.Selected())
+	}
+	m.ScrollUp()
+	if selected := m.SelectedContainer(); docker.ContainerName(selected) != "db" {
+		t.Errorf("Unexpected selected container, expected db, got: %s", docker.ContainerName(selected))
+	}
+	m.Select(-1)
+	m.Select(3)
+	if m.Selected() != 1 {
+		t.Errorf("Out of bounds positions changed the selection: %d", m.Selected())
+	}
+	for i, row := range m.rows {
+		highlighted := row.Name.TextFgColor&gizaktermui.AttrReverse != 0
+		if highlighted != (i == 1) {
+			t.Errorf("Row %d highlighted: %t, only the selected row must be highlighted", i, highlighted)
+		}
+	}
+}
+
+func TestMonitorSelectContainer(t *testing.T) {
+	m := newTestMonitor("web", "db", "cache")
+	if !m.SelectContainer("cacheID") || m.Selected() != 2 {
+		t.Errorf("The container was not selected, selected: %d", m.Selected())
+	}
+	if m.SelectContainer("queueID") || m.Selected() != 2 {
+		t.Errorf("The selection changed selecting a container not shown, selected: %d", m.Selected())
+	}
+	for i, row := range m.rows {
+		highlighted := row.Name.TextFgColor&gizaktermui.AttrReverse != 0
+		if highlighted != (i == 2) {
+			t.Errorf("Row %d highlighted: %t, only the selected row must be highlighted", i, highlighted)
+		}
+	}
+}
+
+func TestMonitorPins(t *testing.T) {
+	m := newTestMonitor("web", "cache", "db", "queue")
+	m.Select(1)
+
+	m.Pin("db")
+	m.Pin("queueID")
+	expected := []string{"db", "queue", "web", "cache"}
+	if names := rowNames(m); !equalStrings(names, expected) {
+		t.Errorf("Unexpected row order after pinning. Expected: %v, got: %v", expected, names)
+	}
+	if selected := m.SelectedContainer(); docker.ContainerName(selected) != "cache" {
+		t.Errorf("Selected container changed after pinning, got: %s", docker.ContainerName(selected))
+	}
+	m.Unpin("db")
+	expected = []string{"queue", "web", "cache", "db"}
+	if names := rowNames(m); !equalStrings(names, expected) {
+		t.Errorf("Unexpected row order after unpinning. Expected: %v, got: %v", expected, names)
+	}
+
+	m.Select(2)
+	m.TogglePinSelected()
+	if pins := m.Pins(); !equalStrings(pins, []string{"queueID", "cacheID"}) {
+		t.Errorf("Selected container was not pinned, pins: %v", pins)
+	}
+	m.TogglePinSelected()
+	if pins := m.Pins(); !equalStrings(pins, []string{"queueID"}) {
+		t.Errorf("Selected container was not unpinned, pins: %v", pins)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//flakyDaemon is a daemon that can be made unreachable
+type flakyDaemon struct {
+	mocks.ContainerDaemonMock
+	reachable  bool
+	containers []*types.Container
+	oomKilled  map[string]bool
+	channels   map[string]chan *docker.Stats
+}
+
+func (d *flakyDaemon) Ping() error {
+	if !d.reachable {
+		return errors.New("Cannot connect")
+	}
+	return nil
+}
+
+func (d *flakyDaemon) ContainerStore() *docker.ContainerStore {
+	return docker.NewMemoryStoreWithContainers(d.containers)
+}
+
+func (d *flakyDaemon) Inspect(id string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+		ID:    id,
+		State: &types.ContainerState{OOMKilled: d.oomKilled[id]}}}, nil
+}
+
+func (d *flakyDaemon) OpenChannel(c *types.Container) *docker.StatsChannel {
+	stats := make(chan *docker.Stats)
+	if d.channels != nil {
+		d.channels[c.ID] = stats
+	}
+	return &docker.StatsChannel{Container: c, Stats: stats, Done: make(chan struct{})}
+}
+
+func TestMonitorReconnects(t *testing.T) {
+	daemon := &flakyDaemon{containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+		{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+	}}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "")
+	m.load()
+	host := m.hosts[0]
+
+	if host.connected || m.ContainerCount() != 0 || m.Grid.RowCount() != 0 {
+		t.Errorf("Monitor has rows while the daemon is unreachable, rows: %d", m.Grid.RowCount())
+	}
+	if !strings.Contains(m.Grid.EmptyMessage, "Cannot connect") {
+		t.Errorf("Monitor does not show that the daemon is unreachable: %s", m.Grid.EmptyMessage)
+	}
+
+	daemon.reachable = true
+	m.reconnect(host.lastPing.Add(time.Second))
+	if host.connected {
+		t.Error("Monitor reconnected before the reconnect interval")
+	}
+	m.reconnect(host.lastPing.Add(reconnectInterval))
+	if !host.connected || m.Grid.RowCount() != 2 {
+		t.Errorf("Monitor did not reconnect, rows: %d", m.Grid.RowCount())
+	}
+}
+
+func TestMonitorForgetsTheHistoriesOfGoneContainers(t *testing.T) {
+	daemon := &flakyDaemon{reachable: true, containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+		{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+	}}
+	histories := NewStatsHistories()
+	m := newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "",
+		&StatsRowOptions{Histories: histories})
+	m.load()
+	defer m.Stop()
+
+	//containers come and go, only the histories of the listed ones are kept
+	now := time.Now()
+	for i := 3; i < 10; i++ {
+		daemon.containers = []*types.Container{
+			{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+			{ID: strconv.Itoa(i), Names: []string{"/job"}, Status: "Up 1 second"},
+		}
+		m.hosts[0].reload = true
+		m.reconnect(now)
+	}
+	if histories.Len() != 2 {
+		t.Errorf("Unexpected number of histories kept: %d", histories.Len())
+	}
+	if _, ok := histories.histories["9"]; !ok {
+		t.Error("The history of a listed container was removed")
+	}
+}
+
+//slowPingDaemon is a daemon whose pings block until released
+type slowPingDaemon struct {
+	flakyDaemon
+	release chan struct{}
+}
+
+func (d *slowPingDaemon) Ping() error {
+	<-d.release
+	return nil
+}
+
+func TestMonitorReconnectDoesNotBlockTheMonitor(t *testing.T) {
+	daemon := &slowPingDaemon{
+		flakyDaemon: flakyDaemon{containers: []*types.Container{{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"}}},
+		release:     make(chan struct{})}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "")
+	host := m.hosts[0]
+	host.lastPing = time.Now()
+
+	reconnected := make(chan struct{})
+	go func() {
+		m.reconnect(host.lastPing.Add(reconnectInterval))
+		close(reconnected)
+	}()
+	for {
+		m.RLock()
+		probing := host.probing
+		m.RUnlock()
+		if probing {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	//A host being checked is not checked again, the monitor can be used meanwhile
+	m.reconnect(host.lastPing.Add(2 * reconnectInterval))
+	m.ScrollDown()
+	m.Buffer()
+	if m.Grid.RowCount() != 0 {
+		t.Errorf("Rows were shown before the daemon answered, rows: %d", m.Grid.RowCount())
+	}
+
+	close(daemon.release)
+	<-reconnected
+	if !host.connected || host.probing || m.Grid.RowCount() != 1 {
+		t.Errorf("Rows were not shown once the daemon answered, rows: %d", m.Grid.RowCount())
+	}
+}
+
+//sizesDaemon is a daemon whose container writable layers grow each time
+//their size is retrieved
+type sizesDaemon struct {
+	flakyDaemon
+	calls int
+}
+
+func (d *sizesDaemon) ContainerSizes() (map[string]int64, error) {
+	d.calls++
+	return map[string]int64{"1": int64(d.calls) * 1024}, nil
+}
+
+func TestMonitorRefreshesWritableLayerSizes(t *testing.T) {
+	daemon := &sizesDaemon{flakyDaemon: flakyDaemon{reachable: true, containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"}}}}
+	m := newMonitorWithOptions(termui.NewGrid(0, 0, 20, 150), []MonitorHost{{Daemon: daemon}}, "",
+		&StatsRowOptions{ShowWritableLayer: true})
+	m.load()
+	host := m.hosts[0]
+	sizesLoaded := func() bool {
+		m.RLock()
+		defer m.RUnlock()
+		return !host.loadingSizes
+	}
+	for !sizesLoaded() {
+		time.Sleep(time.Millisecond)
+	}
+	if text := m.rows[0].WritableLayer.Text; text != "1 KiB" {
+		t.Errorf("Unexpected writable layer size: %s", text)
+	}
+
+	m.refreshWritableLayerSizes(host.sizesLoaded.Add(time.Second))
+	for !sizesLoaded() {
+		time.Sleep(time.Millisecond)
+	}
+	if daemon.calls != 1 {
+		t.Errorf("Sizes were retrieved again before the refresh interval, calls: %d", daemon.calls)
+	}
+	m.refreshWritableLayerSizes(host.sizesLoaded.Add(writableLayerRefreshInterval))
+	for !sizesLoaded() {
+		time.Sleep(time.Millisecond)
+	}
+	if text := m.rows[0].WritableLayer.Text; daemon.calls != 2 || text != "2 KiB" {
+		t.Errorf("Sizes were not refreshed, calls: %d, size shown: %s", daemon.calls, text)
+	}
+}
+
+func TestMonitorToggleIODisplay(t *testing.T) {
+	daemon := &flakyDaemon{reachable: true, containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 second"},
+		{ID: "2", Names: []string{"/db"}, Status: "Up 1 second"}}}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 150), []MonitorHost{{Daemon: daemon}}, "")
+	m.load()
+
+	m.ToggleIODisplay()
+	if m.IODisplay() != IOPerSecond {
+		t.Error("Monitor does not show IO rates after toggling the IO display")
+	}
+	for _, row := range m.rows {
+		if row.display() != IOPerSecond {
+			t.Errorf("Row %s does not show IO rates", row.container.Names[0])
+		}
+	}
+	buf := m.Grid.Buffer()
+	var header []rune
+	for x := buf.Area.Min.X; x < buf.Area.Max.X; x++ {
+		header = append(header, buf.At(x, m.Grid.Y).Ch)
+	}
+	if !strings.Contains(string(header), "NET RX/TX /s") {
+		t.Errorf("The header does not show the IO display mode: %s", string(header))
+	}
+
+	m.ToggleIODisplay()
+	if m.IODisplay() != IOTotals || m.rows[0].display() != IOTotals {
+		t.Error("Monitor does not show IO totals after toggling the IO display again")
+	}
+}
+
+func TestMonitorFollowsTimelineOfExpandedRows(t *testing.T) {
+	daemon := &flakyDaemon{reachable: true, containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 second"}}}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 150), []MonitorHost{{Daemon: daemon}}, "")
+	m.load()
+	row := m.rows[0]
+	height := row.GetHeight()
+
+	m.ToggleSelectedDetail()
+	if row.detail.timeline == nil || len(m.timelines) != 1 {
+		t.Error("The lifecycle events of the expanded row are not followed")
+	}
+	expanded := row.GetHeight()
+	m.ToggleSelectedDetail()
+	m.ToggleSelectedDetail()
+	if row.GetHeight() != expanded {
+		t.Errorf("Unexpected height of the row expanded again: %d, expected: %d", row.GetHeight(), expanded)
+	}
+	m.ToggleSelectedDetail()
+	if row.detail.timeline != nil || len(m.timelines) != 0 || row.GetHeight() != height {
+		t.Error("The lifecycle events of the collapsed row are still followed")
+	}
+}
+
+func TestMonitorShowsTheFirstContainerThatStarts(t *testing.T) {
+	daemon := &flakyDaemon{reachable: true}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "")
+	m.load()
+	host := m.hosts[0]
+
+	if !host.connected || m.Grid.RowCount() != 0 {
+		t.Errorf("Unexpected monitor with no running containers, rows: %d", m.Grid.RowCount())
+	}
+	if !strings.Contains(m.Grid.EmptyMessage, "waiting") {
+		t.Errorf("Monitor does not show that it waits for containers: %s", m.Grid.EmptyMessage)
+	}
+
+	daemon.containers = []*types.Container{{ID: "1", Names: []string{"/web"}, Status: "Up 1 second"}}
+	m.reconnect(host.lastPing.Add(time.Second))
+	if m.Grid.RowCount() != 0 {
+		t.Error("Monitor checked the containers again before the reconnect interval")
+	}
+	m.reconnect(host.lastPing.Add(reconnectInterval))
+	if m.Grid.RowCount() != 1 || m.ContainerCount() != 1 {
+		t.Errorf("The container that started is not shown, rows: %d", m.Grid.RowCount())
+	}
+}
+
+func TestMultiHostMonitor(t *testing.T) {
+	reachable := &flakyDaemon{reachable: true, containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+		{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+	}}
+	unreachable := &flakyDaemon{containers: []*types.Container{
+		{ID: "3", Names: []string{"/cache"}, Status: "Up 1 hour"},
+	}}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 100), []MonitorHost{
+		{Name: "host1", Daemon: reachable},
+		{Name: "host2", Daemon: unreachable}}, "")
+	m.load()
+
+	if m.Grid.RowCount() != 2 {
+		t.Errorf("Monitor does not show the containers of the reachable host, rows: %d", m.Grid.RowCount())
+	}
+	if !m.options.ShowHost {
+		t.Error("Host column is not shown when monitoring several hosts")
+	}
+	for _, row := range m.rows {
+		if row.Host.Text != "host1" {
+			t.Errorf("Unexpected host on row: %s", row.Host.Text)
+		}
+	}
+
+	unreachable.reachable = true
+	m.reconnect(m.hosts[1].lastPing.Add(reconnectInterval))
+	if m.Grid.RowCount() != 3 || m.rows[2].Host.Text != "host2" {
+		t.Errorf("Monitor did not add the containers of the reconnected host, rows: %d", m.Grid.RowCount())
+	}
+
+	m.Stop()
+	m.Stop()
+	for _, host := range m.hosts {
+		if len(host.channels) != 0 {
+			t.Errorf("Channels of host %s were not closed", host.Name)
+		}
+	}
+}
+
+//countingDaemon is a daemon that counts the stats channels opened, its
+//first refreshFailures refreshes fail
+type countingDaemon struct {
+	flakyDaemon
+	opened          int
+	refreshFailures int
+}
+
+func (d *countingDaemon) Refresh(allContainers bool) error {
+	if d.refreshFailures > 0 {
+		d.refreshFailures--
+		return errors.New("Cannot connect")
+	}
+	return nil
+}
+
+func (d *countingDaemon) OpenChannel(c *types.Container) *docker.StatsChannel {
+	d.opened++
+	return d.flakyDaemon.OpenChannel(c)
+}
+
+func TestMonitorOpensStreamsAgainWhenTooManyFail(t *testing.T) {
+	daemon := &countingDaemon{flakyDaemon: flakyDaemon{reachable: true,
+		containers: []*types.Container{
+			{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+			{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+		},
+		channels: make(map[string]chan *docker.Stats)}}
+	m := newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "",
+		&StatsRowOptions{Watchdog: docker.WatchdogPolicy{MaxFailures: 2, Window: time.Minute}})
+	m.load()
+	defer m.Stop()
+
+	//streams ending without sending a sample fail
+	close(daemon.channels["1"])
+	for !m.rows[0].StreamEnded() {
+		time.Sleep(time.Millisecond)
+	}
+	now := time.Now()
+	m.checkEndedStreams(now)
+	m.reconnect(now)
+	if daemon.opened != 2 {
+		t.Errorf("Streams were opened again after a single failure, opened: %d", daemon.opened)
+	}
+
+	close(daemon.channels["2"])
+	for !m.rows[1].StreamEnded() {
+		time.Sleep(time.Millisecond)
+	}
+	m.checkEndedStreams(now)
+	//a failed refresh does not lose the restart
+	daemon.refreshFailures = 1
+	m.reconnect(now)
+	if daemon.opened != 2 || !m.hosts[0].reload {
+		t.Errorf("Streams were opened again with a failed refresh, opened: %d", daemon.opened)
+	}
+	m.reconnect(now)
+	if daemon.opened != 4 || m.watchdog.Restarts() != 1 {
+		t.Errorf("Streams were not opened again, opened: %d, restarts: %d", daemon.opened, m.watchdog.Restarts())
+	}
+	for _, row := range m.rows {
+		if row.StreamEnded() {
+			t.Errorf("The row of %s was not replaced", docker.ContainerName(row.container))
+		}
+	}
+}
+
+//samplingDaemon is a daemon that provides single stats samples
+type samplingDaemon struct {
+	countingDaemon
+}
+
+func (d *samplingDaemon) StatsSample(c *types.Container) (*docker.Stats, error) {
+	return &docker.Stats{CID: c.ID, CPUPercentage: 50}, nil
+}
+
+func TestMonitorPollsContainersOverMaxStreams(t *testing.T) {
+	defer func(interval time.Duration) { statsPollInterval = interval }(statsPollInterval)
+	statsPollInterval = time.Millisecond
+	daemon := &samplingDaemon{countingDaemon{flakyDaemon: flakyDaemon{reachable: true,
+		containers: []*types.Container{
+			{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+			{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+			{ID: "3", Names: []string{"/cache"}, Status: "Up 1 hour"},
+		}}}}
+	m := newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "",
+		&StatsRowOptions{MaxStreams: 1})
+	m.load()
+	defer m.Stop()
+
+	if daemon.opened != 1 || m.ContainerCount() != 3 {
+		t.Errorf("Unexpected streams opened: %d, containers shown: %d", daemon.opened, m.ContainerCount())
+	}
+	timeout := time.After(time.Second)
+	for _, row := range m.rows[1:] {
+		for row.Latest() == nil {
+			select {
+			case <-timeout:
+				t.Fatalf("Stats of %s were not polled", docker.ContainerName(row.container))
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+	if m.rows[0].Latest() != nil {
+		t.Error("Stats of a streamed container were polled")
+	}
+	//polled containers receive samples less often
+	if m.rows[0].polledEvery != 0 || m.rows[1].polledEvery != 2*statsPollInterval {
+		t.Errorf("Unexpected polling intervals: %s, %s", m.rows[0].polledEvery, m.rows[1].polledEvery)
+	}
+}
+
+func TestMonitorTotalsIncludePolledContainers(t *testing.T) {
+	defer func(interval time.Duration) { statsPollInterval = interval }(statsPollInterval)
+	//no sample is polled during the test, the rows are updated by hand
+	statsPollInterval = time.Hour
+	daemon := &samplingDaemon{countingDaemon{flakyDaemon: flakyDaemon{reachable: true,
+		containers: []*types.Container{
+			{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+			{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+			{ID: "3", Names: []string{"/cache"}, Status: "Up 1 hour"},
+		}}}}
+	m := newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "",
+		&StatsRowOptions{MaxStreams: 1})
+	m.load()
+	defer m.Stop()
+
+	now := time.Now()
+	for _, row := range m.rows {
+		row.update(&docker.Stats{CID: row.container.ID, CPUPercentage: 10}, now)
+	}
+	//the streamed container is gone but polled containers are sampled in turns
+	total := NewAggregateStatsRow(m.rows)
+	total.update(now.Add(3 * docker.StatsInterval))
+	if total.Title.Text != "TOTAL (2)" || total.CPU.Text != "20.00%" {
+		t.Errorf("Unexpected totals: %s, CPU: %s", total.Title.Text, total.CPU.Text)
+	}
+	total.update(now.Add(3 * 2 * statsPollInterval))
+	if total.Title.Text != "TOTAL (0)" {
+		t.Errorf("Polled containers with no recent samples are counted: %s", total.Title.Text)
+	}
+}
+
+func TestMonitorMarksOOMKilledContainers(t *testing.T) {
+	daemon := &flakyDaemon{reachable: true,
+		containers: []*types.Container{
+			{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+			{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+		},
+		oomKilled: map[string]bool{"1": true},
+		channels:  make(map[string]chan *docker.Stats)}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "")
+	m.load()
+
+	close(daemon.channels["1"])
+	for !m.rows[0].StreamEnded() {
+		time.Sleep(time.Millisecond)
+	}
+	m.checkEndedStreams(time.Now())
+	if !m.rows[0].OOMKilled() || !strings.HasPrefix(m.rows[0].Name.Text, oomMarker) {
+		t.Errorf("OOM killed container is not marked, name: %s", m.rows[0].Name.Text)
+	}
+	if m.rows[1].OOMKilled() {
+		t.Error("Running container is marked as OOM killed")
+	}
+
+	m.Select(1)
+	m.DismissSelected()
+	if m.Grid.RowCount() != 2 {
+		t.Errorf("A running container was dismissed, rows: %d", m.Grid.RowCount())
+	}
+	m.Select(0)
+	m.DismissSelected()
+	if m.Grid.RowCount() != 1 || m.rows[0].container.ID != "2" {
+		t.Errorf("Stopped container was not dismissed, rows: %d", m.Grid.RowCount())
+	}
+}
+
+func TestMonitorDiagnostics(t *testing.T) {
+	daemon := &flakyDaemon{reachable: true,
+		containers: []*types.Container{
+			{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+			{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+		},
+		channels: make(map[string]chan *docker.Stats)}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "")
+	m.load()
+
+	d := m.Diagnostics()
+	if d.OpenChannels != 2 || len(d.Streams) != 2 {
+		t.Errorf("Unexpected diagnostics: %s", d)
+	}
+	//A goroutine per stream and a goroutine per row
+	if d.Goroutines != 4 {
+		t.Errorf("Unexpected number of goroutines: %d", d.Goroutines)
+	}
+	close(daemon.channels["1"])
+	for !m.rows[0].StreamEnded() {
+		time.Sleep(time.Millisecond)
+	}
+	if d := m.Diagnostics(); d.Goroutines != 3 {
+		t.Errorf("Unexpected number of goroutines after a stream ended: %d", d.Goroutines)
+	}
+}
+
+func TestMonitorMaxRows(t *testing.T) {
+	defer func(max int) { DefaultStatsRowOptions.MaxRows = max }(DefaultStatsRowOptions.MaxRows)
+	DefaultStatsRowOptions.MaxRows = 1
+	daemon := &flakyDaemon{reachable: true, containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+		{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+		{ID: "3", Names: []string{"/cache"}, Status: "Up 1 hour"},
+	}}
+	m := newMonitor(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "")
+	m.load()
+
+	m.Select(1)
+	if m.Selected() != 0 {
+		t.Errorf("A row over the maximum was selected: %d", m.Selected())
+	}
+	m.Grid.Align()
+	buf := m.Grid.Buffer()
+	var runes []rune
+	for y := buf.Area.Min.Y; y < buf.Area.Max.Y; y++ {
+		for x := buf.Area.Min.X; x < buf.Area.Max.X; x++ {
+			runes = append(runes, buf.At(x, y).Ch)
+		}
+	}
+	if !strings.Contains(string(runes), "+2 more") {
+		t.Error("Hidden rows are not summarized")
+	}
+}
+
+func TestMonitorSearch(t *testing.T) {
+	m := newTestMonitor("web", "cache", "db", "webcache")
+	m.Select(0)
+
+	if !m.Search("cach") || m.Selected() != 1 {
+		t.Errorf("Search did not select the first matching container, selected: %d", m.Selected())
+	}
+	if m.rows[1].Name.TextFgColor&gizaktermui.AttrUnderline == 0 || m.rows[3].Name.TextFgColor&gizaktermui.AttrUnderline == 0 {
+		t.Error("Matching rows are not marked")
+	}
+	if m.rows[0].Name.TextFgColor&gizaktermui.AttrUnderline != 0 {
+		t.Error("A row that does not match is marked")
+	}
+	if m.Search("nope") || m.Selected() != 1 {
+		t.Errorf("Selection changed on a search with no matches, selected: %d", m.Selected())
+	}
+	m.ClearSearch()
+	for _, row := range m.rows {
+		if row.Name.TextFgColor&gizaktermui.AttrUnderline != 0 {
+			t.Error("Row is still marked after the search was cleared")
+		}
+	}
+}
+
+func TestMonitorKeyHints(t *testing.T) {
+	m := newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Name: "host"}}, "", &StatsRowOptions{})
+	m.SetKeyHints(termui.KeyHint{Key: "q", Action: "quit"})
+	if hints := m.KeyHints(); hints != nil {
+		t.Errorf("Unexpected key hints on a monitor not showing them: %v", hints)
+	}
+	m = newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Name: "host"}}, "", &StatsRowOptions{ShowKeyHints: true})
+	m.SetKeyHints(termui.KeyHint{Key: "esc", Action: "cancel"})
+	if hints := m.KeyHints(); len(hints) != 1 || hints[0].Key != "esc" {
+		t.Errorf("Unexpected key hints: %v", hints)
+	}
+}
+
+func TestMonitorGroupByImage(t *testing.T) {
+	m := newTestMonitor("web", "cache", "db")
+	m.options = &StatsRowOptions{}
+	m.ToggleGroupByImage()
+	if !m.GroupByImage() || m.Grid.RowCount() != 1 || m.SelectedContainer() != nil {
+		t.Errorf("Rows were not grouped by image, rows: %d", m.Grid.RowCount())
+	}
+	m.ToggleGroupByImage()
+	if m.GroupByImage() || m.Grid.RowCount() != 3 || m.SelectedContainer() == nil {
+		t.Errorf("Container rows were not shown again, rows: %d", m.Grid.RowCount())
+	}
+}
+
+func TestStatsDumpMonitor(t *testing.T) {
+	dump, err := docker.ReadStatsDump(strings.NewReader(`{"id":"c1","name":"/web"}{"id":"c2","name":"/db"}{"id":"c1","name":"/web"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error reading a stats dump: %s", err.Error())
+	}
+	m := newStatsDumpMonitor(termui.NewGrid(0, 0, 20, 100), dump)
+	defer m.Stop()
+	if m.ContainerCount() != 2 {
+		t.Errorf("Unexpected number of containers, expected: 2, got: %d", m.ContainerCount())
+	}
+	m.checkEndedStreams(time.Now())
+}
+
+func TestMonitorLabelSelector(t *testing.T) {
+	m := newMonitor(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Name: "host"}}, "")
+	host := m.hosts[0]
+	host.connected = true
+	for name, env := range map[string]string{"web": "prod", "db": "prod", "cache": "dev"} {
+		c := &types.Container{ID: name + "ID", Names: []string{"/" + name}, Status: "Exited", Labels: map[string]string{"env": env}}
+		host.rows = append(host.rows, NewContainerStatsRow(&docker.StatsChannel{Container: c}))
+	}
+	m.showRows()
+
+	selector, _ := docker.ParseLabelSelector("env=prod")
+	m.SetLabelSelector(selector)
+	if m.ContainerCount() != 2 {
+		t.Errorf("Unexpected number of containers selected by labels, expected: 2, got: %d", m.ContainerCount())
+	}
+	for _, row := range m.rows {
+		if row.container.Labels["env"] != "prod" {
+			t.Errorf("A container not selected by labels is shown: %s", row.container.Names[0])
+		}
+	}
+	selector, _ = docker.ParseLabelSelector("env=staging")
+	m.SetLabelSelector(selector)
+	if m.ContainerCount() != 0 || !strings.Contains(m.Grid.EmptyMessage, "env=staging") {
+		t.Errorf("Unexpected monitor with no containers selected: %d containers, message: %s", m.ContainerCount(), m.Grid.EmptyMessage)
+	}
+	m.SetLabelSelector(nil)
+	if m.ContainerCount() != 3 {
+		t.Errorf("Unexpected number of containers with no label selector, expected: 3, got: %d", m.ContainerCount())
+	}
+}
+
+func BenchmarkMonitorBuffer(b *testing.B) {
+	g := termui.NewGrid(0, 0, 60, 200)
+	m := newMonitor(g, []MonitorHost{{Name: "host"}}, "")
+	host := m.hosts[0]
+	host.connected = true
+	for i := 0; i < 200; i++ {
+		c := &types.Container{ID: fmt.Sprintf("%dID", i), Names: []string{fmt.Sprintf("/container%d", i)}, Status: "Up 1 second"}
+		row := NewContainerStatsRowFor(c, m.options)
+		row.Update(&docker.Stats{CPUPercentage: float64(i % 100), MemoryPercentage: 50, Memory: 1024, MemoryLimit: 2048})
+		host.rows = append(host.rows, row)
+	}
+	m.showRows()
+	m.Select(150)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Buffer()
+	}
+}
+
+func TestMonitorGroupStopped(t *testing.T) {
+	options := *DefaultStatsRowOptions
+	options.ShowStopped = true
+	options.GroupStopped = true
+	m := newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Name: "host"}}, "", &options)
+	host := m.hosts[0]
+	host.connected = true
+	for _, c := range []*types.Container{
+		{ID: "webID", Names: []string{"/web"}, Status: "Up 1 second"},
+		{ID: "oldID", Names: []string{"/old"}, Status: "Exited"},
+		{ID: "dbID", Names: []string{"/db"}, Status: "Up 1 second"},
+		{ID: "jobID", Names: []string{"/job"}, Status: "Exited"},
+	} {
+		host.rows = append(host.rows, NewContainerStatsRowFor(c, m.options))
+	}
+	m.showRows()
+
+	if names := rowNames(m); !equalStrings(names, []string{"web", "db"}) {
+		t.Errorf("Unexpected rows with stopped containers collapsed: %v", names)
+	}
+	if m.Grid.RowCount() != 3 || !strings.Contains(m.stoppedRow.Text, "2 stopped containers") {
+		t.Errorf("Stopped containers are not summarized on a single row: %d rows", m.Grid.RowCount())
+	}
+	m.Select(2)
+	if m.Selected() != 0 {
+		t.Errorf("The summary of stopped containers was selected")
+	}
+
+	m.ToggleStoppedExpanded()
+	if names := rowNames(m); !equalStrings(names, []string{"web", "db", "old", "job"}) {
+		t.Errorf("Unexpected rows with stopped containers expanded: %v", names)
+	}
+	if m.Grid.RowCount() != 5 {
+		t.Errorf("Unexpected number of rows with stopped containers expanded: %d", m.Grid.RowCount())
+	}
+	m.Select(3)
+	if m.SelectedContainer().ID != "jobID" {
+		t.Errorf("Unexpected container selected: %s", m.SelectedContainer().ID)
+	}
+
+	m.showRows()
+	if !m.StoppedExpanded() || len(m.rows) != 4 {
+		t.Errorf("Stopped containers were collapsed again on refresh")
+	}
+}
+
+func TestMonitorSelectedNote(t *testing.T) {
+	m := newTestMonitor("web", "db")
+	if err := m.SetSelectedNote("a note"); err == nil {
+		t.Error("A note was set with no container notes")
+	}
+	dir, err := ioutil.TempDir("", "notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	notes, _ := docker.LoadContainerNotes(filepath.Join(dir, "notes.json"))
+	m.options = &StatsRowOptions{Notes: notes}
+	m.Select(1)
+	if err := m.SetSelectedNote("known leaker"); err != nil {
+		t.Fatal(err)
+	}
+	if m.rows[1].Note.Text != "known leaker" || notes.Note(m.rows[1].container) != "known leaker" {
+		t.Errorf("Unexpected note of the selected container: %s", m.rows[1].Note.Text)
+	}
+	if m.rows[0].Note.Text != "-" {
+		t.Errorf("A note was set on a container that is not selected: %s", m.rows[0].Note.Text)
+	}
+}
+
+func TestMonitorInfraContainers(t *testing.T) {
+	daemon := &flakyDaemon{reachable: true, channels: make(map[string]chan *docker.Stats), containers: []*types.Container{
+		{ID: "1", Names: []string{"/pod_sandbox"}, Image: "k8s.gcr.io/pause:3.1", Status: "Up 1 hour"},
+		{ID: "2", Names: []string{"/web"}, Image: "nginx", Status: "Up 1 hour"},
+		{ID: "3", Names: []string{"/db"}, Image: "postgres", Status: "Up 1 hour"},
+	}}
+	options := &StatsRowOptions{InfraClassifier: docker.DefaultInfraClassifier}
+	m := newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "", options)
+	m.load()
+
+	if m.Grid.RowCount() != 2 {
+		t.Errorf("Infrastructure containers are not hidden by default, rows: %d", m.Grid.RowCount())
+	}
+	if _, ok := daemon.channels["1"]; ok {
+		t.Error("A stats stream was opened for a hidden infrastructure container")
+	}
+
+	options.InfraDisplay = InfraGrouped
+	m = newMonitorWithOptions(termui.NewGrid(0, 0, 20, 100), []MonitorHost{{Daemon: daemon}}, "", options)
+	m.load()
+	if m.Grid.RowCount() != 3 || m.rows[2].container.ID != "1" {
+		t.Errorf("Infrastructure containers are not grouped after the rest, rows: %d", m.Grid.RowCount())
+	}
+
+	if display := m.ToggleInfraDisplay(); display != InfraShown {
+		t.Errorf("Unexpected infrastructure containers display: %s", display)
+	}
+	m.Lock()
+	m.arrangeRows()
+	m.Unlock()
+	if m.rows[0].container.ID != "1" {
+		t.Error("Infrastructure containers are not shown as the rest once shown")
+	}
+	if display := m.ToggleInfraDisplay(); display != InfraHidden {
+		t.Errorf("Unexpected infrastructure containers display: %s", display)
+	}
+}
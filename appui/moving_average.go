@@ -0,0 +1,80 @@
+package appui
+
+//movingAverage calculates the average of the last n values added to it,
+//values are kept on a fixed-size ring.
+type movingAverage struct {
+	values []float64
+	next   int
+	count  int
+	sum    float64
+}
+
+func newMovingAverage(size int) *movingAverage {
+	return &movingAverage{values: make([]float64, size)}
+}
+
+//add adds the given value, replacing the oldest one if the ring is full
+func (m *movingAverage) add(value float64) {
+	if m.count == len(m.values) {
+		m.sum -= m.values[m.next]
+	} else {
+		m.count++
+	}
+	m.values[m.next] = value
+	m.sum += value
+	m.next = (m.next + 1) % len(m.values)
+}
+
+//value returns the average of the values on the ring, if the ring is not
+//full yet the average is calculated over the values added so far.
+func (m *movingAverage) value() float64 {
+	if m.count == 0 {
+		return 0
+	}
+	return m.sum / float64(m.count)
+}
+
+//reset removes every value from the ring
+func (m *movingAverage) reset() {
+	m.next = 0
+	m.count = 0
+	m.sum = 0
+}
+
+//expMovingAverage is an exponential moving average, each new value is weighted
+//by alpha and the previous average by 1-alpha. An alpha of 1 disables smoothing.
+type expMovingAverage struct {
+	alpha  float64
+	avg    float64
+	seeded bool
+}
+
+//newExpMovingAverage creates an expMovingAverage with the given smoothing factor,
+//values outside (0, 1] are treated as 1.
+func newExpMovingAverage(alpha float64) *expMovingAverage {
+	if alpha <= 0 || alpha > 1 {
+		alpha = 1
+	}
+	return &expMovingAverage{alpha: alpha}
+}
+
+//add adds the given value to the average
+func (e *expMovingAverage) add(value float64) {
+	if !e.seeded {
+		e.avg = value
+		e.seeded = true
+		return
+	}
+	e.avg = e.alpha*value + (1-e.alpha)*e.avg
+}
+
+//value returns the current average
+func (e *expMovingAverage) value() float64 {
+	return e.avg
+}
+
+//reset forgets every value added so far
+func (e *expMovingAverage) reset() {
+	e.avg = 0
+	e.seeded = false
+}
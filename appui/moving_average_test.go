@@ -0,0 +1,49 @@
+package appui
+
+import "testing"
+
+func TestMovingAverage(t *testing.T) {
+	avg := newMovingAverage(3)
+	if avg.value() != 0 {
+		t.Errorf("Average of no values is not zero: %f", avg.value())
+	}
+	avg.add(3)
+	if avg.value() != 3 {
+		t.Errorf("Unexpected average on a partial window. Expected: %f, got: %f", 3.0, avg.value())
+	}
+	avg.add(6)
+	avg.add(9)
+	if avg.value() != 6 {
+		t.Errorf("Unexpected average on a full window. Expected: %f, got: %f", 6.0, avg.value())
+	}
+	avg.add(12)
+	if avg.value() != 9 {
+		t.Errorf("Oldest value was not replaced. Expected: %f, got: %f", 9.0, avg.value())
+	}
+	avg.reset()
+	if avg.value() != 0 {
+		t.Errorf("Average after a reset is not zero: %f", avg.value())
+	}
+}
+
+func TestExpMovingAverage(t *testing.T) {
+	ema := newExpMovingAverage(0.5)
+	ema.add(10)
+	if ema.value() != 10 {
+		t.Errorf("First value does not seed the average. Expected: %f, got: %f", 10.0, ema.value())
+	}
+	ema.add(20)
+	if ema.value() != 15 {
+		t.Errorf("Unexpected smoothed value. Expected: %f, got: %f", 15.0, ema.value())
+	}
+
+	noSmoothing := newExpMovingAverage(1)
+	noSmoothing.add(10)
+	noSmoothing.add(20)
+	if noSmoothing.value() != 20 {
+		t.Errorf("An alpha of 1 smoothed the value. Expected: %f, got: %f", 20.0, noSmoothing.value())
+	}
+	if newExpMovingAverage(0).alpha != 1 {
+		t.Error("An alpha of 0 is not treated as no smoothing")
+	}
+}
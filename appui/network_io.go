@@ -0,0 +1,40 @@
+package appui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moncho/dry/docker"
+)
+
+//networkInterfacesText describes the network IO of each interface of the container
+//of the given sample, ordered by interface name. If the container has a single
+//interface, the interface name is omitted unless expand is set.
+func networkInterfacesText(stat *docker.Stats, expand bool, format ByteFormat) string {
+	if stat.Stats == nil || len(stat.Stats.Networks) == 0 ||
+		(len(stat.Stats.Networks) == 1 && !expand) {
+		return networkText(stat.NetworkRx, stat.NetworkTx, format)
+	}
+	var names []string
+	for name := range stat.Stats.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var interfaces []string
+	for _, name := range names {
+		n := stat.Stats.Networks[name]
+		interfaces = append(interfaces,
+			fmt.Sprintf("%s: %s", name, networkText(float64(n.RxBytes), float64(n.TxBytes), format)))
+	}
+	return strings.Join(interfaces, ", ")
+}
+
+func networkText(rx, tx float64, format ByteFormat) string {
+	return fmt.Sprintf("%s / %s", format.size(rx), format.size(tx))
+}
+
+//rateText describes a pair of IO rates, in bytes per second
+func rateText(in, out float64, format ByteFormat) string {
+	return fmt.Sprintf("%s/s / %s/s", format.size(in), format.size(out))
+}
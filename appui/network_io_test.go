@@ -0,0 +1,34 @@
+package appui
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestNetworkInterfacesText(t *testing.T) {
+	single := &docker.Stats{NetworkRx: 1024, NetworkTx: 2048, Stats: &types.StatsJSON{
+		Networks: map[string]types.NetworkStats{
+			"eth0": {RxBytes: 1024, TxBytes: 2048}}}}
+	several := &docker.Stats{NetworkRx: 3072, NetworkTx: 2048, Stats: &types.StatsJSON{
+		Networks: map[string]types.NetworkStats{
+			"eth1": {RxBytes: 2048, TxBytes: 0},
+			"eth0": {RxBytes: 1024, TxBytes: 2048}}}}
+
+	tests := []struct {
+		stat     *docker.Stats
+		expand   bool
+		expected string
+	}{
+		{single, false, "1 KiB / 2 KiB"},
+		{single, true, "eth0: 1 KiB / 2 KiB"},
+		{several, false, "eth0: 1 KiB / 2 KiB, eth1: 2 KiB / 0 B"},
+		{&docker.Stats{NetworkRx: 1024}, true, "1 KiB / 0 B"},
+	}
+	for i, test := range tests {
+		if text := networkInterfacesText(test.stat, test.expand, nil); text != test.expected {
+			t.Errorf("Test %d: unexpected network text. Expected: %s, got: %s", i, test.expected, text)
+		}
+	}
+}
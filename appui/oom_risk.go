@@ -0,0 +1,57 @@
+package appui
+
+import (
+	"time"
+
+	"github.com/moncho/dry/docker"
+)
+
+//DefaultOOMRiskMargin is the percentage of its memory limit that the working set
+//of a container has to go over for the container to be at risk of being killed
+const DefaultOOMRiskMargin = 95
+
+//DefaultOOMRiskPeriod is how long the working set of a container has to stay
+//over the risk margin for the container to be shown at risk
+const DefaultOOMRiskPeriod = 30 * time.Second
+
+//oomRiskMarker is shown before the name of containers at risk of running out of memory
+const oomRiskMarker = "OOM RISK "
+
+//oomRisk tells if a container is at risk of being killed for running out of
+//memory: its working set, the memory used that is not page cache, has been
+//close to its memory limit for a sustained period.
+type oomRisk struct {
+	//margin is the percentage of the limit the working set has to go over
+	margin float64
+	period time.Duration
+	//since is when the working set went over the margin, zero if it is below
+	since time.Time
+}
+
+func newOOMRisk(margin float64, period time.Duration) *oomRisk {
+	if margin <= 0 {
+		margin = DefaultOOMRiskMargin
+	}
+	if period <= 0 {
+		period = DefaultOOMRiskPeriod
+	}
+	return &oomRisk{margin: margin, period: period}
+}
+
+//update checks the given sample, received at the given time, and returns true
+//if the container is at risk.
+func (r *oomRisk) update(s *docker.Stats, now time.Time) bool {
+	if s.MemoryLimit <= 0 || s.MemoryWorkingSet*100/s.MemoryLimit < r.margin {
+		r.since = time.Time{}
+		return false
+	}
+	if r.since.IsZero() {
+		r.since = now
+	}
+	return now.Sub(r.since) >= r.period
+}
+
+//reset forgets when the working set went over the margin
+func (r *oomRisk) reset() {
+	r.since = time.Time{}
+}
@@ -0,0 +1,53 @@
+package appui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestOOMRisk(t *testing.T) {
+	risk := newOOMRisk(90, time.Minute)
+	now := time.Now()
+	high := &docker.Stats{Memory: 1000, MemoryWorkingSet: 950, MemoryLimit: 1000}
+	cached := &docker.Stats{Memory: 1000, MemoryWorkingSet: 500, MemoryLimit: 1000}
+
+	if risk.update(high, now) {
+		t.Error("Container is at risk as soon as its working set is close to the limit")
+	}
+	if risk.update(high, now.Add(30*time.Second)) {
+		t.Error("Container is at risk before the risk period")
+	}
+	if !risk.update(high, now.Add(time.Minute)) {
+		t.Error("Container is not at risk after the risk period")
+	}
+	if risk.update(cached, now.Add(2*time.Minute)) {
+		t.Error("Container whose memory is mostly page cache is at risk")
+	}
+	if risk.update(high, now.Add(3*time.Minute)) {
+		t.Error("Risk period did not start again once the working set went below the margin")
+	}
+	if risk.update(&docker.Stats{MemoryWorkingSet: 950}, now.Add(5*time.Minute)) {
+		t.Error("Container with no memory limit reported is at risk")
+	}
+}
+
+func TestStatsRowOOMRiskMarker(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowFor(container, &StatsRowOptions{OOMRiskMargin: 90, OOMRiskPeriod: time.Second})
+	now := time.Now()
+	high := &docker.Stats{Memory: 1000, MemoryWorkingSet: 950, MemoryLimit: 1000}
+	row.update(high, now)
+	row.update(high, now.Add(time.Second))
+	row.Buffer()
+	if row.Name.Text != oomRiskMarker+"Name" {
+		t.Errorf("Container at risk of running out of memory is not marked: %s", row.Name.Text)
+	}
+	row.update(&docker.Stats{Memory: 1000, MemoryWorkingSet: 100, MemoryLimit: 1000}, now.Add(2*time.Second))
+	row.Buffer()
+	if row.Name.Text != "Name" {
+		t.Errorf("OOM risk marker was not removed: %s", row.Name.Text)
+	}
+}
@@ -0,0 +1,57 @@
+package appui
+
+import "strings"
+
+//hostPIDNamespace is the namespace of the containers using the PID namespace of the host
+const hostPIDNamespace = "host"
+
+//groupByPIDNamespace returns the given rows placing together the rows of containers
+//sharing a PID namespace, groups follow the order in which their first row is
+//found. If no namespace is shared, the order does not change.
+func groupByPIDNamespace(rows []*ContainerStatsRow) []*ContainerStatsRow {
+	var namespaces []string
+	groups := make(map[string][]*ContainerStatsRow)
+	for _, row := range rows {
+		ns := pidNamespaceOf(row, rows)
+		if _, ok := groups[ns]; !ok {
+			namespaces = append(namespaces, ns)
+		}
+		groups[ns] = append(groups[ns], row)
+	}
+	grouped := make([]*ContainerStatsRow, 0, len(rows))
+	for _, ns := range namespaces {
+		grouped = append(grouped, groups[ns]...)
+	}
+	return grouped
+}
+
+//pidNamespaceOf identifies the PID namespace of the container of the given row:
+//the ID of the container that owns the namespace, which can be the container
+//itself, or hostPIDNamespace. Containers are looked up on the given rows.
+func pidNamespaceOf(row *ContainerStatsRow, rows []*ContainerStatsRow) string {
+	//namespaces can be shared with a container that shares its own, the
+	//chain is followed at most once per row to avoid cycles
+	for i := 0; i <= len(rows); i++ {
+		mode := row.pidNamespace()
+		if mode == hostPIDNamespace {
+			return hostPIDNamespace
+		}
+		if !strings.HasPrefix(mode, "container:") {
+			return row.container.ID
+		}
+		owner := strings.TrimPrefix(mode, "container:")
+		var found *ContainerStatsRow
+		for _, r := range rows {
+			if containerMatches(r.container, owner) {
+				found = r
+				break
+			}
+		}
+		if found == nil {
+			//the owner is not shown, containers sharing its namespace are still grouped
+			return owner
+		}
+		row = found
+	}
+	return row.container.ID
+}
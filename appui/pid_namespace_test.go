@@ -0,0 +1,42 @@
+package appui
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestGroupByPIDNamespace(t *testing.T) {
+	newRow := func(id, name, pidMode string) *ContainerStatsRow {
+		return &ContainerStatsRow{
+			container: &types.Container{ID: id, Names: []string{"/" + name}},
+			pidMode:   pidMode}
+	}
+	sidecar := newRow("1", "sidecar", "container:app")
+	web := newRow("2", "web", "")
+	agent := newRow("3", "agent", "host")
+	app := newRow("4", "app", "")
+	debug := newRow("5", "debug", "container:1")
+	exporter := newRow("6", "exporter", "host")
+	orphan := newRow("7", "orphan", "container:gone")
+
+	grouped := groupByPIDNamespace([]*ContainerStatsRow{sidecar, web, agent, app, debug, exporter, orphan})
+	expected := []*ContainerStatsRow{sidecar, app, debug, web, agent, exporter, orphan}
+	for i, row := range grouped {
+		if row != expected[i] {
+			t.Errorf("Unexpected row at position %d. Expected: %s, got: %s", i, expected[i].container.ID, row.container.ID)
+		}
+	}
+
+	unshared := []*ContainerStatsRow{newRow("1", "a", ""), newRow("2", "b", ""), newRow("3", "c", "")}
+	for i, row := range groupByPIDNamespace(unshared) {
+		if row != unshared[i] {
+			t.Errorf("Rows were reordered with no shared namespace, position %d: %s", i, row.container.ID)
+		}
+	}
+
+	cycle := []*ContainerStatsRow{newRow("1", "a", "container:b"), newRow("2", "b", "container:a")}
+	if len(groupByPIDNamespace(cycle)) != 2 {
+		t.Error("Rows sharing namespaces in a cycle were lost")
+	}
+}
@@ -101,10 +101,16 @@ func (r *DockerPs) containerTable() string {
 func (r *DockerPs) tableHeader() string {
 	columns := make([]string, len(r.columns))
 	for i, col := range r.columns {
-		if r.data.sortMode != col.mode {
-			columns[i] = col.title
-		} else {
+		switch {
+		case r.data.sortMode == col.mode:
 			columns[i] = DownArrow + col.title
+		//Sorting by uptime is shown on the status column
+		case col.mode == docker.SortByStatus && r.data.sortMode == docker.SortByUptime:
+			columns[i] = DownArrow + col.title + " (UPTIME)"
+		case col.mode == docker.SortByStatus && r.data.sortMode == docker.SortByUptimeNewest:
+			columns[i] = DownArrow + col.title + " (NEWEST)"
+		default:
+			columns[i] = col.title
 		}
 	}
 	return "<green>" + strings.Join(columns, "\t") + "</>"
@@ -133,29 +139,30 @@ func (r *DockerPs) containerInformation() string {
 	return buf.String()
 }
 
+//containersToShow returns the containers that fit on the screen, the selected
+//container is the last one shown if it does not fit on the first page.
 func (r *DockerPs) containersToShow() []*types.Container {
-	containers := r.data.containers
-	cursorPos := r.data.selectedContainer
-	availableLines := r.height - containerTableStartPos - 1
+	return pageContainers(r.data.containers, r.data.selectedContainer, r.height-containerTableStartPos-1)
+}
 
-	if len(containers) < availableLines {
+//pageContainers returns the containers shown on the given number of lines,
+//so that the container at the cursor position is shown. At least one container
+//is shown.
+func pageContainers(containers []*types.Container, cursorPos, availableLines int) []*types.Container {
+	if availableLines < 1 {
+		availableLines = 1
+	}
+	if len(containers) <= availableLines {
 		return containers
 	}
-
-	start, end := 0, 0
-
-	if cursorPos > availableLines {
+	if cursorPos >= len(containers) {
+		cursorPos = len(containers) - 1
+	}
+	start := 0
+	if cursorPos >= availableLines {
 		start = cursorPos + 1 - availableLines
-		end = cursorPos + 1
-	} else if cursorPos == availableLines {
-		start = 1
-		end = availableLines + 1
-	} else {
-		start = 0
-		end = availableLines
 	}
-
-	return containers[start:end]
+	return containers[start : start+availableLines]
 }
 
 func buildContainerTableTemplate() *template.Template {
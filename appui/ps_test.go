@@ -0,0 +1,54 @@
+package appui
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestPageContainers(t *testing.T) {
+	newContainers := func(n int) []*types.Container {
+		var containers []*types.Container
+		for i := 0; i < n; i++ {
+			containers = append(containers, &types.Container{ID: strconv.Itoa(i)})
+		}
+		return containers
+	}
+	tests := []struct {
+		containers     int
+		cursor         int
+		availableLines int
+		expectedFirst  int
+		expectedCount  int
+	}{
+		//one less container than lines
+		{4, 0, 5, 0, 4},
+		{4, 3, 5, 0, 4},
+		//as many containers as lines
+		{5, 0, 5, 0, 5},
+		{5, 4, 5, 0, 5},
+		//one more container than lines
+		{6, 0, 5, 0, 5},
+		{6, 4, 5, 0, 5},
+		{6, 5, 5, 1, 5},
+		//cursor out of range
+		{6, 10, 5, 1, 5},
+		//no lines available
+		{3, 2, 0, 2, 1},
+		{3, 0, -2, 0, 1},
+		{0, 0, 5, 0, 0},
+	}
+	for _, test := range tests {
+		shown := pageContainers(newContainers(test.containers), test.cursor, test.availableLines)
+		if len(shown) != test.expectedCount {
+			t.Errorf("%d containers, cursor %d, %d lines: expected %d containers shown, got %d",
+				test.containers, test.cursor, test.availableLines, test.expectedCount, len(shown))
+			continue
+		}
+		if len(shown) > 0 && shown[0].ID != strconv.Itoa(test.expectedFirst) {
+			t.Errorf("%d containers, cursor %d, %d lines: expected the first container shown to be %d, got %s",
+				test.containers, test.cursor, test.availableLines, test.expectedFirst, shown[0].ID)
+		}
+	}
+}
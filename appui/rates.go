@@ -0,0 +1,82 @@
+package appui
+
+import (
+	"time"
+
+	"github.com/moncho/dry/docker"
+)
+
+//IORates are the network and block IO rates of a container, in bytes per second,
+//and its block IO operations per second.
+type IORates struct {
+	NetworkRx  float64
+	NetworkTx  float64
+	BlockRead  float64
+	BlockWrite float64
+	ReadOps    float64
+	WriteOps   float64
+}
+
+//IODisplay decides how the network and block IO of containers is shown
+type IODisplay int
+
+const (
+	//IOTotals shows the bytes transferred since the container started
+	IOTotals IODisplay = iota
+	//IOPerSecond shows the bytes transferred per second
+	IOPerSecond
+)
+
+//add adds the given rates to these rates
+func (r *IORates) add(other IORates) {
+	r.NetworkRx += other.NetworkRx
+	r.NetworkTx += other.NetworkTx
+	r.BlockRead += other.BlockRead
+	r.BlockWrite += other.BlockWrite
+	r.ReadOps += other.ReadOps
+	r.WriteOps += other.WriteOps
+}
+
+//rateCalculator calculates IO rates from consecutive stats samples
+type rateCalculator struct {
+	last     *docker.Stats
+	lastTime time.Time
+}
+
+//update calculates the rates between the last sample and the given one, received
+//at the given time. False is returned if there are no rates yet, which is the
+//case of the first sample.
+func (c *rateCalculator) update(s *docker.Stats, now time.Time) (IORates, bool) {
+	last, lastTime := c.last, c.lastTime
+	c.last, c.lastTime = s, now
+	if last == nil {
+		return IORates{}, false
+	}
+	seconds := now.Sub(lastTime).Seconds()
+	if seconds <= 0 {
+		return IORates{}, false
+	}
+	return IORates{
+		NetworkRx:  rate(last.NetworkRx, s.NetworkRx, seconds),
+		NetworkTx:  rate(last.NetworkTx, s.NetworkTx, seconds),
+		BlockRead:  rate(last.BlockRead, s.BlockRead, seconds),
+		BlockWrite: rate(last.BlockWrite, s.BlockWrite, seconds),
+		ReadOps:    rate(last.BlockReadOps, s.BlockReadOps, seconds),
+		WriteOps:   rate(last.BlockWriteOps, s.BlockWriteOps, seconds),
+	}, true
+}
+
+//reset forgets the last sample
+func (c *rateCalculator) reset() {
+	c.last = nil
+	c.lastTime = time.Time{}
+}
+
+//rate returns the rate of change per second between two values of a counter,
+//if the counter was reset the rate is zero.
+func rate(previous, current, seconds float64) float64 {
+	if current < previous {
+		return 0
+	}
+	return (current - previous) / seconds
+}
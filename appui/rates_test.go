@@ -0,0 +1,82 @@
+package appui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moncho/dry/docker"
+)
+
+func TestRateCalculator(t *testing.T) {
+	calc := &rateCalculator{}
+	now := time.Now()
+	if _, ok := calc.update(&docker.Stats{NetworkRx: 100}, now); ok {
+		t.Error("Rates were calculated from a single sample")
+	}
+	rates, ok := calc.update(&docker.Stats{NetworkRx: 300, BlockWrite: 50}, now.Add(2*time.Second))
+	if !ok {
+		t.Fatal("Rates were not calculated from two samples")
+	}
+	if rates.NetworkRx != 100 || rates.BlockWrite != 25 {
+		t.Errorf("Unexpected rates: %+v", rates)
+	}
+	//Counters are reset, i.e. the container was restarted
+	rates, _ = calc.update(&docker.Stats{NetworkRx: 10}, now.Add(3*time.Second))
+	if rates.NetworkRx != 0 {
+		t.Errorf("Unexpected rate after a counter reset: %f", rates.NetworkRx)
+	}
+}
+
+func TestRateCalculatorIOPS(t *testing.T) {
+	calc := &rateCalculator{}
+	now := time.Now()
+	calc.update(&docker.Stats{BlockReadOps: 100, BlockWriteOps: 10}, now)
+	rates, _ := calc.update(&docker.Stats{BlockReadOps: 300, BlockWriteOps: 10}, now.Add(2*time.Second))
+	if rates.ReadOps != 100 || rates.WriteOps != 0 {
+		t.Errorf("Unexpected IOPS: %+v", rates)
+	}
+}
+
+func TestRateCalculatorCounterResets(t *testing.T) {
+	var tests = []struct {
+		name     string
+		previous docker.Stats
+		current  docker.Stats
+		rate     func(IORates) float64
+	}{
+		{"network rx", docker.Stats{NetworkRx: 5000}, docker.Stats{NetworkRx: 100},
+			func(r IORates) float64 { return r.NetworkRx }},
+		{"network tx", docker.Stats{NetworkTx: 5000}, docker.Stats{NetworkTx: 100},
+			func(r IORates) float64 { return r.NetworkTx }},
+		{"block read", docker.Stats{BlockRead: 5000}, docker.Stats{BlockRead: 100},
+			func(r IORates) float64 { return r.BlockRead }},
+		{"block write", docker.Stats{BlockWrite: 5000}, docker.Stats{BlockWrite: 100},
+			func(r IORates) float64 { return r.BlockWrite }},
+		{"block read ops", docker.Stats{BlockReadOps: 50}, docker.Stats{BlockReadOps: 1},
+			func(r IORates) float64 { return r.ReadOps }},
+		{"block write ops", docker.Stats{BlockWriteOps: 50}, docker.Stats{BlockWriteOps: 1},
+			func(r IORates) float64 { return r.WriteOps }},
+	}
+	now := time.Now()
+	for _, test := range tests {
+		calc := &rateCalculator{}
+		previous, current := test.previous, test.current
+		calc.update(&previous, now)
+		rates, ok := calc.update(&current, now.Add(time.Second))
+		if !ok {
+			t.Errorf("%s: rates were not calculated after a counter reset", test.name)
+		}
+		if rate := test.rate(rates); rate != 0 {
+			t.Errorf("%s: unexpected rate after a counter reset: %f", test.name, rate)
+		}
+		//Rates are calculated again from the sample after the reset
+		next := current
+		next.NetworkRx, next.NetworkTx = current.NetworkRx*2, current.NetworkTx*2
+		next.BlockRead, next.BlockWrite = current.BlockRead*2, current.BlockWrite*2
+		next.BlockReadOps, next.BlockWriteOps = current.BlockReadOps*2, current.BlockWriteOps*2
+		rates, _ = calc.update(&next, now.Add(2*time.Second))
+		if rate := test.rate(rates); rate <= 0 {
+			t.Errorf("%s: unexpected rate after the sample that followed a reset: %f", test.name, rate)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package appui
+
+import (
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/ui"
+)
+
+//rawStatsRenderer renders a stats sample as received from the Docker API
+type rawStatsRenderer struct {
+	stats *types.StatsJSON
+}
+
+//NewRawStatsRenderer creates a renderer for the given stats sample, it is
+//rendered as pretty-printed JSON
+func NewRawStatsRenderer(stats *types.StatsJSON) ui.Renderer {
+	return &rawStatsRenderer{stats: stats}
+}
+
+//Render the stats sample
+func (r *rawStatsRenderer) Render() string {
+	if r.stats == nil {
+		return "No stats have been received for the container"
+	}
+	c, err := json.MarshalIndent(r.stats, "", "    ")
+	if err != nil {
+		return "There was an error rendering the stats of the container"
+	}
+	return string(c) + "\n"
+}
@@ -0,0 +1,20 @@
+package appui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestRawStatsRenderer(t *testing.T) {
+	stats := &types.StatsJSON{}
+	stats.PidsStats.Current = 42
+	output := NewRawStatsRenderer(stats).Render()
+	if !strings.Contains(output, "\"pids_stats\": {\n        \"current\": 42\n    }") {
+		t.Errorf("Stats were not rendered as pretty-printed JSON, got: %s", output)
+	}
+	if output := NewRawStatsRenderer(nil).Render(); output == "" {
+		t.Error("Nothing was rendered for a container with no stats")
+	}
+}
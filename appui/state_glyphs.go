@@ -0,0 +1,55 @@
+package appui
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+//StateGlyphs maps container states to the glyph shown before the container name
+type StateGlyphs map[string]rune
+
+//UnicodeStateGlyphs are the glyphs shown for each container state
+var UnicodeStateGlyphs = StateGlyphs{
+	"running":    '▶',
+	"paused":     '⏸',
+	"exited":     '⏹',
+	"restarting": '↻',
+}
+
+//ASCIIStateGlyphs are the glyphs shown for each container state on terminals
+//that do not support unicode
+var ASCIIStateGlyphs = StateGlyphs{
+	"running":    '>',
+	"paused":     '|',
+	"exited":     '.',
+	"restarting": '~',
+}
+
+//prefix returns the glyph of the state of the given container followed by a
+//space, an empty string if there is no glyph for the container state.
+func (g StateGlyphs) prefix(c *types.Container) string {
+	if glyph, ok := g[containerState(c)]; ok {
+		return string(glyph) + " "
+	}
+	return ""
+}
+
+//containerState returns the state of the given container, if the daemon does not
+//report it, the state is guessed from the container status.
+func containerState(c *types.Container) string {
+	if c.State != "" {
+		return c.State
+	}
+	switch status := strings.ToLower(c.Status); {
+	case strings.Contains(status, "paused"):
+		return "paused"
+	case strings.HasPrefix(status, "restarting"):
+		return "restarting"
+	case strings.HasPrefix(status, "up"):
+		return "running"
+	case strings.HasPrefix(status, "exited"):
+		return "exited"
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package appui
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestStateGlyphs(t *testing.T) {
+	var tests = []struct {
+		container *types.Container
+		glyphs    StateGlyphs
+		expected  string
+	}{
+		{&types.Container{State: "running"}, UnicodeStateGlyphs, "▶ "},
+		{&types.Container{State: "paused"}, ASCIIStateGlyphs, "| "},
+		{&types.Container{Status: "Up 2 hours (Paused)"}, UnicodeStateGlyphs, "⏸ "},
+		{&types.Container{Status: "Exited (0) 3 days ago"}, UnicodeStateGlyphs, "⏹ "},
+		{&types.Container{Status: "Restarting (1) 2 seconds ago"}, ASCIIStateGlyphs, "~ "},
+		{&types.Container{State: "created"}, UnicodeStateGlyphs, ""},
+		{&types.Container{State: "running"}, nil, ""},
+	}
+	for _, test := range tests {
+		if prefix := test.glyphs.prefix(test.container); prefix != test.expected {
+			t.Errorf("Unexpected glyph for %+v. Expected: %q, got: %q", test.container, test.expected, prefix)
+		}
+	}
+}
+
+func TestStatsRowNameWithStateGlyph(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"/Name"}, State: "running", Status: "Up 1 second"}
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: make(chan *docker.Stats)},
+		&StatsRowOptions{StateGlyphs: ASCIIStateGlyphs})
+	if row.Name.Text != "> Name" {
+		t.Errorf("Unexpected name column content. Expected: %s, got: %s", "> Name", row.Name.Text)
+	}
+}
@@ -51,6 +51,8 @@ func (r *statsRenderer) Render() string {
 	if processList != nil {
 		topRenderer := NewDockerTopRenderer(processList)
 		io.WriteString(w, topRenderer.Render())
+	} else if s.TopUnsupported {
+		io.WriteString(w, "<yellow><b>PROCESS LIST</></>\n\n<white>"+topUnsupportedText+"</>\n")
 	}
 	w.Flush()
 	return buf.String()
@@ -59,7 +61,7 @@ func (r *statsRenderer) Render() string {
 //NewDockerStatsBufferer creates termui bufferer for docker stats
 func NewDockerStatsBufferer(stats *drydocker.Stats, x, y, height, width int) []termui.Bufferer {
 	var result []termui.Bufferer
-	top, length := NewDockerTopBufferer(stats.ProcessList, 0, y, height-statsHeight, width)
+	top, length := processListBufferer(stats, 0, y, height-statsHeight, width)
 	result = append(result,
 		top)
 	yPos := y + length
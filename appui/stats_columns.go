@@ -0,0 +1,464 @@
+package appui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/docker"
+	drytermui "github.com/moncho/dry/ui/termui"
+)
+
+//statsColumn is a column of the container monitor, it knows its title
+//and how to get, from a row, the widget that renders it.
+//If there is no room to show all the columns, the ones with lower priority
+//are dropped first.
+type statsColumn struct {
+	title    string
+	priority int
+	widget   func(row *ContainerStatsRow) termui.GridBufferer
+}
+
+var (
+	hostColumn = statsColumn{"HOST", 5,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Host }}
+	idColumn = statsColumn{"CONTAINER", 8,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.ID }}
+	nameColumn = statsColumn{"NAME", 10,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Name }}
+	imageColumn = statsColumn{"IMAGE", 5,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Image }}
+	cpuColumn = statsColumn{"CPU", 9,
+		func(row *ContainerStatsRow) termui.GridBufferer { return newGaugeCell(row.CPU) }}
+	cpuAverageColumn = statsColumn{"AVG CPU", 2,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.CPUAverage }}
+	cpuSplitColumn = statsColumn{"USER/SYS", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.CPUSplit }}
+	memColumn = statsColumn{"MEM", 7,
+		func(row *ContainerStatsRow) termui.GridBufferer { return newGaugeCell(row.Memory) }}
+	hostMemColumn = statsColumn{"MEM HOST", 6,
+		func(row *ContainerStatsRow) termui.GridBufferer { return newGaugeCell(row.HostMemory) }}
+	cpuLimitColumn = statsColumn{"CPU LIMIT", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.CPULimit }}
+	memLimitColumn = statsColumn{"MEM LIMIT", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.MemoryLimit }}
+	restartPolicyColumn = statsColumn{"RESTART", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.RestartPolicy }}
+	cpuSetColumn = statsColumn{"CPUSET", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.CPUSet }}
+	noFileColumn = statsColumn{"NOFILE", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.NoFile }}
+	swarmReplicaColumn = statsColumn{"REPLICA", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.SwarmReplica }}
+	startedAtColumn = statsColumn{"STARTED AT", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.StartedAt }}
+	writableLayerColumn = statsColumn{"SIZE RW", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.WritableLayer }}
+	noteColumn = statsColumn{"NOTE", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Note }}
+	netColumn = statsColumn{"NET RX/TX", 6,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Net }}
+	blockColumn = statsColumn{"BLOCK I/O", 4,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Block }}
+	netRateColumn = statsColumn{"NET RX/TX /s", 6,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Net }}
+	blockRateColumn = statsColumn{"BLOCK I/O /s", 4,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Block }}
+	iopsColumn = statsColumn{"IOPS R/W", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.IOPS }}
+	pidsColumn = statsColumn{"PIDS", 3,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Pids }}
+	portsColumn = statsColumn{"PORTS", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Ports }}
+	fdsColumn = statsColumn{"FDS", 1,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.FDs }}
+	updatedColumn = statsColumn{"UPDATED", 2,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Updated }}
+)
+
+//labelColumnPriority is the priority of label columns, they are the first to be dropped
+const labelColumnPriority = 1
+
+//labelColumn returns a column showing the value of the container label with the given key
+func labelColumn(key string) statsColumn {
+	return statsColumn{strings.ToUpper(key), labelColumnPriority,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Labels[key] }}
+}
+
+//DerivedColumn is a column whose value is calculated, on each stats sample, with
+//an expression over the sample fields, i.e. "mem / pids". Derived columns are
+//created with AddDerivedColumn, the expression of any other is not evaluated.
+type DerivedColumn struct {
+	Name       string
+	Expression string
+	expression expression
+}
+
+//derivedColumn returns a column showing the value of the given derived column
+func derivedColumn(d *DerivedColumn) statsColumn {
+	return statsColumn{strings.ToUpper(d.Name), labelColumnPriority,
+		func(row *ContainerStatsRow) termui.GridBufferer { return row.Derived[d.Name] }}
+}
+
+//StatsRowOptions defines what is shown on the rows of the container monitor
+type StatsRowOptions struct {
+	//ShowHost adds a column showing the host of the container, it is always
+	//shown when more than one host is monitored.
+	ShowHost bool
+	//ShowUpdated adds a column showing how long ago the last sample of a
+	//container was received.
+	ShowUpdated bool
+	//ShowCPUAverage adds a column showing the average CPU usage of the
+	//last minute.
+	ShowCPUAverage bool
+	//ShowCPUSplit adds a column showing the percentages of the CPU time spent
+	//in user and in kernel mode
+	ShowCPUSplit bool
+	//CPUSmoothing and MemorySmoothing are the smoothing factors (alpha) of the
+	//exponential moving average applied to the CPU and memory gauges. The
+	//default, 1, and any value outside (0, 1] disable smoothing.
+	CPUSmoothing    float64
+	MemorySmoothing float64
+	//CPUGaugeScale is the CPU usage, in percentage of a core, shown as a full CPU
+	//gauge, so gauges are comparable across containers, i.e. 400 on a host with
+	//four cores. DefaultCPUGaugeScale, a full core, is used if not set.
+	CPUGaugeScale float64
+	//CPUGaugeHostScale makes a full CPU gauge the usage of every core of the
+	//host of the container, it overrides CPUGaugeScale.
+	CPUGaugeHostScale bool
+	//ShowHostMemory adds, next to the memory gauge, that shows the usage as a
+	//percentage of the container limit, a gauge showing it as a percentage of
+	//the memory of the host of the container.
+	ShowHostMemory bool
+	//ShowRawValues makes the gauge labels show the last sample received
+	//instead of the smoothed value.
+	ShowRawValues bool
+	//ColorMargin is the margin, in percentage points, that a gauge value has to
+	//drop below a color threshold for the gauge to change its color back.
+	ColorMargin int
+	//ColumnSeparator, if set, is shown between the columns of the monitor
+	ColumnSeparator rune
+	//AlternateRowShading renders alternate rows with the AltBg color of the theme
+	AlternateRowShading bool
+	//ShowKeyHints shows, on the last line of the monitor, hints of the keys
+	//that can be used, see Monitor.SetKeyHints
+	ShowKeyHints bool
+	//NetworkInterfaces shows the network IO of each network interface of the
+	//container, containers with a single interface show its IO with no interface
+	//name unless ExpandNetworkInterfaces is set.
+	NetworkInterfaces       bool
+	ExpandNetworkInterfaces bool
+	//IOByteFormat formats the network and block IO values, units.BytesSize is
+	//used if not set. HumanSizeWithPrecision shows small values with more detail.
+	IOByteFormat ByteFormat
+	//IODisplay decides whether the network and block IO columns show totals,
+	//the default, or rates.
+	IODisplay IODisplay
+	//IDDisplay decides how long the container IDs shown are, the ID column
+	//widens to fit medium and full IDs.
+	IDDisplay IDDisplay
+	//ShowStopped shows, besides running containers, the containers that are not running
+	ShowStopped bool
+	//GroupStopped places the stopped containers, and those whose stats stream has
+	//ended, after the running ones, collapsed on a single row unless StoppedExpanded
+	GroupStopped bool
+	//StoppedExpanded shows the rows of the stopped containers grouped by GroupStopped
+	StoppedExpanded bool
+	//GroupByImage shows, instead of a row per container, a row per image with
+	//the totals of its containers.
+	GroupByImage bool
+	//InfraClassifier identifies infrastructure containers, such as the pause
+	//containers of Kubernetes pods, InfraDisplay decides how they are shown.
+	//No container is identified as infrastructure if not set.
+	InfraClassifier *docker.InfraClassifier
+	InfraDisplay    InfraDisplay
+	//GroupByPIDNamespace shows together the containers sharing a PID namespace,
+	//either the one of another container or the one of the host.
+	GroupByPIDNamespace bool
+	//OOMRiskMargin is the percentage of the memory limit that the working set,
+	//the memory used that is not page cache, of a container has to stay over
+	//for OOMRiskPeriod for the container to be shown at risk of running out of
+	//memory. DefaultOOMRiskMargin and DefaultOOMRiskPeriod are used if not set.
+	OOMRiskMargin float64
+	OOMRiskPeriod time.Duration
+	//HistorySize is the number of samples kept on the stats history of each
+	//row, DefaultHistorySize is used if not set.
+	HistorySize int
+	//Histories, if set, keeps the stats history of each container so rows created
+	//again for a container, i.e. when the monitor is rebuilt, keep its history.
+	Histories *StatsHistories
+	//StaleTimeout is the time after which a row whose stats stream has not
+	//sent a sample is shown as stale, DefaultStaleTimeout is used if not set.
+	StaleTimeout time.Duration
+	//Watchdog decides when the monitor, as a last resort, opens again every
+	//stats stream because too many of them fail, it is disabled if not set.
+	Watchdog docker.WatchdogPolicy
+	//MaxStreams, if set, is the maximum number of stats streams opened on each
+	//host, the stats of the rest of running containers are polled in turns, so
+	//they are shown less often.
+	MaxStreams int
+	//MaxRows, if set, is the maximum number of containers shown, following the
+	//order of the monitor, the totals of the rest of containers are shown on a
+	//single row.
+	MaxRows int
+	//ScrollPolicy decides where the selected row is kept when the monitor
+	//scrolls, i.e. centered
+	ScrollPolicy drytermui.ScrollPolicy
+	//ShowLimits adds columns showing the CPU and memory limits of the container
+	ShowLimits bool
+	//ShowIOPS adds a column showing the block IO read and write operations
+	//per second of the container
+	ShowIOPS bool
+	//ShowCPUSet adds a column showing the CPUs the container is pinned to
+	ShowCPUSet bool
+	//ShowSwarmReplica adds a column showing the swarm service and the replica
+	//slot of the task of the container, i.e. web.2
+	ShowSwarmReplica bool
+	//ShowStartedAt adds a column showing when the container was last started,
+	//formatted with StartedAtLayout, DefaultStartedAtLayout is used if not set.
+	ShowStartedAt   bool
+	StartedAtLayout string
+	//ShowRestartPolicy adds a column showing the restart policy of the container
+	ShowRestartPolicy bool
+	//ShowNoFile adds a column showing the soft and hard limits of open files
+	//of the container, if set, or "default"
+	ShowNoFile bool
+	//ShowWritableLayer adds a column showing the size of the writable layer of
+	//the container. Sizes are expensive to calculate for the Docker daemon.
+	ShowWritableLayer bool
+	//Notes, if set, are the notes about containers shown on the detail of the rows
+	Notes *docker.ContainerNotes
+	//ShowNotes adds a column showing the note about the container, see Notes
+	ShowNotes bool
+	//ShowPorts adds a column showing the ports of the container
+	ShowPorts bool
+	//ShowOpenFDs adds a column showing the number of open file descriptors
+	//of the container main process, only available if dry runs on the Docker host.
+	ShowOpenFDs bool
+	//NameDisplay decides how container names are shown, i.e. as the Compose
+	//service of the container
+	NameDisplay docker.NameDisplay
+	//StateGlyphs, if set, are used to show the state of the container as a
+	//glyph before its name, ASCIIStateGlyphs can be used on terminals with no
+	//unicode support.
+	StateGlyphs StateGlyphs
+	//Labels are the keys of the container labels shown, a column is
+	//added for each one of them.
+	Labels []string
+	//DerivedColumns are user-defined columns, see AddDerivedColumn
+	DerivedColumns []*DerivedColumn
+}
+
+//optionalColumns maps the name of each optional column of the monitor, as
+//given to ShowColumns, to the option that shows it
+var optionalColumns = map[string]func(o *StatsRowOptions){
+	"host":       func(o *StatsRowOptions) { o.ShowHost = true },
+	"updated":    func(o *StatsRowOptions) { o.ShowUpdated = true },
+	"cpu-avg":    func(o *StatsRowOptions) { o.ShowCPUAverage = true },
+	"cpu-split":  func(o *StatsRowOptions) { o.ShowCPUSplit = true },
+	"mem-host":   func(o *StatsRowOptions) { o.ShowHostMemory = true },
+	"limits":     func(o *StatsRowOptions) { o.ShowLimits = true },
+	"iops":       func(o *StatsRowOptions) { o.ShowIOPS = true },
+	"cpuset":     func(o *StatsRowOptions) { o.ShowCPUSet = true },
+	"replica":    func(o *StatsRowOptions) { o.ShowSwarmReplica = true },
+	"started-at": func(o *StatsRowOptions) { o.ShowStartedAt = true },
+	"restart":    func(o *StatsRowOptions) { o.ShowRestartPolicy = true },
+	"nofile":     func(o *StatsRowOptions) { o.ShowNoFile = true },
+	"size-rw":    func(o *StatsRowOptions) { o.ShowWritableLayer = true },
+	"note":       func(o *StatsRowOptions) { o.ShowNotes = true },
+	"ports":      func(o *StatsRowOptions) { o.ShowPorts = true },
+	"fds":        func(o *StatsRowOptions) { o.ShowOpenFDs = true },
+}
+
+//OptionalColumns returns the names of the optional columns of the monitor, sorted
+func OptionalColumns() []string {
+	var names []string
+	for name := range optionalColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//ShowColumns shows the optional columns with the given names, see OptionalColumns.
+//An error is returned, and no column is shown, if a name is not known.
+func (o *StatsRowOptions) ShowColumns(names ...string) error {
+	for _, name := range names {
+		if _, ok := optionalColumns[name]; !ok {
+			return fmt.Errorf("Unknown monitor column %s, expected one of: %s",
+				name, strings.Join(OptionalColumns(), ", "))
+		}
+	}
+	for _, name := range names {
+		optionalColumns[name](o)
+	}
+	return nil
+}
+
+//AddDerivedColumn adds a column with the given name showing the value of the given
+//expression. Expressions use the +, -, * and / operators, parentheses, numbers and
+//the fields: cpu, mem, mem_limit, mem_percent, net_rx, net_tx, block_read,
+//block_write and pids. An error is returned if the expression is not valid.
+func (o *StatsRowOptions) AddDerivedColumn(name, expr string) error {
+	if name == "" {
+		return fmt.Errorf("Derived column has no name")
+	}
+	for _, d := range o.DerivedColumns {
+		if d.Name == name {
+			return fmt.Errorf("There is already a derived column named %s", name)
+		}
+	}
+	e, err := parseExpression(expr)
+	if err != nil {
+		return fmt.Errorf("Invalid expression for column %s: %s", name, err.Error())
+	}
+	o.DerivedColumns = append(o.DerivedColumns, &DerivedColumn{Name: name, Expression: expr, expression: e})
+	return nil
+}
+
+//SetCPUGaugeScale sets the CPU usage shown as a full CPU gauge: "core" for a
+//full core, "host" for every core of the host, or a percentage of a core, i.e.
+//200 for two cores. An error is returned if the scale is not valid.
+func (o *StatsRowOptions) SetCPUGaugeScale(scale string) error {
+	switch scale {
+	case "core":
+		o.CPUGaugeScale, o.CPUGaugeHostScale = DefaultCPUGaugeScale, false
+	case "host":
+		o.CPUGaugeScale, o.CPUGaugeHostScale = DefaultCPUGaugeScale, true
+	default:
+		value, err := strconv.ParseFloat(scale, 64)
+		if err != nil || value <= 0 {
+			return fmt.Errorf("Invalid CPU gauge scale %s, expected core, host or a percentage of a core", scale)
+		}
+		o.CPUGaugeScale, o.CPUGaugeHostScale = value, false
+	}
+	return nil
+}
+
+//DefaultCPUGaugeScale is the CPU usage shown as a full CPU gauge, a full core
+const DefaultCPUGaugeScale = 100
+
+//DefaultStartedAtLayout is the default layout of the time shown on the started at column
+const DefaultStartedAtLayout = "2006-01-02 15:04"
+
+//DefaultStaleTimeout is the default time after which a row with no new
+//samples is shown as stale
+const DefaultStaleTimeout = 3 * docker.StatsInterval
+
+//DefaultStatsRowOptions are the options used to create ContainerStatsRow(s)
+var DefaultStatsRowOptions = &StatsRowOptions{
+	CPUSmoothing:    1,
+	MemorySmoothing: 1,
+	ColorMargin:     DefaultColorMargin,
+	StaleTimeout:    DefaultStaleTimeout,
+	InfraClassifier: docker.DefaultInfraClassifier,
+	Histories:       NewStatsHistories(),
+	Watchdog:        docker.DefaultWatchdogPolicy}
+
+//header returns the header of the columns to show
+func (o *StatsRowOptions) header() *monitorTableHeader {
+	header := newMonitorTableHeader(o.columns()...)
+	header.separator = columnSeparator(o.ColumnSeparator)
+	header.sizes = columnSizes(header.columns, o.IDDisplay)
+	return header
+}
+
+//columns returns the columns to show, following the order in which they are rendered
+func (o *StatsRowOptions) columns() []statsColumn {
+	var columns []statsColumn
+	if o.ShowHost {
+		columns = append(columns, hostColumn)
+	}
+	columns = append(columns,
+		idColumn,
+		nameColumn,
+		imageColumn,
+	)
+	for _, key := range o.Labels {
+		columns = append(columns, labelColumn(key))
+	}
+	if o.ShowPorts {
+		columns = append(columns, portsColumn)
+	}
+	if o.ShowSwarmReplica {
+		columns = append(columns, swarmReplicaColumn)
+	}
+	if o.ShowStartedAt {
+		columns = append(columns, startedAtColumn)
+	}
+	if o.ShowRestartPolicy {
+		columns = append(columns, restartPolicyColumn)
+	}
+	if o.ShowNoFile {
+		columns = append(columns, noFileColumn)
+	}
+	if o.ShowWritableLayer {
+		columns = append(columns, writableLayerColumn)
+	}
+	if o.ShowNotes {
+		columns = append(columns, noteColumn)
+	}
+	columns = append(columns, cpuColumn)
+	if o.ShowCPUAverage {
+		columns = append(columns, cpuAverageColumn)
+	}
+	if o.ShowCPUSplit {
+		columns = append(columns, cpuSplitColumn)
+	}
+	if o.ShowLimits {
+		columns = append(columns, cpuLimitColumn)
+	}
+	if o.ShowCPUSet {
+		columns = append(columns, cpuSetColumn)
+	}
+	columns = append(columns, memColumn)
+	if o.ShowHostMemory {
+		columns = append(columns, hostMemColumn)
+	}
+	if o.ShowLimits {
+		columns = append(columns, memLimitColumn)
+	}
+	if o.IODisplay == IOPerSecond {
+		columns = append(columns, netRateColumn, blockRateColumn)
+	} else {
+		columns = append(columns, netColumn, blockColumn)
+	}
+	if o.ShowIOPS {
+		columns = append(columns, iopsColumn)
+	}
+	columns = append(columns, pidsColumn)
+	if o.ShowOpenFDs {
+		columns = append(columns, fdsColumn)
+	}
+	for _, d := range o.DerivedColumns {
+		columns = append(columns, derivedColumn(d))
+	}
+	if o.ShowUpdated {
+		columns = append(columns, updatedColumn)
+	}
+	return columns
+}
+
+//columnSizes returns the width needed by each of the given columns, 0 for the
+//columns that share the available width, when IDs are shown as given
+func columnSizes(columns []statsColumn, ids IDDisplay) []int {
+	sizes := make([]int, len(columns))
+	for i, c := range columns {
+		if c.title == idColumn.title && c.priority == idColumn.priority {
+			sizes[i] = ids.width()
+		}
+	}
+	return sizes
+}
+
+//priorities returns the priority of each of the given columns
+func priorities(columns []statsColumn) []int {
+	var priorities []int
+	for _, c := range columns {
+		priorities = append(priorities, c.priority)
+	}
+	return priorities
+}
@@ -0,0 +1,174 @@
+package appui
+
+import (
+	"sync"
+	"time"
+
+	"github.com/moncho/dry/docker"
+)
+
+//DefaultHistorySize is the default number of samples kept on the stats history of a row
+const DefaultHistorySize = 300
+
+//StatsPoint is a point of the stats history of a container, rates are in
+//bytes per second
+type StatsPoint struct {
+	Time             time.Time
+	CPUPercentage    float64
+	MemoryPercentage float64
+	NetworkRate      float64
+	BlockIORate      float64
+}
+
+//StatsHistory is a time series of the stats of a container, points are kept
+//on a fixed-size ring so only the last points are kept.
+type StatsHistory struct {
+	points []StatsPoint
+	next   int
+	count  int
+	sync.RWMutex
+}
+
+//NewStatsHistory creates a history keeping the given number of points,
+//DefaultHistorySize if the size is not positive
+func NewStatsHistory(size int) *StatsHistory {
+	return &StatsHistory{points: make([]StatsPoint, historySize(size))}
+}
+
+//historySize returns the given history size, DefaultHistorySize if it is not positive
+func historySize(size int) int {
+	if size <= 0 {
+		return DefaultHistorySize
+	}
+	return size
+}
+
+//Add adds the given point, replacing the oldest one if the ring is full
+func (h *StatsHistory) Add(p StatsPoint) {
+	h.Lock()
+	defer h.Unlock()
+	h.points[h.next] = p
+	h.next = (h.next + 1) % len(h.points)
+	if h.count < len(h.points) {
+		h.count++
+	}
+}
+
+//Points returns a copy of the points of the history, oldest first
+func (h *StatsHistory) Points() []StatsPoint {
+	h.RLock()
+	defer h.RUnlock()
+	points := make([]StatsPoint, 0, h.count)
+	start := (h.next - h.count + len(h.points)) % len(h.points)
+	for i := 0; i < h.count; i++ {
+		points = append(points, h.points[(start+i)%len(h.points)])
+	}
+	return points
+}
+
+//Len returns the number of points of the history
+func (h *StatsHistory) Len() int {
+	h.RLock()
+	defer h.RUnlock()
+	return h.count
+}
+
+//resize changes the number of points kept by the history, the last points
+//are kept. DefaultHistorySize is used if the size is not positive.
+func (h *StatsHistory) resize(size int) {
+	size = historySize(size)
+	points := h.Points()
+	h.Lock()
+	defer h.Unlock()
+	if len(points) > size {
+		points = points[len(points)-size:]
+	}
+	h.points = make([]StatsPoint, size)
+	copy(h.points, points)
+	h.count = len(points)
+	h.next = h.count % size
+}
+
+//size returns the number of points kept by the history
+func (h *StatsHistory) size() int {
+	h.RLock()
+	defer h.RUnlock()
+	return len(h.points)
+}
+
+//Reset removes every point from the history
+func (h *StatsHistory) Reset() {
+	h.Lock()
+	defer h.Unlock()
+	h.next = 0
+	h.count = 0
+}
+
+//StatsHistories keeps the stats history of each container, by container ID, so
+//the history of a container survives the rows showing it. Histories of containers
+//that are no longer listed are removed with retain.
+type StatsHistories struct {
+	histories map[string]*StatsHistory
+	sync.Mutex
+}
+
+//NewStatsHistories creates an empty StatsHistories
+func NewStatsHistories() *StatsHistories {
+	return &StatsHistories{histories: make(map[string]*StatsHistory)}
+}
+
+//history returns the history of the container with the given ID, a new history
+//keeping the given number of points is created if the container has none, and
+//the existing one is resized if it keeps a different number of points.
+//With no StatsHistories every call returns a new history.
+func (s *StatsHistories) history(id string, size int) *StatsHistory {
+	if s == nil {
+		return NewStatsHistory(size)
+	}
+	s.Lock()
+	defer s.Unlock()
+	h, ok := s.histories[id]
+	if !ok {
+		h = NewStatsHistory(size)
+		s.histories[id] = h
+	} else if h.size() != historySize(size) {
+		h.resize(size)
+	}
+	return h
+}
+
+//retain removes the histories of every container but the ones with the given IDs
+func (s *StatsHistories) retain(ids map[string]bool) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	for id := range s.histories {
+		if !ids[id] {
+			delete(s.histories, id)
+		}
+	}
+}
+
+//Len returns the number of containers with a history
+func (s *StatsHistories) Len() int {
+	if s == nil {
+		return 0
+	}
+	s.Lock()
+	defer s.Unlock()
+	return len(s.histories)
+}
+
+//statsPoint returns the history point of the given sample, received at the
+//given time, and the IO rates calculated with it
+func statsPoint(stat *docker.Stats, rates IORates, now time.Time) StatsPoint {
+	return StatsPoint{
+		Time:             now,
+		CPUPercentage:    stat.CPUPercentage,
+		MemoryPercentage: stat.MemoryPercentage,
+		NetworkRate:      rates.NetworkRx + rates.NetworkTx,
+		BlockIORate:      rates.BlockRead + rates.BlockWrite,
+	}
+}
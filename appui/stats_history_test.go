@@ -0,0 +1,94 @@
+package appui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestStatsHistory(t *testing.T) {
+	h := NewStatsHistory(3)
+	if h.Len() != 0 || len(h.Points()) != 0 {
+		t.Errorf("New history has points: %d", h.Len())
+	}
+	for i := 1; i <= 5; i++ {
+		h.Add(StatsPoint{CPUPercentage: float64(i)})
+	}
+	points := h.Points()
+	if h.Len() != 3 || len(points) != 3 {
+		t.Fatalf("History is not bounded, points: %d", len(points))
+	}
+	for i, expected := range []float64{3, 4, 5} {
+		if points[i].CPUPercentage != expected {
+			t.Errorf("Unexpected point %d. Expected: %f, got: %f", i, expected, points[i].CPUPercentage)
+		}
+	}
+	h.Reset()
+	if h.Len() != 0 {
+		t.Errorf("History has points after a reset: %d", h.Len())
+	}
+	if NewStatsHistory(0).points == nil || len(NewStatsHistory(-1).points) != DefaultHistorySize {
+		t.Error("History with no size does not use the default size")
+	}
+}
+
+func TestStatsRowHistory(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited"}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{HistorySize: 2})
+	now := time.Now()
+	row.update(&docker.Stats{CPUPercentage: 10, MemoryPercentage: 20, NetworkRx: 0}, now)
+	row.update(&docker.Stats{CPUPercentage: 30, MemoryPercentage: 40, NetworkRx: 2048}, now.Add(2*time.Second))
+
+	history := row.History()
+	if len(history) != 2 {
+		t.Fatalf("Unexpected number of points: %d", len(history))
+	}
+	last := history[1]
+	if !last.Time.Equal(now.Add(2*time.Second)) || last.CPUPercentage != 30 || last.MemoryPercentage != 40 || last.NetworkRate != 1024 {
+		t.Errorf("Unexpected last point: %+v", last)
+	}
+	row.ResetStats()
+	if len(row.History()) != 0 {
+		t.Error("History was not reset with the rest of statistics")
+	}
+}
+
+func TestStatsHistoriesKeepTheHistoryOfEachContainer(t *testing.T) {
+	options := &StatsRowOptions{HistorySize: 2, Histories: NewStatsHistories()}
+	web := &types.Container{ID: "web", Names: []string{"web"}, Status: "Exited"}
+	db := &types.Container{ID: "db", Names: []string{"db"}, Status: "Exited"}
+	row := NewContainerStatsRowFor(web, options)
+	row.update(&docker.Stats{CPUPercentage: 10}, time.Now())
+
+	if history := NewContainerStatsRowFor(web, options).History(); len(history) != 1 || history[0].CPUPercentage != 10 {
+		t.Errorf("The history of a container was not kept on a new row: %+v", history)
+	}
+	if history := NewContainerStatsRowFor(db, options).History(); len(history) != 0 {
+		t.Errorf("Unexpected history of another container: %+v", history)
+	}
+	if history := NewContainerStatsRowFor(web, &StatsRowOptions{}).History(); len(history) != 0 {
+		t.Errorf("Unexpected history with no histories kept: %+v", history)
+	}
+}
+
+func TestStatsHistoriesResizeHistories(t *testing.T) {
+	histories := NewStatsHistories()
+	history := histories.history("web", 3)
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		history.Add(StatsPoint{Time: now, CPUPercentage: float64(i)})
+	}
+
+	if h := histories.history("web", 2); h != history || len(h.Points()) != 2 || h.Points()[0].CPUPercentage != 2 {
+		t.Errorf("The history was not shrunk keeping its last points: %+v", h.Points())
+	}
+	history.Add(StatsPoint{Time: now, CPUPercentage: 4})
+	if points := history.Points(); len(points) != 2 || points[1].CPUPercentage != 4 {
+		t.Errorf("Unexpected points after shrinking the history: %+v", points)
+	}
+	if h := histories.history("web", 4); h.size() != 4 || len(h.Points()) != 2 {
+		t.Errorf("The history was not grown keeping its points, size: %d, points: %+v", h.size(), h.Points())
+	}
+}
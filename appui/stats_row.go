@@ -2,7 +2,11 @@ package appui
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	units "github.com/docker/go-units"
@@ -12,81 +16,617 @@ import (
 	drytermui "github.com/moncho/dry/ui/termui"
 )
 
+//oomMarker is shown before the name of containers killed for running out of memory
+const oomMarker = "OOM "
+
+//staleMarker is shown before the name of containers whose stats stream is
+//open but has not sent a sample in the stale timeout
+const staleMarker = "STALE "
+
+//writableLayerWarningSize and writableLayerAlertSize are the sizes, in bytes, from
+//which the size of the writable layer of a container is highlighted
+const (
+	writableLayerWarningSize = 100 * 1024 * 1024
+	writableLayerAlertSize   = 1024 * 1024 * 1024
+)
+
+//cpuAverageSamples is the number of samples used to calculate the average
+//CPU usage, at one sample per second it is the average of the last minute.
+const cpuAverageSamples = 60
+
 //ContainerStatsRow is a Grid row showing runtime information about a container
 type ContainerStatsRow struct {
-	container *types.Container
-	Name      *drytermui.ParColumn
-	ID        *drytermui.ParColumn
-	CPU       *drytermui.GaugeColumn
-	Memory    *drytermui.GaugeColumn
-	Net       *drytermui.ParColumn
-	Block     *drytermui.ParColumn
-	Pids      *drytermui.ParColumn
-	X, Y      int
-	Width     int
-	Height    int
-	columns   []termui.GridBufferer
+	container  *types.Container
+	Host       *drytermui.ParColumn
+	Name       *drytermui.ParColumn
+	ID         *drytermui.ParColumn
+	Image      *drytermui.ParColumn
+	CPU        *drytermui.GaugeColumn
+	CPUAverage *drytermui.ParColumn
+	Memory     *drytermui.GaugeColumn
+	//CPUSplit shows the percentages of CPU time in user and kernel mode
+	CPUSplit *drytermui.ParColumn
+	//HostMemory shows the memory usage as a percentage of the host memory
+	HostMemory *drytermui.GaugeColumn
+	//CPULimit and MemoryLimit show the resource limits of the container
+	CPULimit    *drytermui.ParColumn
+	MemoryLimit *drytermui.ParColumn
+	//RestartPolicy shows the restart policy of the container
+	RestartPolicy *drytermui.ParColumn
+	//CPUSet shows the CPUs the container is pinned to
+	CPUSet *drytermui.ParColumn
+	//StartedAt shows when the container was last started
+	StartedAt *drytermui.ParColumn
+	//NoFile shows the limits of open files of the container
+	NoFile *drytermui.ParColumn
+	//SwarmReplica shows the swarm service and replica slot of the container
+	SwarmReplica *drytermui.ParColumn
+	//WritableLayer shows the size of the writable layer of the container
+	WritableLayer *drytermui.ParColumn
+	//Note shows the note about the container
+	Note  *drytermui.ParColumn
+	Net   *drytermui.ParColumn
+	Block *drytermui.ParColumn
+	//IOPS shows the block IO operations per second
+	IOPS    *drytermui.ParColumn
+	Pids    *drytermui.ParColumn
+	FDs     *drytermui.ParColumn
+	Ports   *drytermui.ParColumn
+	Updated *drytermui.ParColumn
+	//Labels holds a column for each container label shown, by label key
+	Labels map[string]*drytermui.ParColumn
+	//Derived holds a column for each derived column, by name
+	Derived        map[string]*drytermui.ParColumn
+	derivedColumns []*DerivedColumn
+	X, Y           int
+	Width          int
+	Height         int
+	columns        []termui.GridBufferer
+	separator      columnSeparator
+	separators     []int
+	//priority, needed width and visibility of each column
+	priorities []int
+	sizes      []int
+	visible    []bool
+	//definitions of the columns shown
+	definitions []statsColumn
+	//time at which the last stats sample was received
+	lastUpdated time.Time
+	//last stats sample received and not yet shown, it is applied
+	//to the row columns on render
+	pending *docker.Stats
+	//last stats sample received and the IO rates calculated with it
+	latest     *docker.Stats
+	rates      IORates
+	rateCalc   rateCalculator
+	cpuAverage *movingAverage
+	//smoothed values shown on the gauges
+	cpuSmoothed    *expMovingAverage
+	memSmoothed    *expMovingAverage
+	memPctSmoothed *expMovingAverage
+	showRawValues  bool
+	//network IO is shown per interface
+	networkInterfaces bool
+	expandInterfaces  bool
+	ioFormat          ByteFormat
+	ioDisplay         IODisplay
+	//color levels of the gauges
+	cpuColor gaugeColor
+	//cpuScale is the CPU usage shown as a full CPU gauge
+	cpuScale float64
+	memColor gaugeColor
+	//hostMemory is the memory of the host of the container, in bytes, 0 if unknown
+	hostMemory   int64
+	hostMemColor gaugeColor
+	//ports of the container, shown truncated on the Ports column
+	ports string
+	//history are the last samples received, as a time series
+	history *StatsHistory
+	//startedAtLayout is the layout of the time shown on the StartedAt column
+	startedAtLayout string
+	//pidMode is the PID namespace of the container, see docker.ContainerLimits
+	pidMode string
+	//detail is shown below the row when the row is expanded
+	detail   *rowDetail
+	expanded bool
+	//state of the columns, by column widget
+	states map[termui.Bufferer]columnState
+	//streamEnded is true once the stats channel of the container is closed,
+	//usually because the container has stopped
+	streamEnded bool
+	endChecked  bool
+	//oomKilled is true if the container was killed for running out of memory
+	oomKilled bool
+	//atRisk is true if the container is at risk of running out of memory,
+	//riskShown if the row shows it
+	oomRisk   *oomRisk
+	atRisk    bool
+	riskShown bool
+	//stale is true if no sample has been received in staleTimeout
+	stale        bool
+	staleTimeout time.Duration
+	//polledEvery is the time between two samples of a container whose stats
+	//are polled in turns with the ones of other containers, zero if not polled
+	polledEvery time.Duration
+	//sampledEvery is the time until the next sample, as the polling policy
+	//decided on the last sample, zero if samples are streamed
+	sampledEvery time.Duration
+	//odd is true if the row is on an odd position of the grid page
+	odd bool
+	sync.RWMutex
 }
 
 //NewContainerStatsRow creates a ContainerStatsRow for the given container
+//using the default options.
 func NewContainerStatsRow(s *docker.StatsChannel) *ContainerStatsRow {
-	c := s.Container
+	return NewContainerStatsRowWithOptions(s, DefaultStatsRowOptions)
+}
+
+//NewContainerStatsRowWithOptions creates a ContainerStatsRow for the given container
+//showing what the given options define. The row is updated with the samples
+//received on the stats channel.
+func NewContainerStatsRowWithOptions(s *docker.StatsChannel, options *StatsRowOptions) *ContainerStatsRow {
+	row := NewContainerStatsRowFor(s.Container, options)
+	if docker.IsContainerRunning(s.Container) {
+		go func() {
+			for stat := range s.Stats {
+				row.Update(stat)
+			}
+			row.Lock()
+			row.streamEnded = true
+			row.Unlock()
+		}()
+	}
+	return row
+}
+
+//NewContainerStatsRowFor creates a ContainerStatsRow for the given container showing
+//what the given options define. No stats are collected, the row shows the samples
+//given to Update.
+func NewContainerStatsRowFor(c *types.Container, options *StatsRowOptions) *ContainerStatsRow {
 	cf := docker.NewContainerFormatter(c, true)
 	row := &ContainerStatsRow{
-		container: c,
-		Name:      drytermui.NewThemedParColumn(DryTheme, cf.Names()),
-		ID:        drytermui.NewThemedParColumn(DryTheme, cf.ID()),
-		CPU:       drytermui.NewThemedGaugeColumn(DryTheme),
-		Memory:    drytermui.NewThemedGaugeColumn(DryTheme),
-		Net:       drytermui.NewThemedParColumn(DryTheme, "-"),
-		Block:     drytermui.NewThemedParColumn(DryTheme, "-"),
-		Pids:      drytermui.NewThemedParColumn(DryTheme, "-"),
+		container:     c,
+		Host:          drytermui.NewThemedParColumn(DryTheme, "-"),
+		Name:          drytermui.NewThemedParColumn(DryTheme, options.StateGlyphs.prefix(c)+cf.DisplayName(options.NameDisplay)),
+		ID:            drytermui.NewThemedParColumn(DryTheme, options.IDDisplay.text(c.ID)),
+		Image:         drytermui.NewThemedParColumn(DryTheme, cf.ShortImage()),
+		CPU:           drytermui.NewThemedGaugeColumn(DryTheme),
+		CPUAverage:    drytermui.NewThemedParColumn(DryTheme, "-"),
+		Memory:        drytermui.NewThemedGaugeColumn(DryTheme),
+		HostMemory:    drytermui.NewThemedGaugeColumn(DryTheme),
+		CPUSplit:      drytermui.NewThemedParColumn(DryTheme, "-"),
+		CPULimit:      drytermui.NewThemedParColumn(DryTheme, pendingText),
+		MemoryLimit:   drytermui.NewThemedParColumn(DryTheme, pendingText),
+		RestartPolicy: drytermui.NewThemedParColumn(DryTheme, pendingText),
+		CPUSet:        drytermui.NewThemedParColumn(DryTheme, pendingText),
+		StartedAt:     drytermui.NewThemedParColumn(DryTheme, pendingText),
+		SwarmReplica:  drytermui.NewThemedParColumn(DryTheme, "-"),
+		NoFile:        drytermui.NewThemedParColumn(DryTheme, pendingText),
+		WritableLayer: drytermui.NewThemedParColumn(DryTheme, pendingText),
+		Note:          drytermui.NewThemedParColumn(DryTheme, "-"),
+		Net:           drytermui.NewThemedParColumn(DryTheme, "-"),
+		Block:         drytermui.NewThemedParColumn(DryTheme, "-"),
+		IOPS:          drytermui.NewThemedParColumn(DryTheme, "-"),
+		Pids:          drytermui.NewThemedParColumn(DryTheme, "-"),
+		FDs:           drytermui.NewThemedParColumn(DryTheme, "-"),
+		Ports:         drytermui.NewThemedParColumn(DryTheme, "-"),
+		Updated:       drytermui.NewThemedParColumn(DryTheme, "-"),
 
-		Height: 1,
+		Height:            1,
+		cpuAverage:        newMovingAverage(cpuAverageSamples),
+		cpuSmoothed:       newExpMovingAverage(options.CPUSmoothing),
+		memSmoothed:       newExpMovingAverage(options.MemorySmoothing),
+		memPctSmoothed:    newExpMovingAverage(options.MemorySmoothing),
+		showRawValues:     options.ShowRawValues,
+		networkInterfaces: options.NetworkInterfaces,
+		expandInterfaces:  options.ExpandNetworkInterfaces,
+		ioFormat:          options.IOByteFormat,
+		ioDisplay:         options.IODisplay,
+		detail:            newRowDetail(c),
+		cpuColor:          gaugeColor{margin: options.ColorMargin},
+		cpuScale:          options.CPUGaugeScale,
+		memColor:          gaugeColor{margin: options.ColorMargin},
+		hostMemColor:      gaugeColor{margin: options.ColorMargin},
+		Labels:            make(map[string]*drytermui.ParColumn),
+		Derived:           make(map[string]*drytermui.ParColumn),
+		derivedColumns:    options.DerivedColumns,
+		states:            make(map[termui.Bufferer]columnState),
+		staleTimeout:      options.StaleTimeout,
+		oomRisk:           newOOMRisk(options.OOMRiskMargin, options.OOMRiskPeriod),
+		separator:         columnSeparator(options.ColumnSeparator),
+		startedAtLayout:   options.StartedAtLayout,
+		history:           options.Histories.history(c.ID, options.HistorySize),
+	}
+	if row.startedAtLayout == "" {
+		row.startedAtLayout = DefaultStartedAtLayout
+	}
+	if row.staleTimeout <= 0 {
+		row.staleTimeout = DefaultStaleTimeout
+	}
+	row.detail.ioFormat = options.IOByteFormat
+	for _, d := range options.DerivedColumns {
+		row.Derived[d.Name] = drytermui.NewThemedParColumn(DryTheme, "-")
+	}
+	if replica, ok := docker.SwarmReplica(c); ok {
+		row.SwarmReplica.Text = replica
+	}
+	if ports := docker.CompactPorts(c.Ports); len(ports) > 0 {
+		row.ports = strings.Join(ports, ",")
+		row.Ports.Text = row.ports
+	}
+	for _, key := range options.Labels {
+		row.Labels[key] = drytermui.NewThemedParColumn(DryTheme, labelValue(c, key))
+	}
+	if options.Notes != nil {
+		row.setNote(options.Notes.Note(c))
 	}
 	//Columns are rendered following the slice order
-	row.columns = []termui.GridBufferer{
-		row.ID,
-		row.Name,
-		row.CPU,
-		row.Memory,
-		row.Net,
-		row.Block,
-		row.Pids,
+	columns := options.columns()
+	for _, column := range columns {
+		row.columns = append(row.columns, column.widget(row))
 	}
+	row.priorities = priorities(columns)
+	row.sizes = columnSizes(columns, options.IDDisplay)
+	row.definitions = columns
 	if docker.IsContainerRunning(c) {
-		go func() {
-			for stat := range s.Stats {
-				row.setNet(stat.NetworkRx, stat.NetworkTx)
-				row.setCPU(stat.CPUPercentage)
-				row.setMem(stat.Memory, stat.MemoryLimit, stat.MemoryPercentage)
-				row.setBlockIO(stat.BlockRead, stat.BlockWrite)
-				row.setPids(stat.PidsCurrent)
-			}
-		}()
+		//Until the first sample is received
+		for _, column := range row.statsColumns() {
+			row.setColumnState(column, columnPending)
+		}
 	} else {
 		row.markAsNotRunning()
 	}
 	return row
 }
 
-//Reset resets row content
+//Update updates the row with the given stats sample, it is shown on the next render.
+func (row *ContainerStatsRow) Update(stat *docker.Stats) {
+	row.update(stat, time.Now())
+}
+
+//update updates the row with the given stats sample received at the given time
+func (row *ContainerStatsRow) update(stat *docker.Stats, now time.Time) {
+	row.Lock()
+	defer row.Unlock()
+	row.lastUpdated = now
+	row.sampledEvery = stat.Interval
+	row.pending = stat
+	row.latest = stat
+	row.rates, _ = row.rateCalc.update(stat, now)
+	row.history.Add(statsPoint(stat, row.rates, now))
+	row.atRisk = row.oomRisk.update(stat, now)
+	row.cpuAverage.add(stat.CPUPercentage)
+	row.cpuSmoothed.add(stat.CPUPercentage)
+	row.memSmoothed.add(stat.Memory)
+	row.memPctSmoothed.add(stat.MemoryPercentage)
+}
+
+//SetNote shows the given note about the container
+func (row *ContainerStatsRow) SetNote(note string) {
+	row.Lock()
+	defer row.Unlock()
+	row.setNote(note)
+}
+
+func (row *ContainerStatsRow) setNote(note string) {
+	if note == "" {
+		row.Note.Text = "-"
+	} else {
+		row.Note.Text = note
+	}
+	row.detail.setNote(note)
+}
+
+//SetHost sets the name of the host of the container
+func (row *ContainerStatsRow) SetHost(host string) {
+	row.Lock()
+	defer row.Unlock()
+	if host != "" {
+		row.Host.Text = host
+	}
+}
+
+//CPUPercentageAvg returns the average CPU usage of the container over the
+//last cpuAverageSamples samples.
+func (row *ContainerStatsRow) CPUPercentageAvg() float64 {
+	row.RLock()
+	defer row.RUnlock()
+	return row.cpuAverage.value()
+}
+
+//Highlighted marks this row as being highlighted, or not.
+func (row *ContainerStatsRow) Highlighted(highlighted bool) {
+	row.Lock()
+	defer row.Unlock()
+	if highlighted {
+		row.ID.TextFgColor |= termui.AttrReverse
+		row.Name.TextFgColor |= termui.AttrReverse
+	} else {
+		row.ID.TextFgColor &^= termui.AttrReverse
+		row.Name.TextFgColor &^= termui.AttrReverse
+	}
+}
+
+//Pinned marks this row as being pinned, or not, pinned rows show the
+//container name in bold.
+func (row *ContainerStatsRow) Pinned(pinned bool) {
+	row.Lock()
+	defer row.Unlock()
+	if pinned {
+		row.Name.TextFgColor |= termui.AttrBold
+	} else {
+		row.Name.TextFgColor &^= termui.AttrBold
+	}
+}
+
+//SearchMatch marks this row as matching a search, or not, matching rows show
+//the container name underlined.
+func (row *ContainerStatsRow) SearchMatch(match bool) {
+	row.Lock()
+	defer row.Unlock()
+	if match {
+		row.Name.TextFgColor |= termui.AttrUnderline
+	} else {
+		row.Name.TextFgColor &^= termui.AttrUnderline
+	}
+}
+
+//SetLimits shows the given resource limits, and restart policy, of the container
+func (row *ContainerStatsRow) SetLimits(limits docker.ContainerLimits) {
+	row.Lock()
+	defer row.Unlock()
+	row.CPULimit.Text = "unlimited"
+	if limits.CPUs > 0 {
+		row.CPULimit.Text = strconv.FormatFloat(limits.CPUs, 'f', -1, 64) + " cores"
+	}
+	row.MemoryLimit.Text = "unlimited"
+	if limits.Memory > 0 {
+		row.MemoryLimit.Text = units.BytesSize(float64(limits.Memory))
+	}
+	row.RestartPolicy.Text = limits.RestartPolicy
+	row.pidMode = limits.PidMode
+	row.CPUSet.Text = "all"
+	if limits.CPUSet != "" {
+		row.CPUSet.Text = limits.CPUSet
+	}
+	row.StartedAt.Text = "-"
+	if !limits.StartedAt.IsZero() {
+		row.StartedAt.Text = limits.StartedAt.Local().Format(row.startedAtLayout)
+	}
+	row.NoFile.Text = ulimitText(limits.NoFile)
+	row.setColumnState(row.CPUSet, columnReady)
+	row.setColumnState(row.StartedAt, columnReady)
+	row.setColumnState(row.NoFile, columnReady)
+	row.setColumnState(row.CPULimit, columnReady)
+	row.setColumnState(row.MemoryLimit, columnReady)
+	row.setColumnState(row.RestartPolicy, columnReady)
+}
+
+//ulimitText returns the soft and hard values of the given ulimit, i.e. 1024/4096,
+//a single value if both are the same and "default" if the ulimit is not set
+func ulimitText(ulimit *docker.Ulimit) string {
+	if ulimit == nil {
+		return "default"
+	}
+	value := func(v int64) string {
+		if v < 0 {
+			return "unlimited"
+		}
+		return strconv.FormatInt(v, 10)
+	}
+	if ulimit.Soft == ulimit.Hard {
+		return value(ulimit.Soft)
+	}
+	return value(ulimit.Soft) + "/" + value(ulimit.Hard)
+}
+
+//SetWritableLayerSize shows the given size, in bytes, of the writable layer of the
+//container, large sizes are highlighted.
+func (row *ContainerStatsRow) SetWritableLayerSize(size int64) {
+	row.Lock()
+	defer row.Unlock()
+	row.WritableLayer.Text = units.BytesSize(float64(size))
+	switch {
+	case size >= writableLayerAlertSize:
+		row.WritableLayer.TextFgColor = termui.Attribute(DryTheme.GaugeLevels[2])
+	case size >= writableLayerWarningSize:
+		row.WritableLayer.TextFgColor = termui.Attribute(DryTheme.GaugeLevels[1])
+	default:
+		row.WritableLayer.TextFgColor = termui.Attribute(DryTheme.Fg)
+	}
+	row.setColumnState(row.WritableLayer, columnReady)
+}
+
+//writableLayerUnavailable shows that the size of the writable layer of the container
+//could not be retrieved
+func (row *ContainerStatsRow) writableLayerUnavailable() {
+	row.Lock()
+	defer row.Unlock()
+	row.setColumnState(row.WritableLayer, columnNotApplicable)
+}
+
+//limitsUnavailable shows that the resource limits of the container could not be retrieved
+func (row *ContainerStatsRow) limitsUnavailable() {
+	row.Lock()
+	defer row.Unlock()
+	row.setColumnState(row.CPULimit, columnNotApplicable)
+	row.setColumnState(row.MemoryLimit, columnNotApplicable)
+	row.setColumnState(row.RestartPolicy, columnNotApplicable)
+	row.setColumnState(row.CPUSet, columnNotApplicable)
+	row.setColumnState(row.StartedAt, columnNotApplicable)
+	row.setColumnState(row.NoFile, columnNotApplicable)
+}
+
+//MarkOOMKilled marks this row as showing a container that was killed for
+//running out of memory, a red OOM marker is shown before the container name.
+func (row *ContainerStatsRow) MarkOOMKilled() {
+	row.Lock()
+	defer row.Unlock()
+	if row.oomKilled {
+		return
+	}
+	row.oomKilled = true
+	row.setStale(false)
+	row.setOOMRisk(false)
+	row.Name.Text = oomMarker + row.Name.Text
+	row.Name.TextFgColor = termui.ColorRed | row.Name.TextFgColor&(termui.AttrBold|termui.AttrReverse)
+}
+
+//OOMKilled returns true if the container was killed for running out of memory
+func (row *ContainerStatsRow) OOMKilled() bool {
+	row.RLock()
+	defer row.RUnlock()
+	return row.oomKilled
+}
+
+//StreamEnded returns true if the stats channel of the container has been closed
+func (row *ContainerStatsRow) StreamEnded() bool {
+	row.RLock()
+	defer row.RUnlock()
+	return row.streamEnded
+}
+
+//running returns true if the container is running and its stats are being received
+func (row *ContainerStatsRow) running() bool {
+	row.RLock()
+	defer row.RUnlock()
+	return docker.IsContainerRunning(row.container) && !row.streamEnded
+}
+
+//markStopped shows this row as the row of a container that is not running
+func (row *ContainerStatsRow) markStopped() {
+	row.Lock()
+	defer row.Unlock()
+	row.markAsNotRunning()
+}
+
+//uncheckedEnd returns true, only once, after the stats channel of the container
+//is closed.
+func (row *ContainerStatsRow) uncheckedEnd() bool {
+	row.Lock()
+	defer row.Unlock()
+	if !row.streamEnded || row.endChecked {
+		return false
+	}
+	row.endChecked = true
+	return true
+}
+
+//SetOdd sets whether this row is on an odd position of the page, odd
+//rows are shaded.
+func (row *ContainerStatsRow) SetOdd(odd bool) {
+	row.Lock()
+	defer row.Unlock()
+	row.odd = odd
+}
+
+//Expanded returns true if the detail of this row is shown
+func (row *ContainerStatsRow) Expanded() bool {
+	row.RLock()
+	defer row.RUnlock()
+	return row.expanded
+}
+
+//SetExpanded shows, or hides, the detail of this row below it
+func (row *ContainerStatsRow) SetExpanded(expanded bool) {
+	row.Lock()
+	defer row.Unlock()
+	row.expanded = expanded
+}
+
+//SetTimeline shows the lifecycle events of the given timeline on the detail
+//of this row, nil removes them
+func (row *ContainerStatsRow) SetTimeline(timeline *docker.ContainerTimeline) {
+	row.Lock()
+	defer row.Unlock()
+	row.detail.setTimeline(timeline)
+}
+
+//LastUpdated returns the time at which the last stats sample was received,
+//zero time if none has been received yet.
+func (row *ContainerStatsRow) LastUpdated() time.Time {
+	row.RLock()
+	defer row.RUnlock()
+	return row.lastUpdated
+}
+
+//Latest returns the last stats sample received, nil if none has been received yet.
+func (row *ContainerStatsRow) Latest() *docker.Stats {
+	row.RLock()
+	defer row.RUnlock()
+	return row.latest
+}
+
+//Reset resets row content. A sample received but not yet shown is discarded, so
+//that it is not applied over the reset content.
 func (row *ContainerStatsRow) Reset() {
+	row.Lock()
+	defer row.Unlock()
+	row.pending = nil
 	row.CPU.Reset()
 	row.Memory.Reset()
+	row.HostMemory.Reset()
 	row.Net.Reset()
 	row.Pids.Reset()
+	row.CPUSplit.Reset()
+	row.FDs.Reset()
 	row.Block.Reset()
+	row.IOPS.Reset()
+}
+
+//ResetStats clears the statistics accumulated by this row, such as averages, so
+//that they are calculated from the next sample received. Stats collection is
+//not interrupted.
+func (row *ContainerStatsRow) ResetStats() {
+	row.Lock()
+	defer row.Unlock()
+	row.cpuAverage.reset()
+	row.cpuSmoothed.reset()
+	row.memSmoothed.reset()
+	row.memPctSmoothed.reset()
+	row.rateCalc.reset()
+	row.rates = IORates{}
+	row.oomRisk.reset()
+	row.history.Reset()
+	row.setColumnState(row.CPUAverage, columnPending)
+}
+
+//History returns the stats history of the container, oldest point first, of
+//at most StatsRowOptions.HistorySize points
+func (row *ContainerStatsRow) History() []StatsPoint {
+	row.RLock()
+	defer row.RUnlock()
+	return row.history.Points()
+}
+
+//sample returns the last stats sample received and the IO rates calculated with it,
+//rates are zero until two samples are received. False is returned if no sample has been received in twice the sampling interval, the
+//container has probably stopped.
+func (row *ContainerStatsRow) sample(now time.Time) (*docker.Stats, IORates, bool) {
+	row.RLock()
+	defer row.RUnlock()
+	if row.latest == nil || now.Sub(row.lastUpdated) > 2*row.samplingInterval() {
+		return nil, IORates{}, false
+	}
+	return row.latest, row.rates, true
 }
 
-//GetHeight returns this ContainerStatsRow heigth
+//GetHeight returns this ContainerStatsRow heigth, including its
+//detail if the row is expanded
 func (row *ContainerStatsRow) GetHeight() int {
+	row.RLock()
+	defer row.RUnlock()
+	if row.expanded {
+		return row.Height + row.detail.GetHeight()
+	}
 	return row.Height
 }
 
 //SetX sets the x position of this ContainerStatsRow
 func (row *ContainerStatsRow) SetX(x int) {
 	row.X = x
+	row.detail.setX(x)
 }
 
 //SetY sets the y position of this ContainerStatsRow
@@ -97,6 +637,7 @@ func (row *ContainerStatsRow) SetY(y int) {
 	for _, col := range row.columns {
 		col.SetY(y)
 	}
+	row.detail.setY(y + row.Height)
 	row.Y = y
 }
 
@@ -106,51 +647,275 @@ func (row *ContainerStatsRow) SetWidth(width int) {
 		return
 	}
 	row.Width = width
+	row.detail.setWidth(width)
+	row.layout()
+}
+
+//layout places the columns of this row on its width
+func (row *ContainerStatsRow) layout() {
 	x := row.X
-	rw := calcItemWidth(width, len(row.columns))
-	for _, col := range row.columns {
+	widths, visible := layoutSizedColumns(row.Width, row.priorities, row.sizes, row.separator.gap())
+	row.visible = visible
+	row.separators = nil
+	for i, col := range row.columns {
+		if !visible[i] {
+			continue
+		}
+		if x > row.X && row.separator != 0 {
+			row.separators = append(row.separators, row.separator.position(x))
+		}
 		col.SetX(x)
-		col.SetWidth(rw)
-		x += rw + columnSpacing
+		col.SetWidth(widths[i])
+		if col == termui.GridBufferer(row.Ports) && row.ports != "" {
+			row.Ports.Text = truncateText(row.ports, widths[i])
+		}
+		x += widths[i] + row.separator.gap()
 	}
 }
 
 //Buffer returns this ContainerStatsRow data as a termui.Buffer
 func (row *ContainerStatsRow) Buffer() termui.Buffer {
-	buf := termui.NewBuffer()
+	row.Lock()
+	defer row.Unlock()
+	buf := drytermui.NewSizedBuffer(row.Width, row.Height)
+	if row.pending != nil {
+		row.apply(row.pending)
+		row.pending = nil
+	}
+	now := time.Now()
+	row.setUpdated(now)
+	row.checkStale(now)
+	for i, col := range row.columns {
+		if row.visible != nil && !row.visible[i] {
+			continue
+		}
+		buf.Merge(col.Buffer())
+	}
+	row.separator.render(&buf, row.Y, row.separators)
+	if row.stale {
+		greyOut(buf)
+	}
+	if row.expanded {
+		buf.Merge(row.detail.buffer())
+	}
+	if row.odd {
+		shade(buf, DryTheme)
+	}
+	return buf
+}
 
-	buf.Merge(row.ID.Buffer())
-	buf.Merge(row.Name.Buffer())
-	buf.Merge(row.CPU.Buffer())
-	buf.Merge(row.Memory.Buffer())
-	buf.Merge(row.Net.Buffer())
-	buf.Merge(row.Block.Buffer())
-	buf.Merge(row.Pids.Buffer())
+//apply shows the given stats sample on the row columns
+func (row *ContainerStatsRow) apply(stat *docker.Stats) {
+	for _, column := range row.statsColumns() {
+		row.setColumnState(column, columnReady)
+	}
+	switch {
+	case row.ioDisplay == IOPerSecond:
+		row.setNetRate(row.rates.NetworkRx, row.rates.NetworkTx)
+	case row.networkInterfaces:
+		row.Net.Text = networkInterfacesText(stat, row.expandInterfaces, row.ioFormat)
+	default:
+		row.setNet(stat.NetworkRx, stat.NetworkTx)
+	}
+	row.setCPU(row.cpuSmoothed.value())
+	row.CPUAverage.Text = fmt.Sprintf("%.2f%%", row.cpuAverage.value())
+	row.CPUAverage.TextFgColor = percentileToColor(int(row.cpuAverage.value()))
+	row.setCPUSplit(stat.CPUUserPercentage, stat.CPUSystemPercentage)
+	row.setMem(row.memSmoothed.value(), stat.MemoryLimit, row.memPctSmoothed.value())
+	row.setHostMem(row.memSmoothed.value())
+	if row.showRawValues {
+		row.CPU.Label = fmt.Sprintf("%.2f%%", stat.CPUPercentage)
+		row.Memory.Label = fmt.Sprintf("%s / %s", units.BytesSize(stat.Memory), units.BytesSize(stat.MemoryLimit))
+	}
+	if row.ioDisplay == IOPerSecond {
+		row.setBlockIORate(row.rates.BlockRead, row.rates.BlockWrite)
+	} else {
+		row.setBlockIO(stat.BlockRead, stat.BlockWrite)
+	}
+	row.setIOPS(row.rates.ReadOps, row.rates.WriteOps)
+	row.setPids(stat.PidsCurrent)
+	row.setFDs(stat.OpenFDs)
+	row.setDerived(stat)
+	row.detail.setNetworkStats(stat)
+	row.setOOMRisk(row.atRisk && !row.oomKilled)
+}
 
-	return buf
+//setDerived shows the value of the derived columns for the given sample, a dash
+//if the value is not a finite number, i.e. on divisions by zero.
+func (row *ContainerStatsRow) setDerived(stat *docker.Stats) {
+	for _, d := range row.derivedColumns {
+		//Columns not created with AddDerivedColumn have no expression to evaluate
+		if d.expression == nil {
+			row.setColumnState(row.Derived[d.Name], columnNotApplicable)
+			continue
+		}
+		v := d.expression.eval(stat)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			row.setColumnState(row.Derived[d.Name], columnNotApplicable)
+		} else {
+			row.Derived[d.Name].Text = strconv.FormatFloat(v, 'f', 2, 64)
+		}
+	}
 }
 
 func (row *ContainerStatsRow) setNet(rx float64, tx float64) {
-	row.Net.Text = fmt.Sprintf("%s / %s", units.BytesSize(rx), units.BytesSize(tx))
+	row.Net.Text = networkText(rx, tx, row.ioFormat)
 }
 
 func (row *ContainerStatsRow) setBlockIO(read float64, write float64) {
-	row.Block.Text = fmt.Sprintf("%s / %s", units.BytesSize(read), units.BytesSize(write))
+	row.Block.Text = fmt.Sprintf("%s / %s", row.ioFormat.size(read), row.ioFormat.size(write))
+}
+
+//setNetRate shows the given network rates, in bytes per second
+func (row *ContainerStatsRow) setNetRate(rx float64, tx float64) {
+	row.Net.Text = rateText(rx, tx, row.ioFormat)
+}
+
+//setBlockIORate shows the given block IO rates, in bytes per second
+func (row *ContainerStatsRow) setBlockIORate(read float64, write float64) {
+	row.Block.Text = rateText(read, write, row.ioFormat)
+}
+
+//SetIODisplay sets how the network and block IO of the container are shown,
+//the last sample received is shown again on the next render.
+func (row *ContainerStatsRow) SetIODisplay(display IODisplay) {
+	row.Lock()
+	defer row.Unlock()
+	row.ioDisplay = display
+	if row.latest != nil && row.pending == nil {
+		row.pending = row.latest
+	}
+}
+
+//SetIDDisplay sets how long the container ID shown is, the columns of the
+//row are placed again as the ID column might need a different width.
+func (row *ContainerStatsRow) SetIDDisplay(display IDDisplay) {
+	row.Lock()
+	defer row.Unlock()
+	row.ID.Text = display.text(row.container.ID)
+	row.sizes = columnSizes(row.definitions, display)
+	if row.Width > 0 {
+		row.layout()
+	}
 }
+
+//display returns how the network and block IO of the container are shown
+func (row *ContainerStatsRow) display() IODisplay {
+	row.RLock()
+	defer row.RUnlock()
+	return row.ioDisplay
+}
+
+//setIOPS shows the given read and write operations per second
+func (row *ContainerStatsRow) setIOPS(read float64, write float64) {
+	row.IOPS.Text = fmt.Sprintf("%.0f / %.0f", read, write)
+}
+
 func (row *ContainerStatsRow) setPids(pids uint64) {
 	row.Pids.Text = strconv.Itoa(int(pids))
 }
 
+//setFDs shows the number of open file descriptors, a dash if unknown
+func (row *ContainerStatsRow) setFDs(fds int) {
+	if fds < 0 {
+		row.setColumnState(row.FDs, columnNotApplicable)
+		return
+	}
+	row.FDs.Text = strconv.Itoa(fds)
+}
+
+//setUpdated shows how long ago, from the given time, the last sample was received.
+//If no sample has been received in twice the sampling interval of the row the
+//text is shown in amber.
+func (row *ContainerStatsRow) setUpdated(now time.Time) {
+	if row.lastUpdated.IsZero() {
+		return
+	}
+	elapsed := now.Sub(row.lastUpdated)
+	row.Updated.Text = fmt.Sprintf("%ds ago", int(elapsed.Seconds()))
+	if elapsed > 2*row.samplingInterval() {
+		row.Updated.TextFgColor = termui.Attribute(ui.Color214)
+	} else {
+		row.Updated.TextFgColor = termui.Attribute(ui.Color244)
+	}
+}
+
+//checkStale marks the row as stale if its stats stream is open but no sample
+//has been received, from the given time, in the stale timeout. The row is no
+//longer stale once a sample is received.
+func (row *ContainerStatsRow) checkStale(now time.Time) {
+	row.setStale(!row.lastUpdated.IsZero() && !row.streamEnded && !row.oomKilled &&
+		now.Sub(row.lastUpdated) > row.staleAfter())
+}
+
+//staleAfter returns the stale timeout of the row, it is longer for rows whose
+//samples are received less often than every StatsInterval
+func (row *ContainerStatsRow) staleAfter() time.Duration {
+	scale := float64(row.samplingInterval()) / float64(docker.StatsInterval)
+	return time.Duration(float64(row.staleTimeout) * scale)
+}
+
+//setStale shows, or hides, the stale marker before the container name
+func (row *ContainerStatsRow) setStale(stale bool) {
+	if stale == row.stale {
+		return
+	}
+	row.stale = stale
+	if stale {
+		row.Name.Text = staleMarker + row.Name.Text
+	} else {
+		row.Name.Text = strings.Replace(row.Name.Text, staleMarker, "", 1)
+	}
+}
+
+//setOOMRisk shows, or hides, the marker of containers at risk of running out
+//of memory before the container name
+func (row *ContainerStatsRow) setOOMRisk(risk bool) {
+	if risk == row.riskShown {
+		return
+	}
+	row.riskShown = risk
+	if risk {
+		row.Name.Text = oomRiskMarker + row.Name.Text
+	} else {
+		row.Name.Text = strings.Replace(row.Name.Text, oomRiskMarker, "", 1)
+	}
+}
+
+//SetCPUScale sets the CPU usage, in percentage of a core, shown as a full
+//CPU gauge
+func (row *ContainerStatsRow) SetCPUScale(scale float64) {
+	row.Lock()
+	defer row.Unlock()
+	row.cpuScale = scale
+}
+
+//CPUScale returns the CPU usage, in percentage of a core, shown as a full CPU gauge
+func (row *ContainerStatsRow) CPUScale() float64 {
+	row.RLock()
+	defer row.RUnlock()
+	return row.cpuScale
+}
+
+//scaleCPU returns the given CPU usage as a percentage of the given gauge scale
+func scaleCPU(val float64, scale float64) float64 {
+	if scale <= 0 {
+		scale = DefaultCPUGaugeScale
+	}
+	return val * 100 / scale
+}
+
 func (row *ContainerStatsRow) setCPU(val float64) {
 	row.CPU.Label = fmt.Sprintf("%.2f%%", val)
-	cpu := int(val)
+	cpu := int(scaleCPU(val, row.cpuScale))
 	if cpu < 5 {
 		cpu = 5
 	} else if cpu > 100 {
 		cpu = 100
 	}
 	row.CPU.Percent = cpu
-	row.CPU.BarColor = percentileToColor(cpu)
+	row.CPU.BarColor = row.cpuColor.color(cpu)
+	row.CPU.PercentColorHighlighted = labelColor(row.CPU.BarColor)
 }
 
 func (row *ContainerStatsRow) setMem(val float64, limit float64, percent float64) {
@@ -162,7 +927,69 @@ func (row *ContainerStatsRow) setMem(val float64, limit float64, percent float64
 		mem = 100
 	}
 	row.Memory.Percent = mem
-	row.Memory.BarColor = percentileToColor(mem)
+	row.Memory.BarColor = row.memColor.color(mem)
+	row.Memory.PercentColorHighlighted = labelColor(row.Memory.BarColor)
+}
+
+//setCPUSplit shows the given percentages of CPU time in user and kernel mode,
+//a dash if no CPU time was used
+func (row *ContainerStatsRow) setCPUSplit(user, system float64) {
+	if user == 0 && system == 0 {
+		row.CPUSplit.Text = "-"
+		return
+	}
+	row.CPUSplit.Text = fmt.Sprintf("%.0f%% / %.0f%%", user, system)
+}
+
+//setHostMem shows the given memory usage as a percentage of the host memory,
+//a dash if the host memory is unknown
+func (row *ContainerStatsRow) setHostMem(val float64) {
+	if row.hostMemory <= 0 {
+		row.HostMemory.Label = "-"
+		return
+	}
+	percent := val / float64(row.hostMemory) * 100
+	row.HostMemory.Label = fmt.Sprintf("%.1f%% of host", percent)
+	mem := int(percent)
+	if mem < 5 {
+		mem = 5
+	} else if mem > 100 {
+		mem = 100
+	}
+	row.HostMemory.Percent = mem
+	row.HostMemory.BarColor = row.hostMemColor.color(mem)
+	row.HostMemory.PercentColorHighlighted = labelColor(row.HostMemory.BarColor)
+}
+
+//SetHostMemory sets the memory of the host of the container, in bytes, the
+//host memory gauge shows the memory usage as a percentage of it
+func (row *ContainerStatsRow) SetHostMemory(memory int64) {
+	row.Lock()
+	defer row.Unlock()
+	row.hostMemory = memory
+}
+
+//setPolled tells this row that the stats of its container are polled in turns
+//with the ones of n containers, a sample every the given interval, so samples
+//are received n times less often.
+func (row *ContainerStatsRow) setPolled(n int, interval time.Duration) {
+	row.Lock()
+	defer row.Unlock()
+	row.polledEvery = time.Duration(n) * interval
+}
+
+//samplingInterval returns the time between two samples of the row, the
+//longest of the stats interval, the polling turn of the container and the
+//interval the polling policy decided.
+func (row *ContainerStatsRow) samplingInterval() time.Duration {
+	interval := docker.StatsInterval
+	if row.polledEvery > interval {
+		interval = row.polledEvery
+	}
+	if row.sampledEvery > interval {
+		interval = row.sampledEvery
+	}
+	return interval
 }
 
 //markAsNotRunning
@@ -170,19 +997,59 @@ func (row *ContainerStatsRow) markAsNotRunning() {
 	c := termui.Attribute(ui.Color244)
 	row.Name.TextFgColor = c
 	row.ID.TextFgColor = c
+	row.Image.TextFgColor = c
+	for _, label := range row.Labels {
+		label.TextFgColor = c
+	}
 	row.CPU.PercentColor = c
-	row.CPU.Label = "-"
 	row.Memory.PercentColor = c
-	row.Memory.Label = "-"
+	row.HostMemory.PercentColor = c
 	row.Net.TextFgColor = c
+	for _, column := range row.statsColumns() {
+		row.setColumnState(column, columnNotApplicable)
+	}
 }
 
-func percentileToColor(n int) termui.Attribute {
-	c := ui.Color23
-	if n > 90 {
-		c = ui.Color161
-	} else if n > 70 {
-		c = ui.Color131
+//greyOut renders the text of the given buffer in grey, like the text of
+//containers that are not running
+func greyOut(buf termui.Buffer) {
+	grey := termui.Attribute(ui.Color244)
+	for p, c := range buf.CellMap {
+		c.Fg = grey | c.Fg&(termui.AttrBold|termui.AttrReverse)
+		buf.CellMap[p] = c
 	}
-	return termui.Attribute(c)
+}
+
+//shade replaces the theme background of the given buffer with the background
+//color for alternate rows
+func shade(buf termui.Buffer, theme *ui.ColorTheme) {
+	bg := termui.Attribute(theme.Bg)
+	altBg := termui.Attribute(theme.AltBg)
+	for p, c := range buf.CellMap {
+		if c.Bg == bg {
+			c.Bg = altBg
+			buf.CellMap[p] = c
+		}
+	}
+}
+
+//labelValue returns the value of the label with the given key of the
+//given container, a dash if the container does not have the label.
+func labelValue(c *types.Container, key string) string {
+	if value, ok := c.Labels[key]; ok && value != "" {
+		return value
+	}
+	return "-"
+}
+
+//percentileToColor returns the color of the given percentage on the active theme
+func percentileToColor(n int) termui.Attribute {
+	return termui.Attribute(DryTheme.ColorForPercent(n, colorThresholds))
+}
+
+//pidNamespace returns the PID namespace of the container of this row, see docker.ContainerLimits
+func (row *ContainerStatsRow) pidNamespace() string {
+	row.RLock()
+	defer row.RUnlock()
+	return row.pidMode
 }
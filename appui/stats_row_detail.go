@@ -0,0 +1,285 @@
+package appui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/docker"
+	drytermui "github.com/moncho/dry/ui/termui"
+)
+
+//detailIndent is the indentation of the lines of the row detail
+const detailIndent = "  "
+
+//rowDetail is the expandable detail of a ContainerStatsRow, one line
+//per piece of information.
+type rowDetail struct {
+	mounts []types.MountPoint
+	//networks are the names of the networks of the container, sorted
+	networks  []string
+	addresses map[string]string
+	//networkIO is the traffic of each network, if it could be correlated
+	//with the network interfaces of the container
+	networkIO  map[string]string
+	interfaces string
+	ioFormat   ByteFormat
+	ports      []string
+	note       string
+	//timeline, if set, has the recent lifecycle events of the container
+	timeline *docker.ContainerTimeline
+	lines    []*drytermui.ParColumn
+}
+
+func newRowDetail(c *types.Container) *rowDetail {
+	d := &rowDetail{
+		mounts:    c.Mounts,
+		addresses: make(map[string]string),
+		ports:     docker.CompactPorts(c.Ports)}
+	if c.NetworkSettings != nil {
+		for name, settings := range c.NetworkSettings.Networks {
+			d.networks = append(d.networks, name)
+			if settings != nil {
+				d.addresses[name] = settings.IPAddress
+			}
+		}
+		sort.Strings(d.networks)
+	}
+	//A title line and a line per mount, a title line and a line per
+	//network, then a line with the ports. A line with the lifecycle events
+	//and a line with the note about the container are added if known.
+	for i := 0; i < d.fixedLines(); i++ {
+		line := drytermui.NewThemedParColumn(DryTheme, "")
+		line.Height = 1
+		d.lines = append(d.lines, line)
+	}
+	d.setWidth(0)
+	return d
+}
+
+//GetHeight returns the number of lines of this detail
+func (d *rowDetail) GetHeight() int {
+	return len(d.lines)
+}
+
+func (d *rowDetail) setX(x int) {
+	for _, line := range d.lines {
+		line.SetX(x)
+	}
+}
+
+//setY sets the position of the first line of the detail
+func (d *rowDetail) setY(y int) {
+	for i, line := range d.lines {
+		line.SetY(y + i)
+	}
+}
+
+//setWidth sets the width of the detail, paths that do not fit are truncated
+func (d *rowDetail) setWidth(width int) {
+	for _, line := range d.lines {
+		line.SetWidth(width)
+	}
+	ports := d.portsLine()
+	if len(d.ports) == 0 {
+		ports.Text = detailIndent + "Ports: none"
+	} else {
+		ports.Text = detailIndent + "Ports: " + strings.Join(d.ports, ", ")
+	}
+	if len(d.mounts) == 0 {
+		d.lines[0].Text = detailIndent + "Mounts: none"
+	} else {
+		d.lines[0].Text = detailIndent + "Mounts:"
+	}
+	for i, m := range d.mounts {
+		d.lines[i+1].Text = mountLine(m, width)
+	}
+	d.setNetworkLines()
+	d.setExtraLines()
+}
+
+//fixedLines returns the number of lines shown whatever is known about the container
+func (d *rowDetail) fixedLines() int {
+	return 3 + len(d.mounts) + len(d.networks)
+}
+
+//portsLine returns the line showing the ports of the container
+func (d *rowDetail) portsLine() *drytermui.ParColumn {
+	return d.lines[2+len(d.mounts)+len(d.networks)]
+}
+
+//setNote sets the note about the container shown on the detail, the note
+//line is removed if the note is empty
+func (d *rowDetail) setNote(note string) {
+	d.note = note
+	d.setExtraLines()
+}
+
+//setTimeline sets the lifecycle events of the container shown on the detail,
+//the events line is removed if the timeline is nil
+func (d *rowDetail) setTimeline(timeline *docker.ContainerTimeline) {
+	d.timeline = timeline
+	d.setExtraLines()
+}
+
+//setExtraLines adds, after the ports line, the lines showing the lifecycle
+//events of the container and the note about it, if they are known.
+func (d *rowDetail) setExtraLines() {
+	ports := d.portsLine()
+	d.lines = d.lines[:d.fixedLines()]
+	if d.timeline != nil {
+		d.addLine(ports)
+		d.setTimelineLine()
+	}
+	if d.note != "" {
+		d.addLine(ports).Text = detailIndent + "Note: " + d.note
+	}
+}
+
+//addLine adds a line below the last one, as wide as the given line
+func (d *rowDetail) addLine(like *drytermui.ParColumn) *drytermui.ParColumn {
+	line := drytermui.NewThemedParColumn(DryTheme, "")
+	line.Height = 1
+	line.SetX(like.X)
+	line.SetY(like.Y + len(d.lines) - d.fixedLines() + 1)
+	line.SetWidth(like.Width)
+	d.lines = append(d.lines, line)
+	return line
+}
+
+//setTimelineLine shows the events of the timeline, the oldest ones are
+//dropped if they do not fit on the line.
+func (d *rowDetail) setTimelineLine() {
+	if d.timeline == nil {
+		return
+	}
+	line := d.lines[d.fixedLines()]
+	events := d.timeline.Events()
+	if len(events) == 0 {
+		line.Text = detailIndent + "Events: none yet"
+		return
+	}
+	const title = detailIndent + "Events: "
+	text := ""
+	for i := len(events) - 1; i >= 0; i-- {
+		event := eventText(events[i])
+		if text != "" {
+			event += ", "
+		}
+		if line.Width > 0 && len(title)+len(event)+len(text) > line.Width {
+			text = "…" + text
+			break
+		}
+		text = event + text
+	}
+	line.Text = title + text
+}
+
+//eventText describes the given container lifecycle event with its time and action
+func eventText(event events.Message) string {
+	action := strings.TrimPrefix(event.Action, "health_status: ")
+	if code, ok := event.Actor.Attributes["exitCode"]; ok && event.Action == "die" {
+		action += " (exit " + code + ")"
+	}
+	return time.Unix(event.Time, 0).Format("15:04:05") + " " + action
+}
+
+//setNetworkStats shows the traffic of each network of the container, Docker reports
+//traffic by network interface and not by network, so traffic is only shown by network
+//if the container has a single network and a single interface. Otherwise the
+//traffic of each interface is shown on the networks title line.
+func (d *rowDetail) setNetworkStats(stat *docker.Stats) {
+	d.networkIO = nil
+	d.interfaces = ""
+	if stat.Stats == nil || len(stat.Stats.Networks) == 0 {
+		return
+	}
+	if len(d.networks) == 1 && len(stat.Stats.Networks) == 1 {
+		d.networkIO = map[string]string{d.networks[0]: networkText(stat.NetworkRx, stat.NetworkTx, d.ioFormat)}
+	} else {
+		d.interfaces = networkInterfacesText(stat, true, d.ioFormat)
+	}
+	d.setNetworkLines()
+}
+
+func (d *rowDetail) setNetworkLines() {
+	title := d.lines[len(d.mounts)+1]
+	switch {
+	case len(d.networks) == 0:
+		title.Text = detailIndent + "Networks: none"
+	case d.interfaces != "":
+		title.Text = detailIndent + "Networks (traffic by interface: " + d.interfaces + "):"
+	default:
+		title.Text = detailIndent + "Networks:"
+	}
+	for i, name := range d.networks {
+		io, ok := d.networkIO[name]
+		if !ok {
+			io = "-"
+		}
+		d.lines[len(d.mounts)+2+i].Text = fmt.Sprintf("%s%s%-16s %-15s %s",
+			detailIndent, detailIndent, name, d.addresses[name], io)
+	}
+}
+
+func (d *rowDetail) buffer() termui.Buffer {
+	d.setTimelineLine()
+	buf := termui.NewBuffer()
+	for _, line := range d.lines {
+		buf.Merge(line.Buffer())
+	}
+	return buf
+}
+
+//mountLine describes the given mount on a line of the given width, the
+//source and destination paths are truncated if the line does not fit.
+func mountLine(m types.MountPoint, width int) string {
+	prefix := fmt.Sprintf("%s%s%-6s %s ", detailIndent, detailIndent, m.Type, m.Name)
+	suffix := " " + mountMode(m)
+	const arrow = " -> "
+	available := width - len(prefix) - len(suffix) - len(arrow)
+	source, destination := m.Source, m.Destination
+	if width > 0 && len(source)+len(destination) > available {
+		source = truncatePath(source, available/2)
+		destination = truncatePath(destination, available-available/2)
+	}
+	return prefix + source + arrow + destination + suffix
+}
+
+//mountMode returns "rw" if the mount is writable, "ro" otherwise
+func mountMode(m types.MountPoint) string {
+	if m.RW {
+		return "rw"
+	}
+	return "ro"
+}
+
+//truncateText truncates the given text to the given length, the end of
+//the text is replaced by an ellipsis.
+func truncateText(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	if length <= 1 {
+		return "…"
+	}
+	return string(runes[:length-1]) + "…"
+}
+
+//truncatePath truncates the given path to the given length keeping its
+//end, which is usually the most meaningful part of a path.
+func truncatePath(path string, length int) string {
+	runes := []rune(path)
+	if len(runes) <= length {
+		return path
+	}
+	if length <= 1 {
+		return "…"
+	}
+	return "…" + string(runes[len(runes)-length+1:])
+}
@@ -0,0 +1,190 @@
+package appui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/moncho/dry/docker"
+)
+
+func TestStatsRowDetailShowsMounts(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited",
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeVolume, Name: "data", Source: "/var/lib/docker/volumes/data/_data", Destination: "/data", RW: true},
+			{Type: mount.TypeBind, Source: "/etc/hosts", Destination: "/etc/hosts"},
+		}}
+	row := NewContainerStatsRow(&docker.StatsChannel{Container: container})
+
+	if row.GetHeight() != 1 {
+		t.Errorf("Unexpected height of a collapsed row. Expected: %d, got: %d", 1, row.GetHeight())
+	}
+	row.SetExpanded(true)
+	if row.GetHeight() != 6 {
+		t.Errorf("Unexpected height of an expanded row. Expected: %d, got: %d", 6, row.GetHeight())
+	}
+	row.SetWidth(200)
+	volume := row.detail.lines[1].Text
+	if !strings.Contains(volume, "/var/lib/docker/volumes/data/_data -> /data rw") {
+		t.Errorf("Unexpected volume mount line: %s", volume)
+	}
+	if bind := row.detail.lines[2].Text; !strings.HasSuffix(bind, "/etc/hosts -> /etc/hosts ro") {
+		t.Errorf("Unexpected bind mount line: %s", bind)
+	}
+}
+
+func TestStatsRowDetailShowsNetworks(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited",
+		NetworkSettings: &types.SummaryNetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"my_app_net": {IPAddress: "172.18.0.2"},
+			}}}
+	row := NewContainerStatsRow(&docker.StatsChannel{Container: container})
+	d := row.detail
+	d.setWidth(200)
+	if line := d.lines[2].Text; !strings.Contains(line, "my_app_net") || !strings.Contains(line, "172.18.0.2") {
+		t.Errorf("Unexpected network line: %s", line)
+	}
+
+	d.setNetworkStats(&docker.Stats{NetworkRx: 1024, NetworkTx: 2048, Stats: &types.StatsJSON{
+		Networks: map[string]types.NetworkStats{"eth0": {RxBytes: 1024, TxBytes: 2048}}}})
+	if line := d.lines[2].Text; !strings.HasSuffix(line, "1 KiB / 2 KiB") {
+		t.Errorf("Traffic of the only network is not shown: %s", line)
+	}
+
+	d.setNetworkStats(&docker.Stats{Stats: &types.StatsJSON{
+		Networks: map[string]types.NetworkStats{"eth0": {}, "eth1": {}}}})
+	if line := d.lines[2].Text; !strings.HasSuffix(line, "-") {
+		t.Errorf("Traffic that cannot be correlated is shown by network: %s", line)
+	}
+	if title := d.lines[1].Text; !strings.Contains(title, "eth0") || !strings.Contains(title, "eth1") {
+		t.Errorf("Traffic by interface is not shown: %s", title)
+	}
+}
+
+func TestMountLineTruncatesPaths(t *testing.T) {
+	m := types.MountPoint{Type: mount.TypeBind,
+		Source:      "/home/user/projects/a/very/long/path/to/some/data",
+		Destination: "/data"}
+
+	line := mountLine(m, 40)
+	if len([]rune(line)) > 40 {
+		t.Errorf("Mount line does not fit, length: %d, line: %s", len([]rune(line)), line)
+	}
+	if !strings.Contains(line, "…") {
+		t.Errorf("Truncated paths are not marked: %s", line)
+	}
+}
+
+func TestTruncatePath(t *testing.T) {
+	var tests = []struct {
+		path     string
+		length   int
+		expected string
+	}{
+		{"/data", 10, "/data"},
+		{"/var/lib/data", 5, "…data"},
+		{"/var/lib/data", 1, "…"},
+	}
+	for _, test := range tests {
+		if got := truncatePath(test.path, test.length); got != test.expected {
+			t.Errorf("Unexpected truncation of %s. Expected: %s, got: %s", test.path, test.expected, got)
+		}
+	}
+}
+
+func TestStatsRowPorts(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited",
+		Ports: []types.Port{
+			{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+			{IP: "0.0.0.0", PrivatePort: 443, PublicPort: 8443, Type: "tcp"},
+			{PrivatePort: 9000, Type: "udp"},
+		}}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{ShowPorts: true})
+
+	row.SetWidth(400)
+	if row.Ports.Text != "8080:80/tcp,8443:443/tcp,9000/udp" {
+		t.Errorf("Unexpected ports column content: %s", row.Ports.Text)
+	}
+	row.SetWidth(80)
+	if text := row.Ports.Text; !strings.HasSuffix(text, "…") {
+		t.Errorf("Ports were not truncated on a narrow row: %s", text)
+	}
+	ports := row.detail.lines[len(row.detail.lines)-1].Text
+	if !strings.Contains(ports, "8080:80/tcp, 8443:443/tcp, 9000/udp") {
+		t.Errorf("The detail does not show every port: %s", ports)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	if text := truncateText("80:80/tcp", 20); text != "80:80/tcp" {
+		t.Errorf("A text that fits was truncated: %s", text)
+	}
+	if text := truncateText("80:80/tcp,443:443/tcp", 6); text != "80:80…" {
+		t.Errorf("Unexpected truncated text: %s", text)
+	}
+}
+
+func TestRowDetailNote(t *testing.T) {
+	d := newRowDetail(&types.Container{})
+	height := d.GetHeight()
+	d.setNote("known leaker, restart nightly")
+	d.setWidth(80)
+	if d.GetHeight() != height+1 {
+		t.Errorf("Unexpected height of a detail with a note: %d", d.GetHeight())
+	}
+	if note := d.lines[len(d.lines)-1].Text; note != detailIndent+"Note: known leaker, restart nightly" {
+		t.Errorf("Unexpected note line: %s", note)
+	}
+	if ports := d.portsLine().Text; ports != detailIndent+"Ports: none" {
+		t.Errorf("Unexpected ports line: %s", ports)
+	}
+	d.setNote("")
+	if d.GetHeight() != height {
+		t.Errorf("The note line was not removed: %d lines", d.GetHeight())
+	}
+}
+
+func TestRowDetailTimeline(t *testing.T) {
+	d := newRowDetail(&types.Container{})
+	d.setNote("known leaker")
+	height := d.GetHeight()
+	timeline := docker.NewContainerTimeline()
+	d.setTimeline(timeline)
+	d.setWidth(80)
+	if d.GetHeight() != height+1 {
+		t.Errorf("Unexpected height of a detail with a timeline: %d", d.GetHeight())
+	}
+	line := d.lines[d.fixedLines()]
+	if line.Text != detailIndent+"Events: none yet" {
+		t.Errorf("Unexpected line of an empty timeline: %s", line.Text)
+	}
+
+	start := time.Date(2017, 1, 1, 10, 0, 0, 0, time.Local)
+	timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "start", Time: start.Unix()})
+	timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "die", Time: start.Add(time.Minute).Unix(),
+		Actor: events.Actor{Attributes: map[string]string{"exitCode": "137"}}})
+	timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "health_status: healthy", Time: start.Add(2 * time.Minute).Unix()})
+	d.buffer()
+	if expected := detailIndent + "Events: 10:00:00 start, 10:01:00 die (exit 137), 10:02:00 healthy"; line.Text != expected {
+		t.Errorf("Unexpected timeline line: %s, expected: %s", line.Text, expected)
+	}
+	if note := d.lines[len(d.lines)-1].Text; note != detailIndent+"Note: known leaker" {
+		t.Errorf("Unexpected note line: %s", note)
+	}
+
+	line.SetWidth(40)
+	d.setTimelineLine()
+	if expected := detailIndent + "Events: …10:02:00 healthy"; line.Text != expected {
+		t.Errorf("Unexpected timeline line: %s, expected: %s", line.Text, expected)
+	}
+
+	d.setTimeline(nil)
+	if d.GetHeight() != height {
+		t.Errorf("The timeline line was not removed: %d lines", d.GetHeight())
+	}
+}
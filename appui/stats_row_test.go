@@ -1,14 +1,19 @@
 package appui
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	termui "github.com/gizak/termui"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/ui"
+	drytermui "github.com/moncho/dry/ui/termui"
 )
 
 func TestStatsRow(t *testing.T) {
-	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Never worked"}
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Image: "moncho/dry:latest", Status: "Never worked"}
 	sc := &docker.StatsChannel{Container: container}
 
 	row := NewContainerStatsRow(sc)
@@ -19,7 +24,7 @@ func TestStatsRow(t *testing.T) {
 		t.Error("Stats row does not hold a reference to the container.")
 	}
 
-	if len(row.columns) != 7 {
+	if len(row.columns) != 8 {
 		t.Errorf("Stats row does not have the expected number of columns: %d.", len(row.columns))
 	}
 
@@ -31,6 +36,10 @@ func TestStatsRow(t *testing.T) {
 		t.Errorf("Name widget does not contain the container name. Expected: %s, got: %s.", container.Names[0], row.Name.Text)
 	}
 
+	if row.Image.Text != container.Image {
+		t.Errorf("Image widget does not contain the container image. Expected: %s, got: %s.", container.Image, row.Image.Text)
+	}
+
 	if row.CPU.Label != "-" {
 		t.Errorf("CPU widget does not contain the default value. Expected: %s, got: %s.", "-", row.CPU.Label)
 	}
@@ -47,3 +56,634 @@ func TestStatsRow(t *testing.T) {
 		t.Errorf("CPU widget does not contain the default value. Expected: %s, got: %s.", "-", row.Pids.Text)
 	}
 }
+
+func TestStatsRowUpdatedIndicator(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Never worked"}
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container},
+		&StatsRowOptions{ShowUpdated: true})
+
+	if len(row.columns) != 9 {
+		t.Errorf("Stats row does not have the expected number of columns: %d.", len(row.columns))
+	}
+	if !row.LastUpdated().IsZero() {
+		t.Error("No sample has been received but the row has an update time")
+	}
+	now := time.Now()
+	row.lastUpdated = now.Add(-1 * time.Second)
+	row.setUpdated(now)
+	if row.Updated.Text != "1s ago" {
+		t.Errorf("Updated widget does not contain the expected value. Expected: %s, got: %s.", "1s ago", row.Updated.Text)
+	}
+	if row.Updated.TextFgColor == termui.Attribute(ui.Color214) {
+		t.Error("Updated widget is marked as stale on a recent sample")
+	}
+	row.lastUpdated = now.Add(-3 * docker.StatsInterval)
+	row.setUpdated(now)
+	if row.Updated.TextFgColor != termui.Attribute(ui.Color214) {
+		t.Error("Updated widget is not marked as stale")
+	}
+	//rows polled in turns with other 4 containers get a sample every 5 intervals
+	row.setPolled(5, docker.StatsInterval)
+	row.setUpdated(now)
+	if row.Updated.TextFgColor == termui.Attribute(ui.Color214) {
+		t.Error("Updated widget of a polled row is marked as stale before its next sample is due")
+	}
+	row.lastUpdated = now.Add(-11 * docker.StatsInterval)
+	row.setUpdated(now)
+	if row.Updated.TextFgColor != termui.Attribute(ui.Color214) {
+		t.Error("Updated widget of a polled row is not marked as stale")
+	}
+}
+
+func TestStatsRowAppliesLastSampleOnRender(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	stats := make(chan *docker.Stats)
+	row := NewContainerStatsRow(&docker.StatsChannel{Container: container, Stats: stats})
+
+	last := &docker.Stats{CPUPercentage: 20, PidsCurrent: 2}
+	stats <- &docker.Stats{CPUPercentage: 10, PidsCurrent: 1}
+	stats <- last
+	close(stats)
+	//Waits until the row receives the last sample
+	for !row.hasPending(last) {
+		time.Sleep(time.Millisecond)
+	}
+	if row.Pids.Text != pendingText {
+		t.Errorf("Samples were applied before rendering the row, pids: %s", row.Pids.Text)
+	}
+	row.Buffer()
+	if row.Pids.Text != "2" {
+		t.Errorf("Last sample was not applied on render. Expected pids: %s, got: %s.", "2", row.Pids.Text)
+	}
+	if row.CPU.Label != "20.00%" {
+		t.Errorf("Last sample was not applied on render. Expected CPU: %s, got: %s.", "20.00%", row.CPU.Label)
+	}
+}
+
+func TestStatsRowSmoothsGauges(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	stats := make(chan *docker.Stats)
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: stats},
+		&StatsRowOptions{CPUSmoothing: 0.5, MemorySmoothing: 0.5, ShowRawValues: true})
+
+	last := &docker.Stats{CPUPercentage: 60, MemoryPercentage: 60}
+	stats <- &docker.Stats{CPUPercentage: 20, MemoryPercentage: 20}
+	stats <- last
+	close(stats)
+	for !row.hasPending(last) {
+		time.Sleep(time.Millisecond)
+	}
+	row.Buffer()
+	if row.CPU.Percent != 40 {
+		t.Errorf("CPU gauge is not smoothed. Expected: %d, got: %d.", 40, row.CPU.Percent)
+	}
+	if row.Memory.Percent != 40 {
+		t.Errorf("Memory gauge is not smoothed. Expected: %d, got: %d.", 40, row.Memory.Percent)
+	}
+	if row.CPU.Label != "60.00%" {
+		t.Errorf("CPU label does not show the raw value. Expected: %s, got: %s.", "60.00%", row.CPU.Label)
+	}
+}
+
+func (row *ContainerStatsRow) hasPending(stats *docker.Stats) bool {
+	row.RLock()
+	defer row.RUnlock()
+	return row.pending == stats
+}
+
+func TestStatsRowFollowsActiveTheme(t *testing.T) {
+	defer SetActiveTheme("dark256")
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRow(&docker.StatsChannel{Container: container, Stats: make(chan *docker.Stats)})
+	row.Buffer()
+
+	if err := SetActiveTheme("light256"); err != nil {
+		t.Fatalf("Error activating a theme: %s", err.Error())
+	}
+	row.Buffer()
+	if row.Name.Bg != termui.Attribute(Light256.Bg) {
+		t.Errorf("Row background does not follow the active theme. Expected: %d, got: %d", Light256.Bg, row.Name.Bg)
+	}
+	if row.Name.TextFgColor != termui.Attribute(Light256.Fg) {
+		t.Errorf("Row text color does not follow the active theme. Expected: %d, got: %d", Light256.Fg, row.Name.TextFgColor)
+	}
+	if row.CPU.Bg != termui.Attribute(Light256.Bg) {
+		t.Errorf("Gauge background does not follow the active theme. Expected: %d, got: %d", Light256.Bg, row.CPU.Bg)
+	}
+	if SetActiveTheme("nope") == nil {
+		t.Error("Activating an unknown theme did not return an error")
+	}
+}
+
+func TestStatsRowLabelColumns(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second",
+		Labels: map[string]string{"version": "1.2.3"}}
+	options := &StatsRowOptions{Labels: []string{"version", "team"}}
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: make(chan *docker.Stats)}, options)
+
+	if len(row.columns) != 10 {
+		t.Errorf("Unexpected number of columns with two label columns. Expected: %d, got: %d", 10, len(row.columns))
+	}
+	if row.Labels["version"].Text != "1.2.3" {
+		t.Errorf("Unexpected label value. Expected: %s, got: %s", "1.2.3", row.Labels["version"].Text)
+	}
+	if row.Labels["team"].Text != "-" {
+		t.Errorf("A missing label is not shown as a dash, got: %s", row.Labels["team"].Text)
+	}
+	header := options.header()
+	if len(header.pars) != 10 {
+		t.Errorf("Unexpected number of header columns with two label columns. Expected: %d, got: %d", 10, len(header.pars))
+	}
+}
+
+func TestStatsRowResetStats(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	stats := make(chan *docker.Stats)
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: stats},
+		&StatsRowOptions{CPUSmoothing: 0.5, MemorySmoothing: 1})
+
+	first := &docker.Stats{CPUPercentage: 80}
+	stats <- first
+	for !row.hasPending(first) {
+		time.Sleep(time.Millisecond)
+	}
+	row.Buffer()
+	row.ResetStats()
+	if row.CPUPercentageAvg() != 0 {
+		t.Errorf("CPU average was not reset: %f", row.CPUPercentageAvg())
+	}
+	last := &docker.Stats{CPUPercentage: 20}
+	stats <- last
+	close(stats)
+	for !row.hasPending(last) {
+		time.Sleep(time.Millisecond)
+	}
+	row.Buffer()
+	if row.CPUPercentageAvg() != 20 {
+		t.Errorf("CPU average after a reset is not calculated from new samples. Expected: %f, got: %f", 20.0, row.CPUPercentageAvg())
+	}
+	if row.CPU.Label != "20.00%" {
+		t.Errorf("Smoothed CPU after a reset is not calculated from new samples. Expected: %s, got: %s", "20.00%", row.CPU.Label)
+	}
+}
+
+func TestStatsRowUsesMicroGaugesWhenNarrow(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRow(&docker.StatsChannel{Container: container, Stats: make(chan *docker.Stats)})
+	row.setCPU(100)
+
+	cpu := row.columns[3].(*gaugeCell)
+	row.SetWidth(200)
+	if cpu.useMicro() {
+		t.Error("A micro gauge is used on a wide row")
+	}
+	row.SetWidth(60)
+	if !cpu.useMicro() {
+		t.Errorf("A micro gauge is not used on a narrow row, gauge width: %d", row.CPU.Width)
+	}
+	cpu.Buffer()
+	if text := cpu.micro.Text(); text == "" || []rune(text)[0] != '█' {
+		t.Errorf("Micro gauge does not show the CPU usage, got: %q", text)
+	}
+}
+
+func TestStatsRowDerivedColumns(t *testing.T) {
+	options := &StatsRowOptions{}
+	if err := options.AddDerivedColumn("mem_per_pid", "mem / pids"); err != nil {
+		t.Fatalf("Error adding a derived column: %s", err.Error())
+	}
+	if err := options.AddDerivedColumn("broken", "mem / processes"); err == nil {
+		t.Error("A derived column with an invalid expression was added")
+	}
+	if err := options.AddDerivedColumn("mem_per_pid", "mem"); err == nil {
+		t.Error("A derived column with a duplicated name was added")
+	}
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: make(chan *docker.Stats)}, options)
+
+	row.apply(&docker.Stats{Memory: 1000, PidsCurrent: 4})
+	if text := row.Derived["mem_per_pid"].Text; text != "250.00" {
+		t.Errorf("Unexpected derived column value. Expected: %s, got: %s", "250.00", text)
+	}
+	row.apply(&docker.Stats{Memory: 1000})
+	if text := row.Derived["mem_per_pid"].Text; text != "-" {
+		t.Errorf("A division by zero is not shown as a dash, got: %s", text)
+	}
+}
+
+func TestStatsRowDerivedColumnWithNoExpression(t *testing.T) {
+	options := &StatsRowOptions{DerivedColumns: []*DerivedColumn{{Name: "raw", Expression: "mem"}}}
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: make(chan *docker.Stats)}, options)
+
+	row.apply(&docker.Stats{Memory: 1000})
+	if text := row.Derived["raw"].Text; text != "-" {
+		t.Errorf("A derived column with no expression is not shown as a dash, got: %s", text)
+	}
+}
+
+func TestStatsRowShading(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRow(&docker.StatsChannel{Container: container, Stats: make(chan *docker.Stats)})
+	row.SetWidth(100)
+
+	altBg := termui.Attribute(DryTheme.AltBg)
+	if cell := row.Buffer().At(row.Name.X, row.Name.Y); cell.Bg == altBg {
+		t.Error("An even row is shaded")
+	}
+	row.SetOdd(true)
+	if cell := row.Buffer().At(row.Name.X, row.Name.Y); cell.Bg != altBg {
+		t.Errorf("An odd row is not shaded. Expected background: %d, got: %d", altBg, cell.Bg)
+	}
+}
+
+func TestStatsRowLimits(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited"}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{ShowLimits: true})
+
+	if len(row.columns) != 10 {
+		t.Errorf("Stats row does not have the expected number of columns: %d.", len(row.columns))
+	}
+	if row.CPULimit.Text != pendingText {
+		t.Errorf("Limits are not pending before being loaded: %s", row.CPULimit.Text)
+	}
+	row.SetLimits(docker.ContainerLimits{CPUs: 1.5, Memory: 512 * 1024 * 1024})
+	if row.CPULimit.Text != "1.5 cores" || row.MemoryLimit.Text != "512 MiB" {
+		t.Errorf("Unexpected limits: %s, %s", row.CPULimit.Text, row.MemoryLimit.Text)
+	}
+	row.SetLimits(docker.ContainerLimits{})
+	if row.CPULimit.Text != "unlimited" || row.MemoryLimit.Text != "unlimited" {
+		t.Errorf("Unexpected limits of an unlimited container: %s, %s", row.CPULimit.Text, row.MemoryLimit.Text)
+	}
+	row.SetLimits(docker.ContainerLimits{RestartPolicy: "on-failure:3"})
+	if row.RestartPolicy.Text != "on-failure:3" {
+		t.Errorf("Unexpected restart policy: %s", row.RestartPolicy.Text)
+	}
+}
+
+func TestStatsRowOptionalColumns(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"/web.2.x8jm9bs0c7wq"}, Status: "Exited",
+		Labels: map[string]string{"com.docker.swarm.service.name": "web", "com.docker.swarm.task.name": "web.2.x8jm9bs0c7wq"}}
+	tests := []struct {
+		name    string
+		options *StatsRowOptions
+		column  func(row *ContainerStatsRow) *drytermui.ParColumn
+		text    string
+	}{
+		{"cpuset", &StatsRowOptions{ShowCPUSet: true}, func(row *ContainerStatsRow) *drytermui.ParColumn { return row.CPUSet }, pendingText},
+		{"started-at", &StatsRowOptions{ShowStartedAt: true}, func(row *ContainerStatsRow) *drytermui.ParColumn { return row.StartedAt }, pendingText},
+		{"size-rw", &StatsRowOptions{ShowWritableLayer: true}, func(row *ContainerStatsRow) *drytermui.ParColumn { return row.WritableLayer }, pendingText},
+		{"replica", &StatsRowOptions{ShowSwarmReplica: true}, func(row *ContainerStatsRow) *drytermui.ParColumn { return row.SwarmReplica }, "web.2"},
+		{"nofile", &StatsRowOptions{ShowNoFile: true}, func(row *ContainerStatsRow) *drytermui.ParColumn { return row.NoFile }, pendingText},
+	}
+	for _, test := range tests {
+		row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, test.options)
+		if len(row.columns) != 9 {
+			t.Errorf("Stats row with the %s column does not have the expected number of columns: %d.", test.name, len(row.columns))
+		}
+		column := test.column(row)
+		shown := false
+		for _, c := range row.columns {
+			shown = shown || c == termui.GridBufferer(column)
+		}
+		if !shown {
+			t.Errorf("The %s column is not shown", test.name)
+		}
+		if column.Text != test.text {
+			t.Errorf("Unexpected initial text of the %s column. Expected: %s, got: %s", test.name, test.text, column.Text)
+		}
+	}
+}
+
+func TestStatsRowCPUSet(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited"}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{ShowCPUSet: true})
+	row.SetLimits(docker.ContainerLimits{CPUSet: "0-3"})
+	if row.CPUSet.Text != "0-3" {
+		t.Errorf("Unexpected CPU set: %s", row.CPUSet.Text)
+	}
+	row.SetLimits(docker.ContainerLimits{})
+	if row.CPUSet.Text != "all" {
+		t.Errorf("Unexpected CPU set of a container not pinned to any CPU: %s", row.CPUSet.Text)
+	}
+}
+
+func TestStatsRowStartedAt(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited"}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container},
+		&StatsRowOptions{ShowStartedAt: true, StartedAtLayout: "15:04:05"})
+	started := time.Date(2024, 3, 1, 10, 20, 30, 0, time.Local)
+	row.SetLimits(docker.ContainerLimits{StartedAt: started})
+	if row.StartedAt.Text != "10:20:30" {
+		t.Errorf("Unexpected start time: %s", row.StartedAt.Text)
+	}
+	row.SetLimits(docker.ContainerLimits{})
+	if row.StartedAt.Text != "-" {
+		t.Errorf("Unexpected start time of a container that has not started: %s", row.StartedAt.Text)
+	}
+
+	row = NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{ShowStartedAt: true})
+	row.SetLimits(docker.ContainerLimits{StartedAt: started})
+	if row.StartedAt.Text != "2024-03-01 10:20" {
+		t.Errorf("Unexpected start time with the default layout: %s", row.StartedAt.Text)
+	}
+}
+
+func TestStatsRowWritableLayerSize(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited"}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{ShowWritableLayer: true})
+	var tests = []struct {
+		size     int64
+		text     string
+		expected termui.Attribute
+	}{
+		{10 * 1024, "10 KiB", termui.Attribute(DryTheme.Fg)},
+		{200 * 1024 * 1024, "200 MiB", termui.Attribute(DryTheme.GaugeLevels[1])},
+		{2 * 1024 * 1024 * 1024, "2 GiB", termui.Attribute(DryTheme.GaugeLevels[2])},
+	}
+	for _, test := range tests {
+		row.SetWritableLayerSize(test.size)
+		if row.WritableLayer.Text != test.text || row.WritableLayer.TextFgColor != test.expected {
+			t.Errorf("Unexpected writable layer of %d bytes: %s, color %d", test.size, row.WritableLayer.Text, row.WritableLayer.TextFgColor)
+		}
+	}
+	row.writableLayerUnavailable()
+	if row.WritableLayer.Text != notApplicableText {
+		t.Errorf("Unexpected writable layer size when unavailable: %s", row.WritableLayer.Text)
+	}
+}
+
+func TestStatsRowStale(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	stats := make(chan *docker.Stats)
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: stats},
+		&StatsRowOptions{StaleTimeout: 5 * time.Second})
+	defer close(stats)
+
+	now := time.Now()
+	row.checkStale(now)
+	if row.stale {
+		t.Error("Row with no samples yet is stale")
+	}
+	row.lastUpdated = now.Add(-4 * time.Second)
+	row.checkStale(now)
+	if row.stale {
+		t.Error("Row is stale before the stale timeout")
+	}
+	row.lastUpdated = now.Add(-6 * time.Second)
+	row.checkStale(now)
+	if !row.stale || row.Name.Text != staleMarker+"Name" {
+		t.Errorf("Row is not marked as stale after the stale timeout, name: %s", row.Name.Text)
+	}
+	row.lastUpdated = now
+	row.checkStale(now)
+	if row.stale || row.Name.Text != "Name" {
+		t.Errorf("Row is still stale after a new sample, name: %s", row.Name.Text)
+	}
+}
+
+func TestStatsRowBackedOffPolling(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowFor(container, &StatsRowOptions{StaleTimeout: 3 * docker.StatsInterval})
+	now := time.Now()
+	row.update(&docker.Stats{CPUPercentage: 1, Interval: 10 * docker.StatsInterval}, now)
+
+	//the next sample of an idle container is expected in 10 intervals
+	later := now.Add(5 * docker.StatsInterval)
+	if _, _, ok := row.sample(later); !ok {
+		t.Error("The sample of a container polled less often is not current")
+	}
+	row.checkStale(later)
+	if row.stale {
+		t.Error("Row polled less often is stale before its next sample is due")
+	}
+	row.checkStale(now.Add(31 * docker.StatsInterval))
+	if !row.stale {
+		t.Error("Row polled less often is not stale after its stale timeout")
+	}
+	if _, _, ok := row.sample(now.Add(21 * docker.StatsInterval)); ok {
+		t.Error("The sample of a container polled less often is current after two intervals")
+	}
+
+	//busy containers are polled every stats interval again
+	row.update(&docker.Stats{CPUPercentage: 50, Interval: docker.StatsInterval}, now)
+	if _, _, ok := row.sample(later); ok {
+		t.Error("The sample of a container polled every interval is current after five intervals")
+	}
+}
+
+func TestStatsRowUpdatedManually(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowFor(container, DefaultStatsRowOptions)
+	if row.Pids.Text != pendingText {
+		t.Errorf("Row of a running container is not pending its first sample, pids: %s", row.Pids.Text)
+	}
+	now := time.Now()
+	row.update(&docker.Stats{PidsCurrent: 2, BlockRead: 100}, now)
+	row.update(&docker.Stats{PidsCurrent: 3, BlockRead: 300}, now.Add(2*time.Second))
+	row.Buffer()
+	if row.Pids.Text != "3" {
+		t.Errorf("Last sample was not applied on render. Expected pids: %s, got: %s.", "3", row.Pids.Text)
+	}
+	if latest := row.Latest(); latest.PidsCurrent != 3 || row.rates.BlockRead != 100 {
+		t.Errorf("Unexpected sample and rates after updating the row: %+v, %+v", latest, row.rates)
+	}
+}
+
+func TestStatsRowIODisplay(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowFor(container, DefaultStatsRowOptions)
+	now := time.Now()
+	row.update(&docker.Stats{NetworkRx: 1024, BlockRead: 2048}, now)
+	row.update(&docker.Stats{NetworkRx: 3072, BlockRead: 4096}, now.Add(2*time.Second))
+	row.Buffer()
+	if row.Net.Text != "3 KiB / 0 B" || row.Block.Text != "4 KiB / 0 B" {
+		t.Errorf("Unexpected IO totals, net: %s, block: %s", row.Net.Text, row.Block.Text)
+	}
+
+	row.SetIODisplay(IOPerSecond)
+	row.Buffer()
+	if row.Net.Text != "1 KiB/s / 0 B/s" || row.Block.Text != "1 KiB/s / 0 B/s" {
+		t.Errorf("Unexpected IO rates, net: %s, block: %s", row.Net.Text, row.Block.Text)
+	}
+}
+
+func TestStatsRowCPUGaugeScale(t *testing.T) {
+	options := *DefaultStatsRowOptions
+	if err := options.SetCPUGaugeScale("400"); err != nil {
+		t.Fatalf("Unexpected error setting the CPU gauge scale: %s", err)
+	}
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowFor(container, &options)
+	row.setCPU(200)
+	if row.CPU.Percent != 50 || row.CPU.Label != "200.00%" {
+		t.Errorf("Unexpected CPU gauge on a four cores scale: %d, label: %s", row.CPU.Percent, row.CPU.Label)
+	}
+	row.SetCPUScale(DefaultCPUGaugeScale)
+	row.setCPU(200)
+	if row.CPU.Percent != 100 {
+		t.Errorf("CPU gauge on a single core scale is not full: %d", row.CPU.Percent)
+	}
+
+	for _, scale := range []string{"cores", "0", "-100"} {
+		if err := options.SetCPUGaugeScale(scale); err == nil {
+			t.Errorf("Invalid CPU gauge scale %s was accepted", scale)
+		}
+	}
+	if err := options.SetCPUGaugeScale("host"); err != nil || !options.CPUGaugeHostScale {
+		t.Errorf("The CPU gauge is not scaled to the host, error: %v", err)
+	}
+}
+
+func TestStatsRowResetWhileUpdating(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	stats := make(chan *docker.Stats)
+	row := NewContainerStatsRowWithOptions(
+		&docker.StatsChannel{Container: container, Stats: stats}, DefaultStatsRowOptions)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			stats <- &docker.Stats{CPUPercentage: float64(i), MemoryPercentage: float64(i), PidsCurrent: uint64(i)}
+		}
+		close(stats)
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			row.Reset()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		row.Buffer()
+	}
+	wg.Wait()
+	for !row.StreamEnded() {
+		time.Sleep(time.Millisecond)
+	}
+
+	row.Reset()
+	row.Buffer()
+	if row.CPU.Percent != 0 || row.Pids.Text != "-" {
+		t.Errorf("A sample received before the reset was shown: cpu %d%%, pids %s", row.CPU.Percent, row.Pids.Text)
+	}
+}
+
+func TestStatsRowComposeServiceName(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"/shop_web_1"}, Status: "Exited",
+		Labels: map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.service": "web"}}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container},
+		&StatsRowOptions{NameDisplay: docker.NameProjectService})
+	if row.Name.Text != "shop/web" {
+		t.Errorf("Unexpected name of a Compose service container: %s", row.Name.Text)
+	}
+}
+
+func TestStatsRowSwarmReplica(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"/web.2.x8jm9bs0c7wq"}, Status: "Exited",
+		Labels: map[string]string{"com.docker.swarm.service.name": "web", "com.docker.swarm.task.name": "web.2.x8jm9bs0c7wq"}}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{ShowSwarmReplica: true})
+	if row.SwarmReplica.Text != "web.2" {
+		t.Errorf("Unexpected swarm replica: %s", row.SwarmReplica.Text)
+	}
+	container = &types.Container{ID: "CID", Names: []string{"/standalone"}, Status: "Exited"}
+	row = NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{ShowSwarmReplica: true})
+	if row.SwarmReplica.Text != "-" {
+		t.Errorf("Unexpected swarm replica of a container that is not a swarm task: %s", row.SwarmReplica.Text)
+	}
+}
+
+func TestStatsRowHostMemoryGauge(t *testing.T) {
+	options := *DefaultStatsRowOptions
+	options.ShowHostMemory = true
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowFor(container, &options)
+	if len(row.columns) != len(DefaultStatsRowOptions.columns())+1 {
+		t.Errorf("The host memory gauge was not added, columns: %d", len(row.columns))
+	}
+	row.setHostMem(512)
+	if row.HostMemory.Label != "-" {
+		t.Errorf("Unexpected host memory gauge with an unknown host memory: %s", row.HostMemory.Label)
+	}
+	row.SetHostMemory(2048)
+	row.setMem(512, 1024, 50)
+	row.setHostMem(512)
+	if row.Memory.Percent != 50 || row.HostMemory.Percent != 25 {
+		t.Errorf("Unexpected memory gauges. Limit: %d, host: %d", row.Memory.Percent, row.HostMemory.Percent)
+	}
+	if row.HostMemory.Label != "25.0% of host" {
+		t.Errorf("Unexpected host memory gauge label: %s", row.HostMemory.Label)
+	}
+}
+
+func TestStatsRowCPUSplit(t *testing.T) {
+	options := *DefaultStatsRowOptions
+	options.ShowCPUSplit = true
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Up 1 second"}
+	row := NewContainerStatsRowFor(container, &options)
+	if len(row.columns) != len(DefaultStatsRowOptions.columns())+1 {
+		t.Errorf("The CPU split column was not added, columns: %d", len(row.columns))
+	}
+	row.apply(&docker.Stats{CPUPercentage: 50, CPUUserPercentage: 75, CPUSystemPercentage: 25})
+	if row.CPUSplit.Text != "75% / 25%" {
+		t.Errorf("Unexpected CPU split: %s", row.CPUSplit.Text)
+	}
+	row.apply(&docker.Stats{})
+	if row.CPUSplit.Text != "-" {
+		t.Errorf("Unexpected CPU split with no CPU used: %s", row.CPUSplit.Text)
+	}
+}
+
+func TestStatsRowNoFile(t *testing.T) {
+	container := &types.Container{ID: "CID", Names: []string{"Name"}, Status: "Exited"}
+	row := NewContainerStatsRowWithOptions(&docker.StatsChannel{Container: container}, &StatsRowOptions{ShowNoFile: true})
+	tests := []struct {
+		nofile   *docker.Ulimit
+		expected string
+	}{
+		{nil, "default"},
+		{&docker.Ulimit{Soft: 1024, Hard: 4096}, "1024/4096"},
+		{&docker.Ulimit{Soft: 65536, Hard: 65536}, "65536"},
+		{&docker.Ulimit{Soft: 1024, Hard: -1}, "1024/unlimited"},
+	}
+	for _, test := range tests {
+		row.SetLimits(docker.ContainerLimits{NoFile: test.nofile})
+		if row.NoFile.Text != test.expected {
+			t.Errorf("Unexpected open files limit. Expected: %s, got: %s", test.expected, row.NoFile.Text)
+		}
+	}
+}
+
+func TestShowColumns(t *testing.T) {
+	options := &StatsRowOptions{}
+	if err := options.ShowColumns("fds", "unknown"); err == nil {
+		t.Error("Unknown columns were accepted")
+	}
+	if options.ShowOpenFDs {
+		t.Error("Columns were shown despite the error")
+	}
+	if err := options.ShowColumns("updated", "nofile", "fds"); err != nil {
+		t.Fatal(err)
+	}
+	if !options.ShowUpdated || !options.ShowNoFile || !options.ShowOpenFDs || options.ShowPorts {
+		t.Errorf("Unexpected columns shown: %+v", options)
+	}
+	for _, name := range OptionalColumns() {
+		o := &StatsRowOptions{}
+		if err := o.ShowColumns(name); err != nil {
+			t.Fatal(err)
+		}
+		if len(o.columns()) <= len((&StatsRowOptions{}).columns()) {
+			t.Errorf("Column %s does not add a column to the monitor", name)
+		}
+	}
+}
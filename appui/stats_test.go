@@ -1,7 +1,11 @@
 package appui
 
-import "testing"
-import drydocker "github.com/moncho/dry/docker"
+import (
+	"strings"
+	"testing"
+
+	drydocker "github.com/moncho/dry/docker"
+)
 
 func TestNewDockerStatsBufferer(t *testing.T) {
 	stats := &drydocker.Stats{
@@ -13,3 +17,12 @@ func TestNewDockerStatsBufferer(t *testing.T) {
 		t.Error("Unexpected bufferer length")
 	}
 }
+
+func TestStatsRendererTopUnsupported(t *testing.T) {
+	stats := &drydocker.Stats{
+		TopUnsupported: true,
+	}
+	if rendered := NewDockerStatsRenderer(stats).Render(); !strings.Contains(rendered, topUnsupportedText) {
+		t.Errorf("Unsupported top is not shown, got: %s", rendered)
+	}
+}
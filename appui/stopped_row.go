@@ -0,0 +1,30 @@
+package appui
+
+import (
+	"fmt"
+
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/ui"
+	drytermui "github.com/moncho/dry/ui/termui"
+)
+
+//stoppedContainersRow is the row that summarizes the stopped containers
+//of the monitor, whose rows are collapsed or shown above it
+type stoppedContainersRow struct {
+	*drytermui.ParColumn
+}
+
+func newStoppedContainersRow(count int, expanded bool) *stoppedContainersRow {
+	containers := "containers"
+	if count == 1 {
+		containers = "container"
+	}
+	text := fmt.Sprintf("▸ %d stopped %s", count, containers)
+	if expanded {
+		text = fmt.Sprintf("▴ %d stopped %s, shown above", count, containers)
+	}
+	p := drytermui.NewThemedParColumn(DryTheme, text)
+	p.Height = 1
+	p.TextFgColor = termui.Attribute(ui.Color244)
+	return &stoppedContainersRow{p}
+}
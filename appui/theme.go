@@ -1,11 +1,16 @@
 package appui
 
-import "github.com/moncho/dry/ui"
+import (
+	"fmt"
+
+	"github.com/moncho/dry/ui"
+)
 
 //Default16 default theme for 16-color mode
 var Default16 = &ui.ColorTheme{
 	Fg:           ui.ColorWhite,
 	Bg:           ui.ColorBlack,
+	AltBg:        ui.ColorBlack,
 	DarkBg:       ui.ColorBlack,
 	Prompt:       ui.ColorBlue,
 	Key:          ui.ColorGreen,
@@ -16,12 +21,14 @@ var Default16 = &ui.ColorTheme{
 	Cursor:       ui.ColorRed,
 	Selected:     ui.ColorPurple,
 	Header:       ui.ColorLime,
-	Footer:       ui.ColorLime}
+	Footer:       ui.ColorLime,
+	GaugeLevels:  [3]ui.Color{ui.ColorGreen, ui.ColorYellow, ui.ColorRed}}
 
 //Black256 black bg theme for 256-color mode
 var Black256 = &ui.ColorTheme{
 	Fg:           ui.Color255,
 	Bg:           ui.ColorBlack,
+	AltBg:        ui.Color233,
 	DarkBg:       ui.ColorBlack,
 	Prompt:       ui.Color110,
 	Key:          ui.Color108,
@@ -32,12 +39,14 @@ var Black256 = &ui.ColorTheme{
 	Cursor:       ui.Color161,
 	Selected:     ui.Color168,
 	Header:       ui.Color25,
-	Footer:       ui.Color25}
+	Footer:       ui.Color25,
+	GaugeLevels:  [3]ui.Color{ui.Color23, ui.Color131, ui.Color161}}
 
 //Dark256 dark theme for 256-color mode
 var Dark256 = &ui.ColorTheme{
 	Fg:           ui.Color255,
 	Bg:           ui.Color234,
+	AltBg:        ui.Color235,
 	DarkBg:       ui.ColorBlack,
 	Prompt:       ui.Color110,
 	Key:          ui.Color108,
@@ -48,12 +57,14 @@ var Dark256 = &ui.ColorTheme{
 	Cursor:       ui.Color161,
 	Selected:     ui.Color168,
 	Header:       ui.Color25,
-	Footer:       ui.Color25}
+	Footer:       ui.Color25,
+	GaugeLevels:  [3]ui.Color{ui.Color23, ui.Color131, ui.Color161}}
 
 //Light256 light theme for 256-color mode
 var Light256 = &ui.ColorTheme{
 	Fg:           ui.Color241,
 	Bg:           ui.Color231,
+	AltBg:        ui.Color255,
 	DarkBg:       ui.Color251,
 	Prompt:       ui.Color25,
 	Key:          ui.Color66,
@@ -64,20 +75,57 @@ var Light256 = &ui.ColorTheme{
 	Cursor:       ui.Color161,
 	Selected:     ui.Color168,
 	Header:       ui.Color31,
-	Footer:       ui.Color31}
+	Footer:       ui.Color31,
+	GaugeLevels:  [3]ui.Color{ui.Color23, ui.Color131, ui.Color161}}
+
+//ColorBlind256 is the dark theme for 256-color mode with gauges that go from
+//blue to orange, instead of from green to red, safe for red-green color blindness.
+var ColorBlind256 = colorBlind(Dark256)
+
+func colorBlind(theme *ui.ColorTheme) *ui.ColorTheme {
+	c := copyOf(theme)
+	c.GaugeLevels = [3]ui.Color{ui.Color33, ui.Color214, ui.Color202}
+	return c
+}
+
+//themes are the color themes that can be activated, by name
+var themes = map[string]*ui.ColorTheme{
+	"default16":     Default16,
+	"black256":      Black256,
+	"dark256":       Dark256,
+	"light256":      Light256,
+	"colorblind256": ColorBlind256,
+}
 
-//DryTheme is the active theme for dry
-var DryTheme = Dark256
+//DryTheme is the active theme for dry. Components keep a reference to it, so
+//it is never replaced, activating a theme copies the theme colors on it.
+var DryTheme = copyOf(Dark256)
 
 //ColorThemes holds the list of dry color themes
 var ColorThemes = []*ui.ColorTheme{Black256, Dark256}
 
+//SetActiveTheme activates the color theme with the given name. Components
+//created with DryTheme show the new colors the next time they are rendered.
+func SetActiveTheme(name string) error {
+	theme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("Unknown color theme: %s", name)
+	}
+	*DryTheme = *theme
+	return nil
+}
+
 //RotateColorTheme changes the color theme to the next one in the
 //rotation order.
 func RotateColorTheme() {
-	if DryTheme == ColorThemes[0] {
-		DryTheme = ColorThemes[1]
+	if *DryTheme == *ColorThemes[0] {
+		*DryTheme = *ColorThemes[1]
 	} else {
-		DryTheme = ColorThemes[0]
+		*DryTheme = *ColorThemes[0]
 	}
 }
+
+func copyOf(theme *ui.ColorTheme) *ui.ColorTheme {
+	c := *theme
+	return &c
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/gizak/termui"
+	"github.com/moncho/dry/docker"
 	"github.com/moncho/dry/ui"
 )
 
@@ -17,6 +18,10 @@ const (
 	minimumHeight = 3
 )
 
+//topUnsupportedText is shown instead of the process list of a container when
+//the daemon does not support top
+const topUnsupportedText = "unsupported on this daemon"
+
 type topRenderer struct {
 	processList *types.ContainerProcessList
 }
@@ -97,6 +102,16 @@ func NewDockerTopBufferer(processList *types.ContainerProcessList, x, y, height,
 	return ui.NewPar("", DryTheme), 0
 }
 
+//processListBufferer creates termui bufferer for the process list of the given stats,
+//a notice is shown if the process list is not available as top is unsupported.
+func processListBufferer(stats *docker.Stats, x, y, height, width int) (termui.Bufferer, int) {
+	if stats.TopUnsupported {
+		return NewDockerTopBufferer(
+			&types.ContainerProcessList{Titles: []string{topUnsupportedText}}, x, y, height, width)
+	}
+	return NewDockerTopBufferer(stats.ProcessList, x, y, height, width)
+}
+
 type sortByPID [][]string
 
 func (s sortByPID) Len() int {
@@ -0,0 +1,288 @@
+package docker
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+//DefaultDiscoveryInterval is the default time between two checks of the
+//running containers of a Collector
+const DefaultDiscoveryInterval = 5 * StatsInterval
+
+//containerSource is what a Collector needs from a Docker daemon
+type containerSource interface {
+	Refresh(allContainers bool) error
+	ContainerStore() *ContainerStore
+	OpenChannel(container *types.Container) *StatsChannel
+}
+
+//Collector collects the stats of the running containers of a Docker daemon and
+//delivers them to its subscribers, it has no UI. Running containers are discovered
+//periodically: a stats channel is opened for each new container and the channels
+//of containers that are no longer running are closed. The stats of containers over
+//the maximum number of concurrent streams, if set, are polled.
+type Collector struct {
+	source            containerSource
+	discoveryInterval time.Duration
+	subscribers       []func(*Stats)
+	channels          map[string]*StatsChannel
+	//latest are the last samples collected of each container, by container ID
+	latest  map[string]*collectedStats
+	stop    chan struct{}
+	running bool
+	//idle is true if no running containers were found on the last discovery
+	idle bool
+	wg   sync.WaitGroup
+	//goroutines is the number of goroutines started by the collector that are running
+	goroutines int32
+	//watchdog decides when to restart the collection as streams keep failing
+	watchdog Watchdog
+	//maxStreams is the maximum number of stats channels open, the stats
+	//of the rest of running containers are polled, in turns, using the sampler
+	maxStreams   int
+	sampler      statsSampler
+	polled       []*types.Container
+	nextPoll     int
+	pollInterval time.Duration
+	sync.RWMutex
+}
+
+//NewCollector creates a Collector of the stats of the containers of the given daemon
+func NewCollector(daemon ContainerDaemon) *Collector {
+	return newCollector(daemon, DefaultDiscoveryInterval)
+}
+
+func newCollector(source containerSource, discoveryInterval time.Duration) *Collector {
+	c := &Collector{
+		source:            source,
+		discoveryInterval: discoveryInterval,
+		channels:          make(map[string]*StatsChannel),
+		latest:            make(map[string]*collectedStats),
+		watchdog:          Watchdog{policy: DefaultWatchdogPolicy},
+		pollInterval:      StatsInterval}
+	if sampler, ok := source.(statsSampler); ok {
+		c.sampler = sampler
+	}
+	return c
+}
+
+//Subscribe registers the given function to be called with each stats sample
+//collected, functions are called from the collection goroutines so they must
+//not block.
+func (c *Collector) Subscribe(f func(*Stats)) {
+	c.Lock()
+	defer c.Unlock()
+	c.subscribers = append(c.subscribers, f)
+}
+
+//Start starts collecting stats, it does nothing if the collector is already
+//running.
+func (c *Collector) Start() {
+	c.Lock()
+	if c.running {
+		c.Unlock()
+		return
+	}
+	c.running = true
+	c.stop = make(chan struct{})
+	if c.maxStreams > 0 && c.sampler != nil {
+		c.started()
+		go c.poll(c.stop)
+	}
+	c.started()
+	go func(stop <-chan struct{}) {
+		defer c.finished()
+		ticker := time.NewTicker(c.discoveryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.reconcile()
+			case <-stop:
+				return
+			}
+		}
+	}(c.stop)
+	c.Unlock()
+	c.reconcile()
+}
+
+//Stop stops collecting stats, it returns once every stats channel has been
+//closed.
+func (c *Collector) Stop() {
+	c.Lock()
+	if !c.running {
+		c.Unlock()
+		return
+	}
+	c.running = false
+	close(c.stop)
+	for id, channel := range c.channels {
+		close(channel.Done)
+		delete(c.channels, id)
+		delete(c.latest, id)
+	}
+	c.polled = nil
+	c.Unlock()
+	c.wg.Wait()
+}
+
+//reconcile opens a stats channel for each running container with no channel, and
+//closes the channels of the containers that are no longer running. Running
+//containers are polled if no more channels can be opened.
+//The daemon is checked without holding the lock of the collector, so a slow
+//daemon does not block the delivery of stats, the lock is only held to apply
+//the containers found.
+func (c *Collector) reconcile() {
+	if err := c.source.Refresh(false); err != nil {
+		return
+	}
+	containers := c.source.ContainerStore().Filter(ContainerFilters.ByRunningState(true))
+	c.Lock()
+	defer c.Unlock()
+	if !c.running {
+		return
+	}
+	c.apply(containers)
+}
+
+//apply opens and closes stats channels so that there is one for each of the given
+//running containers, or the container is polled.
+//Must be called with the collector locked.
+func (c *Collector) apply(containers []*types.Container) {
+	running := make(map[string]bool)
+	for _, container := range containers {
+		running[container.ID] = true
+	}
+	for id, channel := range c.channels {
+		if !running[id] {
+			close(channel.Done)
+			delete(c.channels, id)
+			delete(c.latest, id)
+		}
+	}
+	var polled []*types.Container
+	for _, container := range containers {
+		if _, ok := c.channels[container.ID]; ok {
+			continue
+		}
+		if c.streamsExhausted() {
+			polled = append(polled, container)
+			continue
+		}
+		channel := c.source.OpenChannel(container)
+		if channel.Stats == nil {
+			continue
+		}
+		c.channels[container.ID] = channel
+		c.started()
+		go c.deliver(channel)
+	}
+	//polled containers have no stream whose end forgets their last samples
+	for _, container := range c.polled {
+		if !running[container.ID] {
+			delete(c.latest, container.ID)
+		}
+	}
+	c.polled = polled
+	c.idle = len(running) == 0
+}
+
+//deliver delivers the stats received on the given channel to the subscribers
+//until the channel is closed.
+func (c *Collector) deliver(channel *StatsChannel) {
+	defer c.finished()
+	received := false
+	for stats := range channel.Stats {
+		received = true
+		c.publish(channel.Container, stats)
+	}
+	//The stream has ended, the container has probably stopped, a new
+	//channel is opened on discovery if it is still running.
+	//A stream closed by the collector might have been replaced already, only
+	//the current stream forgets the samples of its container.
+	c.Lock()
+	restart := false
+	if c.channels[channel.Container.ID] == channel {
+		delete(c.latest, channel.Container.ID)
+		delete(c.channels, channel.Container.ID)
+		close(channel.Done)
+		//only streams that ended on their own, not closed by the collector, fail
+		if !received || streamError(channel) != nil {
+			restart = c.streamFailed(time.Now())
+		}
+	}
+	c.Unlock()
+	if restart {
+		c.reconcile()
+	}
+}
+
+//streamError returns the error that ended the stream of the given channel, if any
+func streamError(channel *StatsChannel) error {
+	select {
+	case err := <-channel.Errors:
+		return err
+	default:
+		return nil
+	}
+}
+
+//publish records the given stats sample of the given container and delivers
+//it to the subscribers
+func (c *Collector) publish(container *types.Container, stats *Stats) {
+	c.record(container, stats, time.Now())
+	c.RLock()
+	subscribers := c.subscribers
+	c.RUnlock()
+	for _, f := range subscribers {
+		f(stats)
+	}
+}
+
+func (c *Collector) started() {
+	c.wg.Add(1)
+	atomic.AddInt32(&c.goroutines, 1)
+}
+
+func (c *Collector) finished() {
+	atomic.AddInt32(&c.goroutines, -1)
+	c.wg.Done()
+}
+
+//Diagnostics returns the state of the stats channels opened by the collector and
+//the number of goroutines running to collect and deliver stats.
+func (c *Collector) Diagnostics() Diagnostics {
+	c.RLock()
+	defer c.RUnlock()
+	d := Diagnostics{
+		OpenChannels: len(c.channels),
+		Goroutines:   int(atomic.LoadInt32(&c.goroutines))}
+	for _, channel := range c.channels {
+		s, running := streamDiagnostics(channel)
+		d.Streams = append(d.Streams, s)
+		if running {
+			d.Goroutines++
+		}
+	}
+	return d
+}
+
+//Idle returns true if there were no running containers when the collector last
+//checked them, stats are collected as soon as a container is found running on
+//a later check.
+func (c *Collector) Idle() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.idle
+}
+
+//Channels returns the number of stats channels open
+func (c *Collector) Channels() int {
+	c.RLock()
+	defer c.RUnlock()
+	return len(c.channels)
+}
@@ -0,0 +1,267 @@
+package docker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+//fakeSource is a container source whose stats channels send a sample
+//with the container ID until done
+type fakeSource struct {
+	containers []*types.Container
+	sync.Mutex
+}
+
+func (s *fakeSource) Refresh(allContainers bool) error {
+	return nil
+}
+
+func (s *fakeSource) ContainerStore() *ContainerStore {
+	s.Lock()
+	defer s.Unlock()
+	return NewMemoryStoreWithContainers(s.containers)
+}
+
+func (s *fakeSource) OpenChannel(container *types.Container) *StatsChannel {
+	stats := make(chan *Stats)
+	done := make(chan struct{})
+	go func() {
+		defer close(stats)
+		for {
+			select {
+			case stats <- &Stats{CID: container.ID}:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return &StatsChannel{Container: container, Stats: stats, Done: done}
+}
+
+func TestCollector(t *testing.T) {
+	source := &fakeSource{containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+		{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+		{ID: "3", Names: []string{"/cache"}, Status: "Exited (0) 1 hour ago"},
+	}}
+	c := newCollector(source, time.Hour)
+	var lock sync.Mutex
+	received := make(map[string]bool)
+	c.Subscribe(func(s *Stats) {
+		lock.Lock()
+		defer lock.Unlock()
+		received[s.CID] = true
+	})
+	c.Start()
+	defer c.Stop()
+
+	if c.Channels() != 2 {
+		t.Errorf("Unexpected number of open channels: %d", c.Channels())
+	}
+	//Discovery, plus a producer and a deliverer per stream
+	if d := c.Diagnostics(); d.Goroutines != 5 || len(d.Streams) != 2 {
+		t.Errorf("Unexpected diagnostics: %s", d)
+	}
+	for {
+		lock.Lock()
+		done := received["1"] && received["2"]
+		lock.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	lock.Lock()
+	if received["3"] {
+		t.Error("Stats of a stopped container were collected")
+	}
+	lock.Unlock()
+
+	source.Lock()
+	source.containers = source.containers[1:]
+	source.Unlock()
+	c.reconcile()
+	if c.Channels() != 1 {
+		t.Errorf("Channel of a container no longer running was not closed, channels: %d", c.Channels())
+	}
+
+	c.Stop()
+	if c.Channels() != 0 {
+		t.Errorf("Channels were not closed on stop: %d", c.Channels())
+	}
+}
+
+func TestCollectorStartsWithNoRunningContainers(t *testing.T) {
+	source := &fakeSource{}
+	c := newCollector(source, time.Hour)
+	received := make(chan string, 1)
+	c.Subscribe(func(s *Stats) {
+		select {
+		case received <- s.CID:
+		default:
+		}
+	})
+	c.Start()
+	defer c.Stop()
+
+	if !c.Idle() || c.Channels() != 0 {
+		t.Errorf("Collector with no running containers is not idle, channels: %d", c.Channels())
+	}
+
+	source.Lock()
+	source.containers = []*types.Container{{ID: "1", Names: []string{"/web"}, Status: "Up 1 second"}}
+	source.Unlock()
+	c.reconcile()
+	if c.Idle() || c.Channels() != 1 {
+		t.Errorf("Collector did not pick up the container that started, channels: %d", c.Channels())
+	}
+	if id := <-received; id != "1" {
+		t.Errorf("Unexpected stats received: %s", id)
+	}
+}
+
+//slowSource is a container source whose refreshes, once started is set, block
+//until released
+type slowSource struct {
+	fakeSource
+	started  chan bool
+	released chan bool
+}
+
+func (s *slowSource) Refresh(allContainers bool) error {
+	if s.started != nil {
+		s.started <- true
+		<-s.released
+	}
+	return nil
+}
+
+func TestCollectorDeliversStatsWhileRefreshing(t *testing.T) {
+	source := &slowSource{fakeSource: fakeSource{containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 second"},
+	}}}
+	c := newCollector(source, time.Hour)
+	delivered := make(chan string, 1)
+	c.Subscribe(func(s *Stats) {
+		select {
+		case delivered <- s.CID:
+		default:
+		}
+	})
+	c.Start()
+	defer c.Stop()
+
+	source.started, source.released = make(chan bool), make(chan bool)
+	go c.reconcile()
+	<-source.started
+	//drops the samples delivered before the refresh started
+	select {
+	case <-delivered:
+	default:
+	}
+	select {
+	case id := <-delivered:
+		if id != "1" {
+			t.Errorf("Unexpected stats delivered: %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Error("No stats were delivered while the daemon was refreshed")
+	}
+	close(source.released)
+}
+
+func TestCollectorsSnapshot(t *testing.T) {
+	now := time.Now()
+	web := &types.Container{ID: "2", Names: []string{"/web"}}
+	db := &types.Container{ID: "1", Names: []string{"/db"}}
+	cache := &types.Container{ID: "3", Names: []string{"/cache"}}
+	local, remote := newCollector(&fakeSource{}, time.Hour), newCollector(&fakeSource{}, time.Hour)
+	local.record(web, &Stats{CPUPercentage: 10}, now)
+	local.record(web, &Stats{CPUPercentage: 20}, now.Add(time.Second))
+	local.record(db, &Stats{CPUPercentage: 50}, now)
+	remote.record(cache, &Stats{CPUPercentage: 5}, now)
+
+	snapshot := local.Snapshot()
+	if len(snapshot) != 2 || snapshot[0].Container != db || snapshot[1].Stats.CPUPercentage != 20 {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+	if snapshot := (Collectors{local, remote}).Snapshot(); len(snapshot) != 3 || snapshot[2].Container != cache {
+		t.Errorf("Unexpected snapshot of several collectors: %+v", snapshot)
+	}
+}
+
+func TestCollectorTopN(t *testing.T) {
+	c := newCollector(&fakeSource{}, time.Hour)
+	now := time.Now()
+	web := &types.Container{ID: "1", Names: []string{"/web"}}
+	db := &types.Container{ID: "2", Names: []string{"/db"}}
+	cache := &types.Container{ID: "3", Names: []string{"/cache"}}
+	c.record(web, &Stats{CPUPercentage: 10, Memory: 300, NetworkRx: 100}, now)
+	c.record(web, &Stats{CPUPercentage: 20, Memory: 300, NetworkRx: 1100}, now.Add(time.Second))
+	c.record(db, &Stats{CPUPercentage: 50, Memory: 100, NetworkRx: 100}, now)
+	c.record(db, &Stats{CPUPercentage: 50, Memory: 100, NetworkRx: 200}, now.Add(time.Second))
+	c.record(cache, &Stats{CPUPercentage: 50, Memory: 200}, now)
+
+	top := c.TopN(MetricCPU, 2)
+	if len(top) != 2 || top[0].ContainerName != "db" || top[1].ContainerName != "cache" {
+		t.Errorf("Unexpected top containers by CPU: %+v", top)
+	}
+	top = c.TopN(MetricMemory, 10)
+	if len(top) != 3 || top[0].ContainerName != "web" || top[2].ContainerName != "db" {
+		t.Errorf("Unexpected top containers by memory: %+v", top)
+	}
+	top = c.TopN(MetricNetworkRate, 1)
+	if len(top) != 1 || top[0].ContainerName != "web" {
+		t.Errorf("Unexpected top container by network rate: %+v", top)
+	}
+	if top := c.TopN(MetricCPU, 0); len(top) != 0 {
+		t.Errorf("Containers returned for a top 0: %+v", top)
+	}
+	if top := c.TopN(MetricCPU, -1); len(top) != 0 {
+		t.Errorf("Containers returned for a negative top: %+v", top)
+	}
+	if top := newCollector(&fakeSource{}, time.Hour).TopN(MetricCPU, 3); len(top) != 0 {
+		t.Errorf("Containers returned with no stats collected: %+v", top)
+	}
+}
+
+func TestCollectorTopNWhileRecording(t *testing.T) {
+	c := newCollector(&fakeSource{}, time.Hour)
+	web := &types.Container{ID: "1", Names: []string{"/web"}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			c.record(web, &Stats{CPUPercentage: float64(i)}, time.Now())
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		c.TopN(MetricCPU, 1)
+	}
+	<-done
+}
+
+func TestCollectorKeepsSamplesOfReopenedStreams(t *testing.T) {
+	c := newCollector(&fakeSource{}, time.Hour)
+	web := &types.Container{ID: "1", Names: []string{"/web"}}
+	ended := make(chan *Stats)
+	close(ended)
+	previous := &StatsChannel{Container: web, Stats: ended, Done: make(chan struct{})}
+	current := &StatsChannel{Container: web, Stats: ended, Done: make(chan struct{})}
+	c.channels[web.ID] = current
+	c.record(web, &Stats{CPUPercentage: 10}, time.Now())
+
+	c.started()
+	c.deliver(previous)
+	if top := c.TopN(MetricCPU, 1); len(top) != 1 {
+		t.Errorf("The end of a replaced stream removed the samples of the current one: %+v", top)
+	}
+	c.started()
+	c.deliver(current)
+	if top := c.TopN(MetricCPU, 1); len(top) != 0 {
+		t.Errorf("The samples of an ended stream were kept: %+v", top)
+	}
+}
@@ -0,0 +1,166 @@
+package docker
+
+import (
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+//Metric is a stats value by which containers can be ranked
+type Metric int
+
+//Metrics by which containers can be ranked, rates are per second
+const (
+	MetricCPU Metric = iota
+	MetricMemory
+	MetricMemoryPercentage
+	MetricNetworkRate
+	MetricBlockIORate
+	MetricPids
+)
+
+//collectedStats are the last two stats samples received of a container,
+//rates are calculated from them
+type collectedStats struct {
+	container   *types.Container
+	latest      *Stats
+	latestAt    time.Time
+	previous    *Stats
+	previousAt  time.Time
+	networkRate float64
+	blockIORate float64
+}
+
+//add keeps the given sample, received at the given time, as the latest one
+func (s *collectedStats) add(stats *Stats, now time.Time) {
+	s.previous, s.previousAt = s.latest, s.latestAt
+	s.latest, s.latestAt = stats, now
+	s.networkRate, s.blockIORate = 0, 0
+	if s.previous == nil {
+		return
+	}
+	elapsed := s.latestAt.Sub(s.previousAt).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	s.networkRate = perSecond(s.latest.NetworkRx+s.latest.NetworkTx, s.previous.NetworkRx+s.previous.NetworkTx, elapsed)
+	s.blockIORate = perSecond(s.latest.BlockRead+s.latest.BlockWrite, s.previous.BlockRead+s.previous.BlockWrite, elapsed)
+}
+
+//perSecond returns the rate of a counter, counters that are reset, i.e. on
+//a container restart, have no rate
+func perSecond(current, previous, elapsed float64) float64 {
+	if current < previous {
+		return 0
+	}
+	return (current - previous) / elapsed
+}
+
+//value returns the value of the given metric
+func (s *collectedStats) value(m Metric) float64 {
+	switch m {
+	case MetricMemory:
+		return s.latest.Memory
+	case MetricMemoryPercentage:
+		return s.latest.MemoryPercentage
+	case MetricNetworkRate:
+		return s.networkRate
+	case MetricBlockIORate:
+		return s.blockIORate
+	case MetricPids:
+		return float64(s.latest.PidsCurrent)
+	}
+	return s.latest.CPUPercentage
+}
+
+//record keeps the given sample of the given container, received at the given time
+func (c *Collector) record(container *types.Container, stats *Stats, now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	collected, ok := c.latest[container.ID]
+	if !ok {
+		collected = &collectedStats{container: container}
+		c.latest[container.ID] = collected
+	}
+	collected.add(stats, now)
+}
+
+//TopN returns the records of the n containers with the highest value of the given
+//metric, highest first, containers with the same value are ordered by ID. Only
+//the samples already collected are used, the daemon is not called.
+func (c *Collector) TopN(metric Metric, n int) []StatsRecord {
+	if n < 0 {
+		n = 0
+	}
+	//samples are copied while locked, new samples replace them concurrently
+	c.RLock()
+	ranked := byMetricValue{}
+	for _, collected := range c.latest {
+		ranked.snapshots = append(ranked.snapshots, StatsSnapshot{Container: collected.container, Stats: collected.latest})
+		ranked.values = append(ranked.values, collected.value(metric))
+	}
+	c.RUnlock()
+	sort.Sort(ranked)
+	if n < len(ranked.snapshots) {
+		ranked.snapshots = ranked.snapshots[:n]
+	}
+	records := make([]StatsRecord, 0, len(ranked.snapshots))
+	for _, snapshot := range ranked.snapshots {
+		records = append(records, snapshot.Record())
+	}
+	return records
+}
+
+//Snapshot returns the last stats sample collected of each container, ordered by
+//container ID
+func (c *Collector) Snapshot() []StatsSnapshot {
+	c.RLock()
+	snapshots := make([]StatsSnapshot, 0, len(c.latest))
+	for _, collected := range c.latest {
+		snapshots = append(snapshots, StatsSnapshot{Container: collected.container, Stats: collected.latest})
+	}
+	c.RUnlock()
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Container.ID < snapshots[j].Container.ID
+	})
+	return snapshots
+}
+
+//Collectors are the stats collectors of several Docker hosts
+type Collectors []*Collector
+
+//Snapshot returns the last stats sample collected of each container of every host
+func (c Collectors) Snapshot() []StatsSnapshot {
+	var snapshots []StatsSnapshot
+	for _, collector := range c {
+		snapshots = append(snapshots, collector.Snapshot()...)
+	}
+	return snapshots
+}
+
+//Stop stops every collector
+func (c Collectors) Stop() {
+	for _, collector := range c {
+		collector.Stop()
+	}
+}
+
+//byMetricValue sorts stats snapshots by the value of a metric, highest first,
+//and then by container ID
+type byMetricValue struct {
+	snapshots []StatsSnapshot
+	values    []float64
+}
+
+func (a byMetricValue) Len() int { return len(a.snapshots) }
+func (a byMetricValue) Swap(i, j int) {
+	a.snapshots[i], a.snapshots[j] = a.snapshots[j], a.snapshots[i]
+	a.values[i], a.values[j] = a.values[j], a.values[i]
+}
+func (a byMetricValue) Less(i, j int) bool {
+	if a.values[i] != a.values[j] {
+		return a.values[i] > a.values[j]
+	}
+	return a.snapshots[i].Container.ID < a.snapshots[j].Container.ID
+}
@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//WatchdogPolicy decides when a Collector, or a monitor, as a last resort, tears
+//down every stats channel it opened and opens them again, so that it recovers from
+//a daemon that fails persistently, i.e. because its connection pool is exhausted.
+type WatchdogPolicy struct {
+	//MaxFailures is the number of stats streams failing in Window that makes
+	//the collector restart, 0 disables the watchdog
+	MaxFailures int
+	//Window is the period over which failures are counted, the collector is
+	//not restarted more than once per Window
+	Window time.Duration
+}
+
+//DefaultWatchdogPolicy restarts a collector if ten streams fail in thirty seconds
+var DefaultWatchdogPolicy = WatchdogPolicy{MaxFailures: 10, Window: 30 * StatsInterval}
+
+//Watchdog counts the stats streams that failed, a stream fails if it ends with
+//an error or without sending a sample. It is not safe for concurrent use.
+type Watchdog struct {
+	policy      WatchdogPolicy
+	failures    []time.Time
+	lastRestart time.Time
+	restarts    int
+}
+
+//NewWatchdog creates a Watchdog that follows the given policy
+func NewWatchdog(policy WatchdogPolicy) *Watchdog {
+	return &Watchdog{policy: policy}
+}
+
+//Failed records a stream that failed at the given time, it returns true if every
+//stream must be opened again
+func (w *Watchdog) Failed(now time.Time) bool {
+	if w.policy.MaxFailures <= 0 {
+		return false
+	}
+	w.failures = append(w.failures, now)
+	recent := w.failures[:0]
+	for _, t := range w.failures {
+		if now.Sub(t) < w.policy.Window {
+			recent = append(recent, t)
+		}
+	}
+	w.failures = recent
+	if len(w.failures) < w.policy.MaxFailures ||
+		(!w.lastRestart.IsZero() && now.Sub(w.lastRestart) < w.policy.Window) {
+		return false
+	}
+	w.failures = nil
+	w.lastRestart = now
+	w.restarts++
+	return true
+}
+
+//Restarts returns the number of times the Watchdog decided that every stream
+//had to be opened again
+func (w *Watchdog) Restarts() int {
+	return w.restarts
+}
+
+//SetWatchdogPolicy sets when the collector restarts because too many of its
+//stats streams fail, DefaultWatchdogPolicy is used if not set.
+func (c *Collector) SetWatchdogPolicy(policy WatchdogPolicy) {
+	c.Lock()
+	defer c.Unlock()
+	c.watchdog.policy = policy
+}
+
+//Restarts returns the number of times the collector has been restarted by its watchdog
+func (c *Collector) Restarts() int {
+	c.RLock()
+	defer c.RUnlock()
+	return c.watchdog.Restarts()
+}
+
+//streamFailed records that a stats stream ended with an error or without sending
+//a sample, the whole collection is restarted if the watchdog decides so. It returns
+//true if the collection was restarted, the channels are opened again by reconcile.
+//Must be called with the collector locked.
+func (c *Collector) streamFailed(now time.Time) bool {
+	if !c.running || !c.watchdog.Failed(now) {
+		return false
+	}
+	log.Warnf("%d stats streams failed in %s, restarting the stats collection",
+		c.watchdog.policy.MaxFailures, c.watchdog.policy.Window)
+	c.restart()
+	return true
+}
+
+//restart closes every stats channel, reconcile opens them again.
+//Must be called with the collector locked.
+func (c *Collector) restart() {
+	for id, channel := range c.channels {
+		close(channel.Done)
+		delete(c.channels, id)
+	}
+	c.latest = make(map[string]*collectedStats)
+}
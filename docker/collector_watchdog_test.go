@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+//failingSource is a container source whose stats channels end without sending a sample
+type failingSource struct {
+	fakeSource
+	opened chan string
+}
+
+func (s *failingSource) OpenChannel(container *types.Container) *StatsChannel {
+	s.opened <- container.ID
+	stats := make(chan *Stats)
+	close(stats)
+	return &StatsChannel{Container: container, Stats: stats, Done: make(chan struct{})}
+}
+
+func TestWatchdog(t *testing.T) {
+	w := NewWatchdog(WatchdogPolicy{MaxFailures: 3, Window: time.Minute})
+	now := time.Now()
+	if w.Failed(now) || w.Failed(now.Add(time.Second)) {
+		t.Error("Watchdog restarted before reaching the maximum number of failures")
+	}
+	if w.Failed(now.Add(2 * time.Minute)) {
+		t.Error("Watchdog counted failures out of its window")
+	}
+	if w.Failed(now.Add(2*time.Minute+time.Second)) || !w.Failed(now.Add(2*time.Minute+2*time.Second)) {
+		t.Error("Watchdog did not restart on the maximum number of failures")
+	}
+	for i := 0; i < 3; i++ {
+		if w.Failed(now.Add(2*time.Minute + 3*time.Second)) {
+			t.Error("Watchdog restarted twice in its window")
+		}
+	}
+	if w.Restarts() != 1 {
+		t.Errorf("Unexpected number of restarts: %d", w.Restarts())
+	}
+
+	disabled := NewWatchdog(WatchdogPolicy{})
+	for i := 0; i < 10; i++ {
+		if disabled.Failed(now) {
+			t.Error("A disabled watchdog restarted")
+		}
+	}
+}
+
+func TestCollectorRestartsOnFailingStreams(t *testing.T) {
+	source := &failingSource{opened: make(chan string, 10)}
+	source.containers = []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+		{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+	}
+	c := newCollector(source, time.Hour)
+	c.SetWatchdogPolicy(WatchdogPolicy{MaxFailures: 2, Window: time.Hour})
+	c.Start()
+	defer c.Stop()
+
+	timeout := time.After(time.Second)
+	for opened := 0; opened < 4; opened++ {
+		select {
+		case <-source.opened:
+		case <-timeout:
+			t.Fatalf("Stats channels were not opened again, opened: %d", opened)
+		}
+	}
+	if restarts := c.Restarts(); restarts != 1 {
+		t.Errorf("Unexpected number of restarts: %d", restarts)
+	}
+}
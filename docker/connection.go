@@ -2,11 +2,14 @@ package docker
 
 import (
 	"crypto/tls"
+	"io"
 	"net"
 	"net/http"
 	"path/filepath"
 	"time"
 
+	"golang.org/x/net/context"
+
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/opts"
 	"github.com/docker/go-connections/sockets"
@@ -91,7 +94,16 @@ func newHTTPClient(host string, config *tls.Config) (*http.Client, error) {
 
 //ConnectToDaemon connects to a Docker daemon using the given properties.
 func ConnectToDaemon(env *Env) (*DockerDaemon, error) {
+	client, err := newAPIClient(env)
+	if err != nil {
+		return nil, err
+	}
+	return connect(client, env)
+}
 
+//newAPIClient creates a client of the Docker daemon described by the given
+//properties, TLS certificates are read each time a client is created.
+func newAPIClient(env *Env) (client.APIClient, error) {
 	host, err := getServerHost(env)
 	if err != nil {
 		return nil, errors.Wrap(err, "Invalid Host")
@@ -131,9 +143,45 @@ func ConnectToDaemon(env *Env) (*DockerDaemon, error) {
 		return nil, errors.Wrap(err, "HttpClient creation error")
 	}
 
-	client, err := client.NewClient(host, env.DockerAPIVersion, httpClient, headers)
-	if err == nil {
-		return connect(client, env)
+	apiClient, err := client.NewClient(host, env.DockerAPIVersion, httpClient, headers)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating client")
+	}
+	return apiClient, nil
+}
+
+//apiClient returns the client used to talk to the Docker daemon
+func (daemon *DockerDaemon) apiClient() client.APIClient {
+	daemon.clientLock.RLock()
+	defer daemon.clientLock.RUnlock()
+	return daemon.client
+}
+
+//ReloadTLS reads again the TLS certificates used to connect to the Docker daemon
+//and replaces the client used to talk to it. Requests already started, such as
+//stats streams, keep using the previous certificates until they are opened again.
+//Nothing is done if the connection does not use TLS.
+func (daemon *DockerDaemon) ReloadTLS() error {
+	env := daemon.dockerEnv
+	if env == nil || (env.DockerCertPath == "" && !env.DockerTLSVerify) {
+		return nil
+	}
+	apiClient, err := newAPIClient(env)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	if _, err := apiClient.Ping(ctx); err != nil {
+		return errors.Wrap(err, "Cannot connect to the Docker daemon with the new certificates")
+	}
+	daemon.clientLock.Lock()
+	previous := daemon.client
+	daemon.client = apiClient
+	daemon.clientLock.Unlock()
+	//Closes the idle connections of the previous client, those in use are closed once done
+	if c, ok := previous.(io.Closer); ok {
+		c.Close()
 	}
-	return nil, errors.Wrap(err, "Error creating client")
+	return nil
 }
@@ -0,0 +1,37 @@
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestReloadTLSWithoutTLS(t *testing.T) {
+	client := createClient()
+	daemon := &DockerDaemon{client: client, dockerEnv: &Env{DockerHost: "unix:///var/run/docker.sock"}}
+	if err := daemon.ReloadTLS(); err != nil {
+		t.Errorf("Unexpected error reloading TLS on a connection with no TLS: %s", err)
+	}
+	if daemon.apiClient() != client {
+		t.Error("The client of a connection with no TLS was replaced")
+	}
+}
+
+func TestReloadTLSKeepsClientOnError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dry-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	client := createClient()
+	daemon := &DockerDaemon{client: client, dockerEnv: &Env{
+		DockerHost:      "tcp://127.0.0.1:2376",
+		DockerCertPath:  dir,
+		DockerTLSVerify: true}}
+	if err := daemon.ReloadTLS(); err == nil {
+		t.Error("Reloading TLS with no certificates did not fail")
+	}
+	if daemon.apiClient() != client {
+		t.Error("The client was replaced after failing to reload TLS")
+	}
+}
@@ -78,6 +78,31 @@ func (c *ContainerFormatter) Image() string {
 	return c.c.Image
 }
 
+//ShortImage returns the image used by the container as repo:tag, without
+//the registry and with digests and image IDs shortened.
+func (c *ContainerFormatter) ShortImage() string {
+	c.addHeader(imageHeader)
+	image := c.c.Image
+	if image == "" {
+		return "<no image>"
+	}
+	//Containers created from an image ID
+	if strings.HasPrefix(image, "sha256:") {
+		return TruncateID(image)
+	}
+	var digest string
+	if i := strings.Index(image, "@"); i >= 0 {
+		image, digest = image[:i], image[i+1:]
+	}
+	if parts := strings.SplitN(image, "/", 2); len(parts) == 2 && isRegistry(parts[0]) {
+		image = parts[1]
+	}
+	if digest != "" {
+		return image + "@" + TruncateID(digest)
+	}
+	return image
+}
+
 //Command prettifies the command that starts the container
 func (c *ContainerFormatter) Command() string {
 	c.addHeader(commandHeader)
@@ -153,14 +178,28 @@ func (c *ContainerFormatter) addHeader(header string) {
 	c.header = append(c.header, strings.ToUpper(header))
 }
 
+//isRegistry returns true if the given image name component is a registry host
+func isRegistry(s string) bool {
+	return s == "localhost" || strings.ContainsAny(s, ".:")
+}
+
+//stripNamePrefix returns the given container names without their leading slash,
+//the given slice is not modified as it usually belongs to a container.
 func stripNamePrefix(ss []string) []string {
+	names := make([]string, len(ss))
 	for i, s := range ss {
-		if s[0] == '/' {
-			ss[i] = s[1:]
-		}
+		names[i] = strings.TrimPrefix(s, "/")
 	}
+	return names
+}
 
-	return ss
+//ContainerName returns the first name of the given container without its
+//leading slash, its truncated ID if it has no name
+func ContainerName(c *types.Container) string {
+	if len(c.Names) == 0 {
+		return TruncateID(c.ID)
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
 }
 
 //DisplayablePorts formats the given ports information for displaying
@@ -209,6 +248,59 @@ func DisplayablePorts(ports []types.Port) string {
 	return strings.Join(result, ", ")
 }
 
+//CompactPorts formats the given ports as a list of "hostPort:containerPort/proto"
+//entries, or "containerPort/proto" if the port is not published. The IP of
+//published ports is omitted and consecutive ports are collapsed into ranges.
+func CompactPorts(ports []types.Port) []string {
+	type portRange struct {
+		private, public int
+		length          int
+		proto           string
+	}
+	sorted := make([]types.Port, len(ports))
+	copy(sorted, ports)
+	sort.Sort(byPortInfo(sorted))
+
+	var ranges []*portRange
+	seen := make(map[types.Port]bool)
+	for _, port := range sorted {
+		//The same port is usually published on several IPs, i.e. IPv4 and IPv6
+		port.IP = ""
+		if seen[port] {
+			continue
+		}
+		seen[port] = true
+		private, public := int(port.PrivatePort), int(port.PublicPort)
+		if len(ranges) > 0 {
+			last := ranges[len(ranges)-1]
+			next := private == last.private+last.length
+			if next && last.proto == port.Type &&
+				((public == 0 && last.public == 0) || (last.public != 0 && public == last.public+last.length)) {
+				last.length++
+				continue
+			}
+		}
+		ranges = append(ranges, &portRange{private: private, public: public, length: 1, proto: port.Type})
+	}
+	var result []string
+	for _, r := range ranges {
+		private := portOrRange(r.private, r.length)
+		if r.public == 0 {
+			result = append(result, fmt.Sprintf("%s/%s", private, r.proto))
+		} else {
+			result = append(result, fmt.Sprintf("%s:%s/%s", portOrRange(r.public, r.length), private, r.proto))
+		}
+	}
+	return result
+}
+
+func portOrRange(first, length int) string {
+	if length == 1 {
+		return strconv.Itoa(first)
+	}
+	return fmt.Sprintf("%d-%d", first, first+length-1)
+}
+
 // byPortInfo is a temporary type used to sort types.Port by its fields
 type byPortInfo []types.Port
 
@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestShortImageFormatting(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"", "<no image>"},
+		{"nginx", "nginx"},
+		{"nginx:1.10.0-alpine", "nginx:1.10.0-alpine"},
+		{"moncho/dry:latest", "moncho/dry:latest"},
+		{"localhost:5000/nginx:1.10.0-alpine", "nginx:1.10.0-alpine"},
+		{"quay.io/coreos/etcd:v3.1.0", "coreos/etcd:v3.1.0"},
+		{"nginx@sha256:0fe6413f3e30fcc5920bc8fa769280975b10b1c26721de956e1428b9e2f29d04", "nginx@0fe6413f3e"},
+		{"sha256:0fe6413f3e30fcc5920bc8fa769280975b10b1c26721de956e1428b9e2f29d04", "0fe6413f3e"},
+	}
+	for _, test := range tests {
+		formatter := NewContainerFormatter(&types.Container{Image: test.image}, true)
+		if image := formatter.ShortImage(); image != test.expected {
+			t.Errorf("Image %s was not formatted as expected. Expected: %s, got: %s", test.image, test.expected, image)
+		}
+	}
+}
+
+func TestCompactPorts(t *testing.T) {
+	ports := []types.Port{
+		{IP: "0.0.0.0", PrivatePort: 8080, PublicPort: 80, Type: "tcp"},
+		{IP: "::", PrivatePort: 8080, PublicPort: 80, Type: "tcp"},
+		{PrivatePort: 9002, Type: "tcp"},
+		{PrivatePort: 9000, Type: "tcp"},
+		{PrivatePort: 9001, Type: "tcp"},
+		{IP: "0.0.0.0", PrivatePort: 5000, PublicPort: 6000, Type: "udp"},
+		{IP: "0.0.0.0", PrivatePort: 5001, PublicPort: 6001, Type: "udp"},
+		{IP: "0.0.0.0", PrivatePort: 5002, PublicPort: 7000, Type: "udp"},
+	}
+	expected := []string{"6000-6001:5000-5001/udp", "7000:5002/udp", "80:8080/tcp", "9000-9002/tcp"}
+	result := CompactPorts(ports)
+	if len(result) != len(expected) {
+		t.Fatalf("Unexpected ports. Expected: %v, got: %v", expected, result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Unexpected ports. Expected: %v, got: %v", expected, result)
+			break
+		}
+	}
+}
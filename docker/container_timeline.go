@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"golang.org/x/net/context"
+)
+
+//ContainerTimelineCapacity is the number of events kept by a ContainerTimeline
+const ContainerTimelineCapacity = 8
+
+//lifecycleActions are the actions of the container events kept on a timeline,
+//health status events have the new status after the action name.
+var lifecycleActions = []string{
+	"create", "start", "restart", "pause", "unpause",
+	"kill", "oom", "die", "stop", "destroy", "health_status"}
+
+//ContainerTimeline keeps the most recent lifecycle events of a container
+type ContainerTimeline struct {
+	log *EventLog
+	sync.RWMutex
+}
+
+//NewContainerTimeline creates an empty timeline
+func NewContainerTimeline() *ContainerTimeline {
+	log := &EventLog{}
+	log.Init(ContainerTimelineCapacity)
+	return &ContainerTimeline{log: log}
+}
+
+//Push adds the given event to the timeline if it is a lifecycle event,
+//i.e. the container was started or it became unhealthy.
+func (t *ContainerTimeline) Push(event *events.Message) {
+	if !isLifecycleEvent(event) {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.log.Push(event)
+}
+
+//Events returns the events of the timeline, from the oldest to the most recent one
+func (t *ContainerTimeline) Events() []events.Message {
+	t.RLock()
+	defer t.RUnlock()
+	return t.log.Events()
+}
+
+func isLifecycleEvent(event *events.Message) bool {
+	if event.Type != events.ContainerEventType {
+		return false
+	}
+	for _, action := range lifecycleActions {
+		if event.Action == action || strings.HasPrefix(event.Action, action+":") {
+			return true
+		}
+	}
+	return false
+}
+
+func timelineEvents(t *ContainerTimeline) eventProcessor {
+	return func(event events.Message) error {
+		t.Push(&event)
+		return nil
+	}
+}
+
+//ContainerTimeline subscribes to the events of the given container, the returned
+//timeline keeps its lifecycle events since the container was created, as long
+//as the Docker daemon still has them, until the returned channel is closed.
+func (daemon *DockerDaemon) ContainerTimeline(c *dockerTypes.Container) (*ContainerTimeline, chan<- struct{}, error) {
+	args := filters.NewArgs()
+	args.Add("type", events.ContainerEventType)
+	args.Add("container", c.ID)
+	options := dockerTypes.EventsOptions{
+		Since:   strconv.FormatInt(c.Created, 10),
+		Filters: args,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	messages, errs := daemon.apiClient().Events(ctx, options)
+
+	timeline := NewContainerTimeline()
+	done := make(chan struct{})
+	go func() {
+		defer cancel()
+		for {
+			select {
+			case event := <-messages:
+				if err := handleEvent(ctx, event, timelineEvents(timeline)); err != nil {
+					return
+				}
+			case <-errs:
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+	return timeline, done, nil
+}
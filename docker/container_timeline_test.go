@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestContainerTimeline(t *testing.T) {
+	timeline := NewContainerTimeline()
+	timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "create"})
+	timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "attach"})
+	timeline.Push(&events.Message{Type: events.NetworkEventType, Action: "connect"})
+	timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "start"})
+	timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "health_status: unhealthy"})
+	timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "exec_start: sh"})
+
+	var actions []string
+	for _, e := range timeline.Events() {
+		actions = append(actions, e.Action)
+	}
+	expected := []string{"create", "start", "health_status: unhealthy"}
+	if len(actions) != len(expected) {
+		t.Fatalf("Unexpected timeline events: %v, expected: %v", actions, expected)
+	}
+	for i, action := range expected {
+		if actions[i] != action {
+			t.Errorf("Unexpected timeline events: %v, expected: %v", actions, expected)
+		}
+	}
+}
+
+func TestContainerTimelineKeepsRecentEvents(t *testing.T) {
+	timeline := NewContainerTimeline()
+	for i := 0; i < ContainerTimelineCapacity+2; i++ {
+		timeline.Push(&events.Message{Type: events.ContainerEventType, Action: "restart", Time: int64(i)})
+	}
+	events := timeline.Events()
+	if len(events) != ContainerTimelineCapacity {
+		t.Fatalf("Unexpected number of events: %d", len(events))
+	}
+	if events[0].Time != 2 || events[len(events)-1].Time != ContainerTimelineCapacity+1 {
+		t.Errorf("The oldest events were not dropped, first: %d, last: %d",
+			events[0].Time, events[len(events)-1].Time)
+	}
+}
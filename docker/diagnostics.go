@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+//StreamState is the state of the stats stream of a StatsChannel
+type StreamState int32
+
+//Stream states
+const (
+	//StreamWaiting means that the stream is waiting to be opened, streams are rate limited
+	StreamWaiting StreamState = iota
+	//StreamOpen means that stats are being received
+	StreamOpen
+	//StreamEnded means that no more stats are received on the channel
+	StreamEnded
+)
+
+func (s StreamState) String() string {
+	switch s {
+	case StreamWaiting:
+		return "waiting"
+	case StreamOpen:
+		return "streaming"
+	case StreamEnded:
+		return "ended"
+	}
+	return "unknown"
+}
+
+//streamState holds the state of a stream, it is safe for concurrent use
+type streamState struct {
+	state int32
+}
+
+func (s *streamState) set(state StreamState) {
+	atomic.StoreInt32(&s.state, int32(state))
+}
+
+func (s *streamState) get() StreamState {
+	return StreamState(atomic.LoadInt32(&s.state))
+}
+
+//StreamDiagnostics describes the stats stream of a container
+type StreamDiagnostics struct {
+	ContainerID string
+	State       StreamState
+}
+
+//Diagnostics describes the stats streams opened and the goroutines running to
+//collect and deliver stats, it helps to find leaks.
+type Diagnostics struct {
+	OpenChannels int
+	Goroutines   int
+	Streams      []StreamDiagnostics
+}
+
+//String returns a one-line summary of this Diagnostics
+func (d Diagnostics) String() string {
+	count := make(map[StreamState]int)
+	for _, s := range d.Streams {
+		count[s.State]++
+	}
+	return fmt.Sprintf("%d open channels, %d goroutines, streams: %d %s, %d %s, %d %s",
+		d.OpenChannels, d.Goroutines,
+		count[StreamOpen], StreamOpen, count[StreamWaiting], StreamWaiting, count[StreamEnded], StreamEnded)
+}
+
+//streamDiagnostics returns the diagnostics of the stream of the given channel and
+//whether the goroutine producing its stats is running.
+func streamDiagnostics(channel *StatsChannel) (StreamDiagnostics, bool) {
+	state := channel.State()
+	return StreamDiagnostics{ContainerID: channel.Container.ID, State: state}, state != StreamEnded
+}
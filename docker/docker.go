@@ -38,6 +38,7 @@ var defaultImageListOptions = dockerTypes.ImageListOptions{
 //DockerDaemon knows how to talk to the Docker daemon
 type DockerDaemon struct {
 	client         dockerAPI.APIClient //client used to to connect to the Docker daemon
+	clientLock     sync.RWMutex
 	containerStore *ContainerStore
 	images         []dockerTypes.ImageSummary
 	networks       []dockerTypes.NetworkResource
@@ -47,6 +48,10 @@ type DockerDaemon struct {
 	version        *dockerTypes.Version
 	refreshLock    sync.Mutex
 	eventLog       *EventLog
+	host           hostInfo
+	limits         limitsCache
+	limiter        *rateLimiter
+	limiterOnce    sync.Once
 }
 
 func init() {
@@ -95,7 +100,7 @@ func (daemon *DockerDaemon) ContainerByID(cid string) *dockerTypes.Container {
 func (daemon *DockerDaemon) DiskUsage() (dockerTypes.DiskUsage, error) {
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
-	return daemon.client.DiskUsage(ctx)
+	return daemon.apiClient().DiskUsage(ctx)
 }
 
 //DockerEnv returns Docker-related environment variables
@@ -110,7 +115,7 @@ func (daemon *DockerDaemon) Events() (<-chan dockerEvents.Message, chan<- struct
 	//Since: time.Now().String(),
 	}
 	ctx, cancel := context.WithCancel(context.Background())
-	events, err := daemon.client.Events(ctx, options)
+	events, err := daemon.apiClient().Events(ctx, options)
 
 	eventC := make(chan dockerEvents.Message)
 	done := make(chan struct{})
@@ -124,6 +129,7 @@ func (daemon *DockerDaemon) Events() (<-chan dockerEvents.Message, chan<- struct
 				if err := handleEvent(
 					ctx,
 					event,
+					forgetStartedContainers(&daemon.limits),
 					streamEvents(eventC),
 					logEvents(daemon.eventLog)); err != nil {
 					return
@@ -150,7 +156,7 @@ func (daemon *DockerDaemon) History(id string) ([]dockerTypes.ImageHistory, erro
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	return daemon.client.ImageHistory(
+	return daemon.apiClient().ImageHistory(
 		ctx, id)
 }
 
@@ -184,7 +190,7 @@ func (daemon *DockerDaemon) Info() (dockerTypes.Info, error) {
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	return daemon.client.Info(ctx)
+	return daemon.apiClient().Info(ctx)
 }
 
 //Inspect the container with the given id
@@ -192,7 +198,7 @@ func (daemon *DockerDaemon) Inspect(id string) (dockerTypes.ContainerJSON, error
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	return daemon.client.ContainerInspect(ctx, id)
+	return daemon.apiClient().ContainerInspect(ctx, id)
 }
 
 //InspectImage the image with the name
@@ -200,7 +206,7 @@ func (daemon *DockerDaemon) InspectImage(name string) (dockerTypes.ImageInspect,
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	inspect, _, err := daemon.client.ImageInspectWithRaw(ctx, name)
+	inspect, _, err := daemon.apiClient().ImageInspectWithRaw(ctx, name)
 	return inspect, err
 }
 
@@ -216,7 +222,7 @@ func (daemon *DockerDaemon) Kill(id string) error {
 
 	//TODO Sends the right signal
 
-	return daemon.client.ContainerKill(ctx, id, "")
+	return daemon.apiClient().ContainerKill(ctx, id, "")
 }
 
 //Logs shows the logs of the container with the given id
@@ -231,7 +237,7 @@ func (daemon *DockerDaemon) Logs(id string) io.ReadCloser {
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	reader, _ := daemon.client.ContainerLogs(ctx, id, options)
+	reader, _ := daemon.apiClient().ContainerLogs(ctx, id, options)
 	return reader
 }
 
@@ -264,7 +270,7 @@ func (daemon *DockerDaemon) NetworkInspect(id string) (dockerTypes.NetworkResour
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	return daemon.client.NetworkInspect(
+	return daemon.apiClient().NetworkInspect(
 		ctx, id)
 }
 
@@ -273,6 +279,14 @@ func (daemon *DockerDaemon) Ok() (bool, error) {
 	return daemon.err == nil, daemon.err
 }
 
+//Ping checks that the Docker daemon is reachable
+func (daemon *DockerDaemon) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	_, err := daemon.apiClient().Ping(ctx)
+	return err
+}
+
 func (daemon *DockerDaemon) OpenChannel(container *dockerTypes.Container) *StatsChannel {
 	return NewStatsChannel(daemon, container)
 }
@@ -284,19 +298,19 @@ func (daemon *DockerDaemon) Prune() (*PruneReport, error) {
 
 	args := filters.NewArgs()
 	args.Add("force", "y")
-	cReport, err := daemon.client.ContainersPrune(c, args)
+	cReport, err := daemon.apiClient().ContainersPrune(c, args)
 	if err != nil {
 		return nil, err
 	}
-	iReport, err := daemon.client.ImagesPrune(c, args)
+	iReport, err := daemon.apiClient().ImagesPrune(c, args)
 	if err != nil {
 		return nil, err
 	}
-	nReport, err := daemon.client.NetworksPrune(c, args)
+	nReport, err := daemon.apiClient().NetworksPrune(c, args)
 	if err != nil {
 		return nil, err
 	}
-	vRreport, err := daemon.client.VolumesPrune(c, args)
+	vRreport, err := daemon.apiClient().VolumesPrune(c, args)
 	if err != nil {
 		return nil, err
 	}
@@ -314,12 +328,12 @@ func (daemon *DockerDaemon) RestartContainer(id string) error {
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
 	//fixme: timeout to start a container
-	return daemon.client.ContainerRestart(ctx, id, &containerOpTimeout)
+	return daemon.apiClient().ContainerRestart(ctx, id, &containerOpTimeout)
 }
 
 //Refresh the container list
 func (daemon *DockerDaemon) Refresh(allContainers bool) error {
-	containers, err := containers(daemon.client, allContainers)
+	containers, err := containers(daemon.apiClient(), allContainers)
 	if err == nil {
 		daemon.containerStore = NewMemoryStoreWithContainers(containers)
 	}
@@ -331,7 +345,7 @@ func (daemon *DockerDaemon) RefreshImages() error {
 	daemon.refreshLock.Lock()
 	defer daemon.refreshLock.Unlock()
 
-	images, err := images(daemon.client, defaultImageListOptions)
+	images, err := images(daemon.apiClient(), defaultImageListOptions)
 
 	if err == nil {
 		daemon.images = images
@@ -344,7 +358,7 @@ func (daemon *DockerDaemon) RefreshNetworks() error {
 	daemon.refreshLock.Lock()
 	defer daemon.refreshLock.Unlock()
 
-	networks, err := networks(daemon.client)
+	networks, err := networks(daemon.apiClient())
 
 	if err == nil {
 		daemon.networks = networks
@@ -354,7 +368,7 @@ func (daemon *DockerDaemon) RefreshNetworks() error {
 
 //RemoveAllStoppedContainers removes all stopped containers
 func (daemon *DockerDaemon) RemoveAllStoppedContainers() (int, error) {
-	containers, err := containers(daemon.client, true)
+	containers, err := containers(daemon.apiClient(), true)
 	var count uint32
 	errs := make(chan error, 1)
 	defer close(errs)
@@ -392,7 +406,7 @@ func (daemon *DockerDaemon) RemoveAllStoppedContainers() (int, error) {
 func (daemon *DockerDaemon) RemoveDanglingImages() (int, error) {
 	danglingfilters := filters.NewArgs()
 	danglingfilters.Add("dangling", "true")
-	images, err := images(daemon.client,
+	images, err := images(daemon.apiClient(),
 		dockerTypes.ImageListOptions{
 			Filters: danglingfilters})
 	var count uint32
@@ -429,7 +443,7 @@ func (daemon *DockerDaemon) RemoveNetwork(id string) error {
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	return daemon.client.NetworkRemove(ctx, id)
+	return daemon.apiClient().NetworkRemove(ctx, id)
 }
 
 //Rm removes the container with the given id
@@ -444,7 +458,7 @@ func (daemon *DockerDaemon) Rm(id string) error {
 		}
 		//TODO use cancel function
 		ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
-		err := daemon.client.ContainerRemove(ctx, id, opts)
+		err := daemon.apiClient().ContainerRemove(ctx, id, opts)
 		if err != nil {
 			daemon.Refresh(true)
 		}
@@ -460,7 +474,7 @@ func (daemon *DockerDaemon) Rmi(name string, force bool) ([]dockerTypes.ImageDel
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	return daemon.client.ImageRemove(ctx, name, options)
+	return daemon.apiClient().ImageRemove(ctx, name, options)
 }
 
 //Stats shows resource usage statistics of the container with the given id
@@ -474,12 +488,26 @@ func (daemon *DockerDaemon) StopContainer(id string) error {
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	return daemon.client.ContainerStop(ctx, id, &containerOpTimeout)
+	return daemon.apiClient().ContainerStop(ctx, id, &containerOpTimeout)
 }
 
 //Sort the list of containers by the given mode
 func (daemon *DockerDaemon) Sort(sortMode SortMode) {
-	daemon.containerStore.Sort(sortMode)
+	if sortMode != SortByUptime && sortMode != SortByUptimeNewest {
+		daemon.containerStore.Sort(sortMode)
+		return
+	}
+	//The start time of running containers is only known by inspecting them,
+	//inspections are cached, see ContainerLimits
+	started := make(map[string]time.Time)
+	for _, c := range daemon.containerStore.Filter(ContainerFilters.ByRunningState(true)) {
+		if limits, err := daemon.ContainerLimits(c.ID); err == nil {
+			started[c.ID] = limits.StartedAt
+		}
+	}
+	daemon.containerStore.sort(sortMode, func(c *dockerTypes.Container) time.Time {
+		return started[c.ID]
+	})
 }
 
 //SortImages sorts the list of images by the given mode
@@ -501,7 +529,7 @@ func (daemon *DockerDaemon) Top(id string) (dockerTypes.ContainerProcessList, er
 	//TODO use cancel function
 	ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-	return daemon.client.ContainerTop(ctx, id, nil)
+	return daemon.apiClient().ContainerTop(ctx, id, nil)
 }
 
 //Version returns  version information about the Docker Engine
@@ -510,7 +538,7 @@ func (daemon *DockerDaemon) Version() (*dockerTypes.Version, error) {
 		//TODO use cancel function
 		ctx, _ := context.WithTimeout(context.Background(), defaultOperationTimeout)
 
-		v, err := daemon.client.ServerVersion(ctx)
+		v, err := daemon.apiClient().ServerVersion(ctx)
 		if err == nil {
 			daemon.version = &v
 			return daemon.version, nil
@@ -12,6 +12,19 @@ type Env struct {
 	DockerTLSVerify  bool //tls must be verified
 	DockerCertPath   string
 	DockerAPIVersion string
+	//StatsStreamRate is the maximum number of stats streams opened per second,
+	//DefaultStatsStreamRate is used if not set
+	StatsStreamRate int
+	//StatsOneShot makes stats to be requested one sample at a time, instead of
+	//reading them from a long-lived stream.
+	StatsOneShot bool
+	//StatsPollingPolicy, if set, creates the policy that decides how often the
+	//stats of a container are requested on one-shot mode.
+	StatsPollingPolicy func() PollingPolicy
+	//StatsOpenFDs makes the open file descriptors of containers to be counted
+	//along with their stats, it needs the container to be inspected and its
+	//proc entries to be read, so it is only done if they are shown.
+	StatsOpenFDs bool
 }
 
 //NewEnv creates a new docker environment struct
@@ -20,5 +33,5 @@ func NewEnv() *Env {
 	if version == "" {
 		version = client.DefaultVersion
 	}
-	return &Env{DockerAPIVersion: version}
+	return &Env{DockerAPIVersion: version, StatsStreamRate: DefaultStatsStreamRate}
 }
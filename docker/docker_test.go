@@ -1,12 +1,15 @@
 package docker
 
 import (
+	"errors"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	dockerAPI "github.com/docker/docker/client"
 	"github.com/moncho/dry/docker/mock"
+	"golang.org/x/net/context"
 )
 
 func TestContainerListRetrieval(t *testing.T) {
@@ -41,3 +44,47 @@ func TestContainerConversionToPointerList(t *testing.T) {
 func createClient() dockerAPI.APIClient {
 	return mock.APIClientMock{}
 }
+
+func TestHostCapacityIsCached(t *testing.T) {
+	daemon := &DockerDaemon{client: createClient()}
+
+	if daemon.HostCPUs() != 4 {
+		t.Errorf("Unexpected number of host CPUs: %d", daemon.HostCPUs())
+	}
+	if daemon.HostMemory() != 1024*1024*1024 {
+		t.Errorf("Unexpected host memory: %d", daemon.HostMemory())
+	}
+	lastRefresh := daemon.host.lastRefresh
+	daemon.HostCPUs()
+	if lastRefresh != daemon.host.lastRefresh {
+		t.Error("Host information was refreshed before its expiration")
+	}
+}
+
+//failingInfoClient is a client whose Info calls fail, it counts them
+type failingInfoClient struct {
+	mock.APIClientMock
+	calls *int
+}
+
+func (c failingInfoClient) Info(ctx context.Context) (types.Info, error) {
+	*c.calls++
+	return types.Info{}, errors.New("daemon is gone")
+}
+
+func TestHostCapacityRefreshBacksOffOnError(t *testing.T) {
+	calls := 0
+	daemon := &DockerDaemon{client: failingInfoClient{calls: &calls}}
+
+	daemon.HostCPUs()
+	daemon.HostMemory()
+	daemon.HostCPUs()
+	if calls != 1 {
+		t.Errorf("Info was retried right after failing, calls: %d", calls)
+	}
+	daemon.host.lastFailure = time.Now().Add(-hostInfoRetryInterval)
+	daemon.HostCPUs()
+	if calls != 2 {
+		t.Errorf("Info was not retried after the retry interval, calls: %d", calls)
+	}
+}
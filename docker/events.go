@@ -28,6 +28,17 @@ func logEvents(log *EventLog) eventProcessor {
 	}
 }
 
+//forgetStartedContainers removes started containers from the given limits cache,
+//their start time has changed.
+func forgetStartedContainers(cache *limitsCache) eventProcessor {
+	return func(event events.Message) error {
+		if event.Type == events.ContainerEventType && event.Action == "start" {
+			cache.forget(event.Actor.ID)
+		}
+		return nil
+	}
+}
+
 type eventProcessor func(event events.Message) error
 
 func decodeEvents(
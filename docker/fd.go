@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+//FDRefreshInterval is the time between two consecutive counts of the open
+//file descriptors of a container, counting is slower than collecting stats.
+const FDRefreshInterval = 5 * StatsInterval
+
+//procRoot is the mount point of the proc filesystem of the Docker host
+var procRoot = "/proc"
+
+//fdCollector counts the open file descriptors of the main process of a
+//container, reading them from the proc filesystem of the host. It only works
+//if dry runs on the Docker host and has access to the proc filesystem.
+type fdCollector struct {
+	pid         int
+	count       int
+	lastRefresh time.Time
+}
+
+//newFDCollector creates a collector for the container with the given id, the
+//daemon has to be local. If the file descriptors of the container cannot be
+//counted the collector always returns -1.
+func newFDCollector(client StatsClient, local bool, id string) *fdCollector {
+	collector := &fdCollector{count: -1}
+	if !local {
+		return collector
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	if cjson, err := client.ContainerInspect(ctx, id); err == nil && cjson.ContainerJSONBase != nil && cjson.State != nil {
+		collector.pid = cjson.State.Pid
+	}
+	return collector
+}
+
+//openFDs returns the number of open file descriptors, the count is refreshed if
+//FDRefreshInterval has passed since the last refresh. -1 is returned if the
+//file descriptors cannot be counted.
+func (c *fdCollector) openFDs(now time.Time) int {
+	if c.pid <= 0 {
+		return -1
+	}
+	if now.Sub(c.lastRefresh) < FDRefreshInterval {
+		return c.count
+	}
+	c.lastRefresh = now
+	count, err := countOpenFDs(c.pid)
+	if err != nil {
+		c.count = -1
+	} else {
+		c.count = count
+	}
+	return c.count
+}
+
+//countOpenFDs counts the open file descriptors of the process with the given pid
+func countOpenFDs(pid int) (int, error) {
+	fds, err := ioutil.ReadDir(filepath.Join(procRoot, strconv.Itoa(pid), "fd"))
+	if err != nil {
+		return 0, err
+	}
+	return len(fds), nil
+}
+
+//isLocal returns true if the Docker daemon runs on the same host as dry
+func (daemon *DockerDaemon) isLocal() bool {
+	if daemon.dockerEnv == nil {
+		return false
+	}
+	return strings.HasPrefix(daemon.dockerEnv.DockerHost, "unix://")
+}
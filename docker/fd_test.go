@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFDCollector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(root string) { procRoot = root }(procRoot)
+	procRoot = dir
+
+	fdDir := filepath.Join(dir, "42", "fd")
+	if err := os.MkdirAll(fdDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, fd := range []string{"0", "1", "2"} {
+		if err := ioutil.WriteFile(filepath.Join(fdDir, fd), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	collector := &fdCollector{pid: 42, count: -1}
+	now := time.Now()
+	if fds := collector.openFDs(now); fds != 3 {
+		t.Errorf("Unexpected number of open file descriptors. Expected: %d, got: %d", 3, fds)
+	}
+	ioutil.WriteFile(filepath.Join(fdDir, "3"), nil, 0644)
+	if fds := collector.openFDs(now.Add(time.Second)); fds != 3 {
+		t.Errorf("Count was refreshed before the refresh interval. Expected: %d, got: %d", 3, fds)
+	}
+	if fds := collector.openFDs(now.Add(FDRefreshInterval)); fds != 4 {
+		t.Errorf("Count was not refreshed. Expected: %d, got: %d", 4, fds)
+	}
+
+	unknown := &fdCollector{pid: 43, count: -1}
+	if fds := unknown.openFDs(now); fds != -1 {
+		t.Errorf("Unexpected count for a process that cannot be read: %d", fds)
+	}
+}
+
+func TestFDCollectorOnRemoteDaemon(t *testing.T) {
+	daemon := &DockerDaemon{client: createClient(), dockerEnv: &Env{DockerHost: "tcp://10.0.0.1:2376"}}
+	source := daemon.statsSource()
+	if fds := newFDCollector(source.client, source.local, "0").openFDs(time.Now()); fds != -1 {
+		t.Errorf("File descriptors were counted for a remote daemon: %d", fds)
+	}
+}
@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+//hostInfoTTL is the time during which host capacity information is cached
+var hostInfoTTL = 5 * time.Minute
+
+//hostInfoRetryInterval is the time to wait before asking the daemon for the host
+//capacity again after a failed attempt
+var hostInfoRetryInterval = 30 * time.Second
+
+//hostInfo caches the capacity of the Docker host as reported by the daemon
+type hostInfo struct {
+	ncpu        int
+	memTotal    int64
+	lastRefresh time.Time
+	//lastFailure is the time of the last failed attempt to refresh the values
+	lastFailure time.Time
+	sync.Mutex
+}
+
+//HostCPUs returns the number of CPUs of the Docker host. The value is
+//cached, Info is only called on the daemon once the cache expires.
+func (daemon *DockerDaemon) HostCPUs() int {
+	daemon.refreshHostInfo()
+	daemon.host.Lock()
+	defer daemon.host.Unlock()
+	return daemon.host.ncpu
+}
+
+//HostMemory returns the total memory of the Docker host, in bytes. The value is
+//cached, Info is only called on the daemon once the cache expires.
+func (daemon *DockerDaemon) HostMemory() int64 {
+	daemon.refreshHostInfo()
+	daemon.host.Lock()
+	defer daemon.host.Unlock()
+	return daemon.host.memTotal
+}
+
+//refreshHostInfo refreshes the host capacity information if it has expired.
+//On error, the last known values are kept and no new attempt is made until
+//hostInfoRetryInterval has passed.
+func (daemon *DockerDaemon) refreshHostInfo() {
+	daemon.host.Lock()
+	defer daemon.host.Unlock()
+	if time.Since(daemon.host.lastRefresh) < hostInfoTTL ||
+		time.Since(daemon.host.lastFailure) < hostInfoRetryInterval {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	info, err := daemon.apiClient().Info(ctx)
+	if err != nil {
+		daemon.host.lastFailure = time.Now()
+		return
+	}
+	daemon.host.ncpu = info.NCPU
+	daemon.host.memTotal = info.MemTotal
+	daemon.host.lastRefresh = time.Now()
+}
@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"path"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+//DefaultInfraImagePatterns are the images of the infrastructure containers
+//identified by default, the pause containers of Kubernetes pods
+var DefaultInfraImagePatterns = []string{"pause", "pause:*", "pause-*"}
+
+//DefaultInfraLabels are the labels of the infrastructure containers identified
+//by default, the sandbox containers of Kubernetes pods
+var DefaultInfraLabels = []string{"io.kubernetes.docker.type=podsandbox"}
+
+//InfraClassifier identifies infrastructure containers, such as the pause
+//containers of Kubernetes pods, as opposed to application containers.
+type InfraClassifier struct {
+	//ImagePatterns are shell patterns, as used by path.Match, matched against
+	//the image of the container. Patterns with no slash are matched against the
+	//image name without its repository path, i.e. pause:3.1 for k8s.gcr.io/pause:3.1
+	ImagePatterns []string
+	//Labels are label keys, or key=value pairs, of infrastructure containers
+	Labels []string
+}
+
+//DefaultInfraClassifier identifies the infrastructure containers using the
+//default image patterns and labels
+var DefaultInfraClassifier = &InfraClassifier{
+	ImagePatterns: DefaultInfraImagePatterns,
+	Labels:        DefaultInfraLabels}
+
+//NewInfraClassifier creates an InfraClassifier with the given image patterns and
+//labels, the default ones are used for the ones not given.
+func NewInfraClassifier(imagePatterns, labels []string) *InfraClassifier {
+	if len(imagePatterns) == 0 {
+		imagePatterns = DefaultInfraImagePatterns
+	}
+	if len(labels) == 0 {
+		labels = DefaultInfraLabels
+	}
+	return &InfraClassifier{ImagePatterns: imagePatterns, Labels: labels}
+}
+
+//IsInfra returns true if the given container is an infrastructure container,
+//its image matches one of the image patterns or it has one of the labels.
+func (ic *InfraClassifier) IsInfra(c *types.Container) bool {
+	if ic == nil {
+		return false
+	}
+	for _, pattern := range ic.ImagePatterns {
+		image := c.Image
+		if !strings.Contains(pattern, "/") {
+			image = path.Base(image)
+		}
+		if ok, _ := path.Match(pattern, image); ok {
+			return true
+		}
+	}
+	for _, label := range ic.Labels {
+		key, value := label, ""
+		withValue := strings.Contains(label, "=")
+		if withValue {
+			parts := strings.SplitN(label, "=", 2)
+			key, value = parts[0], parts[1]
+		}
+		if v, ok := c.Labels[key]; ok && (!withValue || v == value) {
+			return true
+		}
+	}
+	return false
+}
+
+//ByInfra filters containers by whether the given classifier identifies them as
+//infrastructure containers
+func (c ContainerFilter) ByInfra(classifier *InfraClassifier, infra bool) ContainerFilter {
+	return func(c *types.Container) bool {
+		return classifier.IsInfra(c) == infra
+	}
+}
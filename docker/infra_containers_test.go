@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestInfraClassifier(t *testing.T) {
+	tests := []struct {
+		container *types.Container
+		infra     bool
+	}{
+		{&types.Container{Image: "k8s.gcr.io/pause:3.1"}, true},
+		{&types.Container{Image: "registry.k8s.io/pause"}, true},
+		{&types.Container{Image: "gcr.io/google_containers/pause-amd64:3.0"}, true},
+		{&types.Container{Image: "pause"}, true},
+		{&types.Container{Image: "nginx", Labels: map[string]string{"io.kubernetes.docker.type": "podsandbox"}}, true},
+		{&types.Container{Image: "nginx", Labels: map[string]string{"io.kubernetes.docker.type": "container"}}, false},
+		{&types.Container{Image: "nginx"}, false},
+		{&types.Container{Image: "acme/pauseless"}, false},
+	}
+	for _, test := range tests {
+		if infra := DefaultInfraClassifier.IsInfra(test.container); infra != test.infra {
+			t.Errorf("Unexpected classification of %s with labels %v. Expected infra: %v, got: %v",
+				test.container.Image, test.container.Labels, test.infra, infra)
+		}
+	}
+
+	classifier := &InfraClassifier{ImagePatterns: []string{"acme/agent*"}, Labels: []string{"infra"}}
+	if !classifier.IsInfra(&types.Container{Image: "acme/agent:1.0"}) {
+		t.Error("Container not classified by a custom image pattern")
+	}
+	if !classifier.IsInfra(&types.Container{Image: "nginx", Labels: map[string]string{"infra": ""}}) {
+		t.Error("Container not classified by a custom label key")
+	}
+	var none *InfraClassifier
+	if none.IsInfra(&types.Container{Image: "pause"}) {
+		t.Error("A nil classifier identified an infrastructure container")
+	}
+}
+
+func TestNewInfraClassifier(t *testing.T) {
+	pause := &types.Container{Image: "k8s.gcr.io/pause:3.1"}
+	agent := &types.Container{Image: "nginx", Labels: map[string]string{"infra": ""}}
+	sandbox := &types.Container{Image: "nginx", Labels: map[string]string{"io.kubernetes.docker.type": "podsandbox"}}
+
+	classifier := NewInfraClassifier(nil, []string{"infra"})
+	if !classifier.IsInfra(pause) || !classifier.IsInfra(agent) || classifier.IsInfra(sandbox) {
+		t.Errorf("Unexpected classifier with only labels given: %+v", classifier)
+	}
+	classifier = NewInfraClassifier([]string{"acme/agent*"}, nil)
+	if classifier.IsInfra(pause) || !classifier.IsInfra(sandbox) {
+		t.Errorf("Unexpected classifier with only image patterns given: %+v", classifier)
+	}
+}
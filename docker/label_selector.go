@@ -0,0 +1,126 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+//labelOperator is the operator of a label selector requirement
+type labelOperator int
+
+const (
+	labelEquals labelOperator = iota
+	labelNotEquals
+	labelExists
+	labelNotExists
+)
+
+//labelRequirement is a condition on a container label
+type labelRequirement struct {
+	key      string
+	operator labelOperator
+	value    string
+}
+
+//matches returns true if the given labels meet this requirement. Containers
+//without the label meet inequality requirements.
+func (r labelRequirement) matches(labels map[string]string) bool {
+	value, ok := labels[r.key]
+	switch r.operator {
+	case labelEquals:
+		return ok && value == r.value
+	case labelNotEquals:
+		return !ok || value != r.value
+	case labelExists:
+		return ok
+	case labelNotExists:
+		return !ok
+	}
+	return false
+}
+
+//LabelSelector selects containers by their labels, a container is selected if its
+//labels meet every requirement of the selector
+type LabelSelector []labelRequirement
+
+//ParseLabelSelector parses the given label selector, a comma-separated list of
+//requirements as in Kubernetes selectors: key=value (or key==value), key!=value,
+//key (the label exists) and !key (the label does not exist).
+//I.e. env=prod,team!=infra,!deprecated
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var selector LabelSelector
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("Empty label selector")
+	}
+	for _, term := range strings.Split(s, ",") {
+		r, err := parseLabelRequirement(strings.TrimSpace(term))
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+func parseLabelRequirement(term string) (labelRequirement, error) {
+	var r labelRequirement
+	switch {
+	case strings.HasPrefix(term, "!"):
+		r = labelRequirement{key: strings.TrimSpace(term[1:]), operator: labelNotExists}
+	case strings.Contains(term, "!="):
+		kv := strings.SplitN(term, "!=", 2)
+		r = labelRequirement{key: strings.TrimSpace(kv[0]), operator: labelNotEquals, value: strings.TrimSpace(kv[1])}
+	case strings.Contains(term, "=="):
+		kv := strings.SplitN(term, "==", 2)
+		r = labelRequirement{key: strings.TrimSpace(kv[0]), operator: labelEquals, value: strings.TrimSpace(kv[1])}
+	case strings.Contains(term, "="):
+		kv := strings.SplitN(term, "=", 2)
+		r = labelRequirement{key: strings.TrimSpace(kv[0]), operator: labelEquals, value: strings.TrimSpace(kv[1])}
+	default:
+		r = labelRequirement{key: term, operator: labelExists}
+	}
+	if r.key == "" {
+		return r, fmt.Errorf("Invalid label selector requirement '%s': no label key", term)
+	}
+	if strings.ContainsAny(r.key, "!= ") || strings.ContainsAny(r.value, "!=") {
+		return r, fmt.Errorf("Invalid label selector requirement '%s'", term)
+	}
+	return r, nil
+}
+
+//String returns the selector using the selector syntax
+func (s LabelSelector) String() string {
+	terms := make([]string, len(s))
+	for i, r := range s {
+		switch r.operator {
+		case labelEquals:
+			terms[i] = r.key + "=" + r.value
+		case labelNotEquals:
+			terms[i] = r.key + "!=" + r.value
+		case labelExists:
+			terms[i] = r.key
+		case labelNotExists:
+			terms[i] = "!" + r.key
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+//Matches returns true if the given labels meet every requirement of this selector
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+//ByLabels filters containers by their labels using the given selector
+func (c ContainerFilter) ByLabels(selector LabelSelector) ContainerFilter {
+	return func(c *types.Container) bool {
+		return selector.Matches(c.Labels)
+	}
+}
@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	var tests = []struct {
+		selector string
+		expected string
+	}{
+		{"env=prod", "env=prod"},
+		{"env==prod", "env=prod"},
+		{" env = prod , team!=infra", "env=prod,team!=infra"},
+		{"env,!deprecated", "env,!deprecated"},
+		{"env=", "env="},
+	}
+	for _, test := range tests {
+		s, err := ParseLabelSelector(test.selector)
+		if err != nil {
+			t.Errorf("Unexpected error parsing '%s': %s", test.selector, err.Error())
+			continue
+		}
+		if s.String() != test.expected {
+			t.Errorf("Unexpected selector parsing '%s'. Expected: %s, got: %s", test.selector, test.expected, s.String())
+		}
+	}
+}
+
+func TestParseInvalidLabelSelector(t *testing.T) {
+	for _, selector := range []string{"", " ", "=prod", "!=prod", "!", "env=prod,", "env=a=b", "env!=a!=b", "my env"} {
+		if s, err := ParseLabelSelector(selector); err == nil {
+			t.Errorf("Invalid selector '%s' was parsed: %s", selector, s)
+		}
+	}
+}
+
+func TestFilterByLabels(t *testing.T) {
+	containers := []*types.Container{
+		{ID: "1", Labels: map[string]string{"env": "prod", "team": "web"}},
+		{ID: "2", Labels: map[string]string{"env": "prod", "team": "infra"}},
+		{ID: "3", Labels: map[string]string{"env": "dev", "deprecated": "true"}},
+		{ID: "4"},
+	}
+	var tests = []struct {
+		selector string
+		expected []string
+	}{
+		{"env=prod", []string{"1", "2"}},
+		{"env=prod,team!=infra", []string{"1"}},
+		{"team!=infra", []string{"1", "3", "4"}},
+		{"env", []string{"1", "2", "3"}},
+		{"!deprecated", []string{"1", "2", "4"}},
+		{"env=staging", nil},
+	}
+	for _, test := range tests {
+		selector, err := ParseLabelSelector(test.selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter := ContainerFilters.ByLabels(selector)
+		var ids []string
+		for _, c := range containers {
+			if filter(c) {
+				ids = append(ids, c.ID)
+			}
+		}
+		if len(ids) != len(test.expected) {
+			t.Errorf("Unexpected containers selected by '%s'. Expected: %v, got: %v", test.selector, test.expected, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != test.expected[i] {
+				t.Errorf("Unexpected containers selected by '%s'. Expected: %v, got: %v", test.selector, test.expected, ids)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"golang.org/x/net/context"
+)
+
+//ContainerLimits are the resource limits of a container, and its restart policy
+type ContainerLimits struct {
+	//CPUs is the number of CPUs the container can use, 0 if unlimited
+	CPUs float64
+	//Memory is the memory limit, in bytes, 0 if unlimited
+	Memory int64
+	//CPUSet are the CPUs the container is pinned to, i.e. 0-3, empty if unset
+	CPUSet string
+	//RestartPolicy is the restart policy of the container: no, always,
+	//unless-stopped or on-failure, followed by the maximum retry count if set,
+	//i.e. on-failure:3
+	RestartPolicy string
+	//PidMode is the PID namespace of the container: empty if it has its own,
+	//host or container:<id or name> if it is shared
+	PidMode string
+	//StartedAt is when the container was last started, zero if it has never started
+	StartedAt time.Time
+	//NoFile is the limit of open files of the container, nil if not set and the
+	//default limit of the daemon applies
+	NoFile *Ulimit
+}
+
+//Ulimit are the soft and hard values of a ulimit, -1 is unlimited
+type Ulimit struct {
+	Soft int64
+	Hard int64
+}
+
+//limitsCache caches the resource limits of containers, by container ID
+type limitsCache struct {
+	limits map[string]ContainerLimits
+	sync.Mutex
+}
+
+//forget removes the limits of the container with the given ID from the cache
+func (c *limitsCache) forget(id string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.limits, id)
+}
+
+//ContainerLimits returns the resource limits of the container with the given ID.
+//Limits are cached, the container is only inspected the first time, and again
+//once it is started if the daemon events are being listened to.
+func (daemon *DockerDaemon) ContainerLimits(id string) (ContainerLimits, error) {
+	daemon.limits.Lock()
+	limits, ok := daemon.limits.limits[id]
+	daemon.limits.Unlock()
+	if ok {
+		return limits, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	cjson, err := daemon.apiClient().ContainerInspect(ctx, id)
+	if err != nil {
+		return ContainerLimits{}, err
+	}
+	if cjson.ContainerJSONBase != nil && cjson.HostConfig != nil {
+		limits = limitsOf(cjson.HostConfig)
+	}
+	if cjson.ContainerJSONBase != nil && cjson.State != nil {
+		limits.StartedAt = startedAt(cjson.State.StartedAt)
+	}
+	daemon.limits.Lock()
+	defer daemon.limits.Unlock()
+	if daemon.limits.limits == nil {
+		daemon.limits.limits = make(map[string]ContainerLimits)
+	}
+	daemon.limits.limits[id] = limits
+	return limits, nil
+}
+
+//limitsOf returns the resource limits defined by the given host configuration,
+//CPU limits are either given as NanoCPUs (--cpus) or as a CFS quota.
+func limitsOf(hc *container.HostConfig) ContainerLimits {
+	limits := ContainerLimits{
+		Memory:        hc.Memory,
+		CPUSet:        hc.CpusetCpus,
+		RestartPolicy: restartPolicy(hc.RestartPolicy),
+		PidMode:       string(hc.PidMode)}
+	for _, ulimit := range hc.Ulimits {
+		if ulimit != nil && ulimit.Name == "nofile" {
+			limits.NoFile = &Ulimit{Soft: ulimit.Soft, Hard: ulimit.Hard}
+		}
+	}
+	if hc.NanoCPUs > 0 {
+		limits.CPUs = float64(hc.NanoCPUs) / 1e9
+	} else if hc.CPUQuota > 0 && hc.CPUPeriod > 0 {
+		limits.CPUs = float64(hc.CPUQuota) / float64(hc.CPUPeriod)
+	}
+	return limits
+}
+
+//restartPolicy returns the given restart policy as text
+func restartPolicy(policy container.RestartPolicy) string {
+	switch {
+	case policy.Name == "":
+		return "no"
+	case policy.IsOnFailure() && policy.MaximumRetryCount > 0:
+		return fmt.Sprintf("%s:%d", policy.Name, policy.MaximumRetryCount)
+	}
+	return policy.Name
+}
+
+//startedAt parses the given start time of a container, as returned by the
+//Docker API. Containers that have never started have no start time.
+func startedAt(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || t.IsZero() {
+		return time.Time{}
+	}
+	return t
+}
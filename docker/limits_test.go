@@ -0,0 +1,122 @@
+package docker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"golang.org/x/net/context"
+)
+
+//inspectCountClient counts the containers inspected
+type inspectCountClient struct {
+	slowTopClient
+	inspected *int
+}
+
+func (c inspectCountClient) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	*c.inspected++
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+		ID: id,
+		HostConfig: &container.HostConfig{Resources: container.Resources{
+			NanoCPUs: 1500000000, Memory: 512 * 1024 * 1024}}}}, nil
+}
+
+func TestContainerLimits(t *testing.T) {
+	inspected := 0
+	daemon := &DockerDaemon{client: inspectCountClient{inspected: &inspected}}
+
+	for i := 0; i < 2; i++ {
+		limits, err := daemon.ContainerLimits("1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if limits.CPUs != 1.5 || limits.Memory != 512*1024*1024 {
+			t.Errorf("Unexpected limits: %+v", limits)
+		}
+	}
+	if inspected != 1 {
+		t.Errorf("Limits are not cached, the container was inspected %d times", inspected)
+	}
+}
+
+func TestContainerLimitsForgottenOnStart(t *testing.T) {
+	inspected := 0
+	daemon := &DockerDaemon{client: inspectCountClient{inspected: &inspected}}
+	forget := forgetStartedContainers(&daemon.limits)
+
+	daemon.ContainerLimits("1")
+	forget(events.Message{Type: events.ContainerEventType, Action: "die", Actor: events.Actor{ID: "1"}})
+	daemon.ContainerLimits("1")
+	if inspected != 1 {
+		t.Errorf("Limits were forgotten on a die event, the container was inspected %d times", inspected)
+	}
+	forget(events.Message{Type: events.ContainerEventType, Action: "start", Actor: events.Actor{ID: "1"}})
+	daemon.ContainerLimits("1")
+	if inspected != 2 {
+		t.Errorf("Limits were not forgotten on a start event, the container was inspected %d times", inspected)
+	}
+}
+
+func TestLimitsOf(t *testing.T) {
+	var tests = []struct {
+		resources container.Resources
+		expected  ContainerLimits
+	}{
+		{container.Resources{}, ContainerLimits{RestartPolicy: "no"}},
+		{container.Resources{NanoCPUs: 500000000}, ContainerLimits{CPUs: 0.5, RestartPolicy: "no"}},
+		{container.Resources{CPUQuota: 200000, CPUPeriod: 100000, Memory: 1024}, ContainerLimits{CPUs: 2, Memory: 1024, RestartPolicy: "no"}},
+		{container.Resources{CpusetCpus: "0-3"}, ContainerLimits{CPUSet: "0-3", RestartPolicy: "no"}},
+	}
+	for _, test := range tests {
+		if limits := limitsOf(&container.HostConfig{Resources: test.resources}); limits != test.expected {
+			t.Errorf("Unexpected limits. Expected: %+v, got: %+v", test.expected, limits)
+		}
+	}
+	if limits := limitsOf(&container.HostConfig{PidMode: "container:web"}); limits.PidMode != "container:web" {
+		t.Errorf("Unexpected PID mode. Expected: %s, got: %s", "container:web", limits.PidMode)
+	}
+	hc := &container.HostConfig{}
+	hc.Ulimits = append(hc.Ulimits, nil, nil)
+	if limits := limitsOf(hc); limits.NoFile != nil {
+		t.Errorf("Unexpected open files limit with no ulimits set: %+v", limits.NoFile)
+	}
+	if err := json.Unmarshal([]byte(`{"Ulimits":[{"Name":"nproc","Soft":10,"Hard":10},{"Name":"nofile","Soft":1024,"Hard":4096}]}`), hc); err != nil {
+		t.Fatal(err)
+	}
+	if limits := limitsOf(hc); limits.NoFile == nil || *limits.NoFile != (Ulimit{Soft: 1024, Hard: 4096}) {
+		t.Errorf("Unexpected open files limit: %+v", limits.NoFile)
+	}
+}
+
+func TestRestartPolicy(t *testing.T) {
+	var tests = []struct {
+		policy   container.RestartPolicy
+		expected string
+	}{
+		{container.RestartPolicy{}, "no"},
+		{container.RestartPolicy{Name: "always"}, "always"},
+		{container.RestartPolicy{Name: "unless-stopped"}, "unless-stopped"},
+		{container.RestartPolicy{Name: "on-failure"}, "on-failure"},
+		{container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 3}, "on-failure:3"},
+	}
+	for _, test := range tests {
+		if policy := restartPolicy(test.policy); policy != test.expected {
+			t.Errorf("Unexpected restart policy. Expected: %s, got: %s", test.expected, policy)
+		}
+	}
+}
+
+func TestStartedAt(t *testing.T) {
+	if started := startedAt("2024-03-01T10:20:30.123456789Z"); !started.Equal(time.Date(2024, 3, 1, 10, 20, 30, 123456789, time.UTC)) {
+		t.Errorf("Unexpected start time: %s", started)
+	}
+	for _, s := range []string{"0001-01-01T00:00:00Z", "", "not a time"} {
+		if started := startedAt(s); !started.IsZero() {
+			t.Errorf("A start time was returned for %q: %s", s, started)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types"
 )
@@ -85,9 +86,15 @@ func (c *ContainerStore) List() []*types.Container {
 
 // Sort sorts the store
 func (c *ContainerStore) Sort(mode SortMode) {
+	c.sort(mode, nil)
+}
+
+// sort sorts the store, startedAt, if set, gives the time at which a container
+// was started when sorting by uptime.
+func (c *ContainerStore) sort(mode SortMode, startedAt func(c *types.Container) time.Time) {
 	c.RLock()
 	defer c.RUnlock()
-	SortContainers(c.c, mode)
+	sortContainers(c.c, mode, startedAt)
 }
 
 // Size returns the number of containers in the store.
@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"errors"
 	"strconv"
 
 	"golang.org/x/net/context"
@@ -32,3 +33,24 @@ func (m APIClientMock) ContainerList(ctx context.Context, options types.Containe
 
 	return containers, nil
 }
+
+//ContainerInspect returns information about the container with the given ID,
+//only containers with IDs from 0 to 9 are found.
+func (m APIClientMock) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	if i, err := strconv.Atoi(id); err != nil || i < 0 || i > 9 {
+		return types.ContainerJSON{}, errors.New("No such container: " + id)
+	}
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{ID: id},
+	}, nil
+}
+
+//Ping pings a daemon that is always reachable
+func (m APIClientMock) Ping(ctx context.Context) (types.Ping, error) {
+	return types.Ping{}, nil
+}
+
+//Info returns information about a Docker host with 4 CPUs and 1GiB of memory
+func (m APIClientMock) Info(ctx context.Context) (types.Info, error) {
+	return types.Info{NCPU: 4, MemTotal: 1024 * 1024 * 1024}, nil
+}
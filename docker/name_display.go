@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+)
+
+//Labels set by Docker Compose on the containers of a project
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+	composeNumberLabel  = "com.docker.compose.container-number"
+)
+
+//NameDisplay decides how the name of a container is shown
+type NameDisplay int
+
+const (
+	//NameRaw shows the name of the container
+	NameRaw NameDisplay = iota
+	//NameService shows the Compose service of the container, i.e. web
+	NameService
+	//NameProjectService shows the Compose project and service of the container, i.e. shop/web
+	NameProjectService
+)
+
+//nameDisplays are the name displays by name
+var nameDisplays = map[string]NameDisplay{
+	"raw":     NameRaw,
+	"service": NameService,
+	"project": NameProjectService,
+}
+
+//ParseNameDisplay returns the name display with the given name: raw, service or project
+func ParseNameDisplay(name string) (NameDisplay, error) {
+	if d, ok := nameDisplays[name]; ok {
+		return d, nil
+	}
+	return NameRaw, fmt.Errorf("Unknown container name display: %s", name)
+}
+
+//composeName returns the name of the given container as the given display decides,
+//false if the container is not a Compose service or the display shows raw names.
+//Replicas other than the first one are followed by their number, i.e. web-2.
+func composeName(c *types.Container, display NameDisplay) (string, bool) {
+	service, ok := c.Labels[composeServiceLabel]
+	if display == NameRaw || !ok || service == "" {
+		return "", false
+	}
+	name := service
+	if number := c.Labels[composeNumberLabel]; number != "" && number != "1" {
+		name += "-" + number
+	}
+	if project := c.Labels[composeProjectLabel]; display == NameProjectService && project != "" {
+		name = project + "/" + name
+	}
+	return name, true
+}
+
+//DisplayName returns the name of the container as the given display decides,
+//containers that are not Compose services are shown with their names.
+func (c *ContainerFormatter) DisplayName(display NameDisplay) string {
+	if name, ok := composeName(c.c, display); ok {
+		c.addHeader(namesHeader)
+		return name
+	}
+	return c.Names()
+}
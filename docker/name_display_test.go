@@ -0,0 +1,36 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestContainerDisplayName(t *testing.T) {
+	compose := func(number string) *types.Container {
+		return &types.Container{Names: []string{"/shop_web_" + number}, Labels: map[string]string{
+			composeProjectLabel: "shop",
+			composeServiceLabel: "web",
+			composeNumberLabel:  number}}
+	}
+	tests := []struct {
+		container *types.Container
+		display   NameDisplay
+		expected  string
+	}{
+		{compose("1"), NameRaw, "shop_web_1"},
+		{compose("1"), NameService, "web"},
+		{compose("2"), NameService, "web-2"},
+		{compose("1"), NameProjectService, "shop/web"},
+		{&types.Container{Names: []string{"/standalone"}}, NameService, "standalone"},
+		{&types.Container{Names: []string{"/standalone"}}, NameProjectService, "standalone"},
+	}
+	for _, test := range tests {
+		if name := NewContainerFormatter(test.container, true).DisplayName(test.display); name != test.expected {
+			t.Errorf("Unexpected name with display %d. Expected: %s, got: %s", test.display, test.expected, name)
+		}
+	}
+	if _, err := ParseNameDisplay("nickname"); err == nil {
+		t.Error("No error parsing an unknown name display")
+	}
+}
@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	pkgError "github.com/pkg/errors"
+)
+
+//NoteKeyLabel is the label whose value, if a container has it, is the key of the note
+//of the container, so containers recreated with a different name keep their note
+const NoteKeyLabel = "dry.note.key"
+
+//ContainerNotes are short notes about containers, persisted to a file. Notes are
+//kept by container name, so they survive containers being recreated, unless the
+//container has the NoteKeyLabel label. Containers with no name use its ID.
+type ContainerNotes struct {
+	path  string
+	notes map[string]string
+	sync.RWMutex
+}
+
+//LoadContainerNotes loads the notes persisted on the given file, if the
+//file does not exist there are no notes.
+func LoadContainerNotes(path string) (*ContainerNotes, error) {
+	n := &ContainerNotes{path: path, notes: make(map[string]string)}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return n, nil
+	}
+	if err != nil {
+		return n, pkgError.Wrap(err, "Error reading container notes")
+	}
+	if err := json.Unmarshal(data, &n.notes); err != nil {
+		return n, pkgError.Wrap(err, "Error reading container notes")
+	}
+	return n, nil
+}
+
+//Note returns the note of the given container, empty if there is none
+func (n *ContainerNotes) Note(c *types.Container) string {
+	n.RLock()
+	defer n.RUnlock()
+	if note, ok := n.notes[noteKey(c)]; ok {
+		return note
+	}
+	return n.notes[c.ID]
+}
+
+//SetNote sets the note of the given container and persists the notes, an
+//empty note removes the note of the container.
+func (n *ContainerNotes) SetNote(c *types.Container, note string) error {
+	n.Lock()
+	defer n.Unlock()
+	delete(n.notes, c.ID)
+	if note = strings.TrimSpace(note); note == "" {
+		delete(n.notes, noteKey(c))
+	} else {
+		n.notes[noteKey(c)] = note
+	}
+	return n.save()
+}
+
+//save writes the notes to the notes file
+func (n *ContainerNotes) save() error {
+	data, err := json.MarshalIndent(n.notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(n.path), 0700); err != nil {
+		return pkgError.Wrap(err, "Error saving container notes")
+	}
+	return pkgError.Wrap(ioutil.WriteFile(n.path, data, 0600), "Error saving container notes")
+}
+
+//noteKey returns the key of the note of the given container
+func noteKey(c *types.Container) string {
+	if key := c.Labels[NoteKeyLabel]; key != "" {
+		return key
+	}
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+	return c.ID
+}
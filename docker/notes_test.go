@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestContainerNotes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dry", "notes.json")
+
+	notes, err := LoadContainerNotes(path)
+	if err != nil {
+		t.Fatalf("Unexpected error loading notes from a file that does not exist: %s", err.Error())
+	}
+	web := &types.Container{ID: "1", Names: []string{"/web"}}
+	labeled := &types.Container{ID: "2", Names: []string{"/job_1"}, Labels: map[string]string{NoteKeyLabel: "job"}}
+	unnamed := &types.Container{ID: "3"}
+	for c, note := range map[*types.Container]string{web: "known leaker, restart nightly", labeled: "batch", unnamed: "temporary"} {
+		if err := notes.SetNote(c, note); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	notes, err = LoadContainerNotes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		container *types.Container
+		expected  string
+	}{
+		{&types.Container{ID: "restarted", Names: []string{"/web"}}, "known leaker, restart nightly"},
+		{&types.Container{ID: "recreated", Names: []string{"/job_2"}, Labels: map[string]string{NoteKeyLabel: "job"}}, "batch"},
+		{unnamed, "temporary"},
+		{&types.Container{ID: "4", Names: []string{"/db"}}, ""},
+	}
+	for _, test := range tests {
+		if note := notes.Note(test.container); note != test.expected {
+			t.Errorf("Unexpected note of container %s. Expected: %q, got: %q", test.container.ID, test.expected, note)
+		}
+	}
+
+	notes.SetNote(web, " ")
+	if note := notes.Note(web); note != "" {
+		t.Errorf("Note was not removed: %s", note)
+	}
+}
+
+func TestLoadInvalidContainerNotes(t *testing.T) {
+	f, err := ioutil.TempFile("", "notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("{nope")
+	f.Close()
+	if _, err := LoadContainerNotes(f.Name()); err == nil {
+		t.Error("Loading an invalid notes file did not return an error")
+	}
+}
@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"math"
+	"time"
+)
+
+//PollingPolicy decides, on one-shot stats mode, how long to wait before requesting
+//the next stats sample of a container. A policy is created for each container.
+type PollingPolicy interface {
+	//Next returns the time to wait after the given sample was received
+	Next(s *Stats) time.Duration
+}
+
+//fixedPolling requests a sample every StatsInterval
+type fixedPolling struct{}
+
+func (fixedPolling) Next(s *Stats) time.Duration {
+	return StatsInterval
+}
+
+//AdaptivePolling is a PollingPolicy that polls less often containers that have
+//been idle for a while. The interval doubles, up to Max, on each sample after
+//IdleSamples consecutive idle samples, and goes back to Min as soon as a sample
+//is not idle. A sample is idle if its CPU usage is below IdleCPU and its memory
+//usage has changed less than MemoryTolerance (a fraction) since the previous one.
+type AdaptivePolling struct {
+	Min, Max        time.Duration
+	IdleCPU         float64
+	MemoryTolerance float64
+	IdleSamples     int
+	interval        time.Duration
+	idle            int
+	lastMemory      float64
+}
+
+//NewAdaptivePolling creates an AdaptivePolling policy that polls every StatsInterval
+//and, after 10 idle samples, slows down up to a sample every 10 StatsIntervals.
+func NewAdaptivePolling() PollingPolicy {
+	return &AdaptivePolling{
+		Min:             StatsInterval,
+		Max:             10 * StatsInterval,
+		IdleCPU:         1,
+		MemoryTolerance: 0.01,
+		IdleSamples:     10,
+	}
+}
+
+//Next returns the time to wait after the given sample was received
+func (p *AdaptivePolling) Next(s *Stats) time.Duration {
+	if p.interval == 0 {
+		p.interval = p.Min
+	}
+	memoryChange := math.Abs(s.Memory - p.lastMemory)
+	idle := s.CPUPercentage < p.IdleCPU && memoryChange <= p.MemoryTolerance*p.lastMemory
+	p.lastMemory = s.Memory
+	if !idle {
+		p.idle = 0
+		p.interval = p.Min
+		return p.interval
+	}
+	p.idle++
+	if p.idle >= p.IdleSamples {
+		p.interval *= 2
+		if p.interval > p.Max {
+			p.interval = p.Max
+		}
+	}
+	return p.interval
+}
@@ -0,0 +1,38 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptivePolling(t *testing.T) {
+	p := &AdaptivePolling{
+		Min:             time.Second,
+		Max:             4 * time.Second,
+		IdleCPU:         1,
+		MemoryTolerance: 0.01,
+		IdleSamples:     2,
+	}
+	idle := &Stats{CPUPercentage: 0.5, Memory: 1000}
+	busy := &Stats{CPUPercentage: 50, Memory: 1000}
+
+	expected := []struct {
+		stats    *Stats
+		interval time.Duration
+	}{
+		//The first sample is not idle, memory changes from 0
+		{idle, time.Second},
+		{idle, time.Second},
+		{idle, 2 * time.Second},
+		{idle, 4 * time.Second},
+		{idle, 4 * time.Second},
+		{busy, time.Second},
+		{idle, time.Second},
+		{&Stats{CPUPercentage: 0.5, Memory: 2000}, time.Second},
+	}
+	for i, e := range expected {
+		if interval := p.Next(e.stats); interval != e.interval {
+			t.Errorf("Sample %d: unexpected interval. Expected: %s, got: %s", i, e.interval, interval)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"sync"
+	"time"
+)
+
+//DefaultStatsStreamRate is the default maximum number of stats streams
+//opened per second on a Docker daemon
+const DefaultStatsStreamRate = 10
+
+//rateLimiter is a token bucket, it holds up to rate tokens and is refilled
+//at rate tokens per second.
+type rateLimiter struct {
+	rate   int
+	tokens float64
+	last   time.Time
+	sync.Mutex
+}
+
+func newRateLimiter(rate int) *rateLimiter {
+	if rate <= 0 {
+		rate = DefaultStatsStreamRate
+	}
+	return &rateLimiter{rate: rate, tokens: float64(rate)}
+}
+
+//reserve takes a token from the bucket and returns how long the caller has
+//to wait before using it, zero if a token was available.
+func (l *rateLimiter) reserve() time.Duration {
+	return l.reserveAt(time.Now())
+}
+
+func (l *rateLimiter) reserveAt(now time.Time) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+		if l.tokens > float64(l.rate) {
+			l.tokens = float64(l.rate)
+		}
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+}
+
+//streamLimiter returns the rate limiter used to open stats streams on this daemon
+func (daemon *DockerDaemon) streamLimiter() *rateLimiter {
+	daemon.limiterOnce.Do(func() {
+		rate := DefaultStatsStreamRate
+		if daemon.dockerEnv != nil && daemon.dockerEnv.StatsStreamRate > 0 {
+			rate = daemon.dockerEnv.StatsStreamRate
+		}
+		daemon.limiter = newRateLimiter(rate)
+	})
+	return daemon.limiter
+}
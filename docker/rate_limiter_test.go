@@ -0,0 +1,31 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(2)
+	now := time.Now()
+
+	if wait := limiter.reserveAt(now); wait != 0 {
+		t.Errorf("First token is not available, wait: %s", wait)
+	}
+	if wait := limiter.reserveAt(now); wait != 0 {
+		t.Errorf("Second token is not available, wait: %s", wait)
+	}
+	if wait := limiter.reserveAt(now); wait != 500*time.Millisecond {
+		t.Errorf("Unexpected wait once the bucket is empty. Expected: %s, got: %s", 500*time.Millisecond, wait)
+	}
+	//After a second the bucket is refilled with 2 tokens, one was already reserved
+	if wait := limiter.reserveAt(now.Add(time.Second)); wait != 0 {
+		t.Errorf("Bucket was not refilled, wait: %s", wait)
+	}
+}
+
+func TestRateLimiterDefaultRate(t *testing.T) {
+	if newRateLimiter(0).rate != DefaultStatsStreamRate {
+		t.Error("A limiter with no rate does not use the default rate")
+	}
+}
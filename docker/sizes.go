@@ -0,0 +1,27 @@
+package docker
+
+import (
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"golang.org/x/net/context"
+)
+
+//ContainerSizes returns the size, in bytes, of the writable layer of each running
+//container, by container ID. Sizes are calculated by the Docker daemon when
+//listing containers with the size flag, which is expensive, so they are only
+//retrieved when asked for.
+func (daemon *DockerDaemon) ContainerSizes() (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	args := filters.NewArgs()
+	args.Add("status", "running")
+	containers, err := daemon.apiClient().ContainerList(ctx, dockerTypes.ContainerListOptions{Filters: args, Size: true})
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]int64, len(containers))
+	for _, c := range containers {
+		sizes[c.ID] = c.SizeRw
+	}
+	return sizes, nil
+}
@@ -0,0 +1,33 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker/mock"
+	"golang.org/x/net/context"
+)
+
+//sizeClient lists containers with their writable layer size, if asked for
+type sizeClient struct {
+	mock.APIClientMock
+}
+
+func (c sizeClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	var size int64
+	if options.Size {
+		size = 2048
+	}
+	return []types.Container{{ID: "1", SizeRw: size}, {ID: "2"}}, nil
+}
+
+func TestContainerSizes(t *testing.T) {
+	daemon := &DockerDaemon{client: sizeClient{}}
+	sizes, err := daemon.ContainerSizes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes) != 2 || sizes["1"] != 2048 || sizes["2"] != 0 {
+		t.Errorf("Unexpected container sizes: %v", sizes)
+	}
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"sort"
+	"time"
 
 	"github.com/docker/docker/api/types"
 )
@@ -13,6 +14,8 @@ const (
 	SortByImage
 	SortByStatus
 	SortByName
+	SortByUptime
+	SortByUptimeNewest
 )
 
 //SortMode represents allowed modes to sort a container slice
@@ -50,17 +53,61 @@ func (a byStatus) Less(i, j int) bool {
 type byName struct{ apiContainers }
 
 func (a byName) Less(i, j int) bool {
-	if len(a.apiContainers[i].Names) > 0 {
-		if len(a.apiContainers[j].Names) > 0 {
-			return a.apiContainers[i].Names[0] < a.apiContainers[j].Names[0]
+	ci, cj := a.apiContainers[i], a.apiContainers[j]
+	if len(ci.Names) > 0 && len(cj.Names) > 0 && ci.Names[0] != cj.Names[0] {
+		return ci.Names[0] < cj.Names[0]
+	}
+	if (len(ci.Names) > 0) != (len(cj.Names) > 0) {
+		return len(ci.Names) > 0
+	}
+	//Containers with the same name, or with no name, are sorted by ID so
+	//their order does not change between sorts
+	return byContainerID{a.apiContainers}.Less(i, j)
+}
+
+//byUptime sorts running containers before stopped ones and then by the time
+//at which they were started. The list of containers does not include when a
+//container was started, so startedAt, if set, gives it. Containers whose start
+//time is not known are considered to have been started when they were created.
+type byUptime struct {
+	apiContainers
+	newestFirst bool
+	startedAt   func(c *types.Container) time.Time
+}
+
+func (a byUptime) Less(i, j int) bool {
+	ci, cj := a.apiContainers[i], a.apiContainers[j]
+	if ri, rj := IsContainerRunning(ci), IsContainerRunning(cj); ri != rj {
+		return ri
+	}
+	si, sj := a.started(ci), a.started(cj)
+	if si.Equal(sj) {
+		return byName{a.apiContainers}.Less(i, j)
+	}
+	if a.newestFirst {
+		return si.After(sj)
+	}
+	return si.Before(sj)
+}
+
+//started returns when the given container was started, its creation time if it is not known
+func (a byUptime) started(c *types.Container) time.Time {
+	if a.startedAt != nil {
+		if t := a.startedAt(c); !t.IsZero() {
+			return t
 		}
-		return true
 	}
-	return false
+	return time.Unix(c.Created, 0)
 }
 
 //SortContainers sorts the given containers slice using the given mode
 func SortContainers(containers []*types.Container, mode SortMode) {
+	sortContainers(containers, mode, nil)
+}
+
+//sortContainers sorts the given containers slice using the given mode, startedAt,
+//if set, gives the time at which a container was started, see byUptime.
+func sortContainers(containers []*types.Container, mode SortMode, startedAt func(c *types.Container) time.Time) {
 	switch mode {
 	case SortByContainerID:
 		sort.Sort(byContainerID{containers})
@@ -70,5 +117,9 @@ func SortContainers(containers []*types.Container, mode SortMode) {
 		sort.Sort(byStatus{containers})
 	case SortByName:
 		sort.Sort(byName{containers})
+	case SortByUptime:
+		sort.Sort(byUptime{containers, false, startedAt})
+	case SortByUptimeNewest:
+		sort.Sort(byUptime{containers, true, startedAt})
 	}
 }
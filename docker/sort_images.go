@@ -30,24 +30,31 @@ func (s byID) Less(i, j int) bool { return s.apiImages[i].ID < s.apiImages[j].ID
 type byRepository struct{ apiImages }
 
 func (s byRepository) Less(i, j int) bool {
-	if len(s.apiImages[i].RepoTags) > 0 {
-		if len(s.apiImages[j].RepoTags) > 0 {
-			return s.apiImages[i].RepoTags[0] < s.apiImages[j].RepoTags[0]
-		}
-		return true
+	ii, ij := s.apiImages[i], s.apiImages[j]
+	if len(ii.RepoTags) > 0 && len(ij.RepoTags) > 0 && ii.RepoTags[0] != ij.RepoTags[0] {
+		return ii.RepoTags[0] < ij.RepoTags[0]
 	}
-	return false
+	if (len(ii.RepoTags) > 0) != (len(ij.RepoTags) > 0) {
+		return len(ii.RepoTags) > 0
+	}
+	return byID{s.apiImages}.Less(i, j)
 }
 
 type bySize struct{ apiImages }
 
 func (s bySize) Less(i, j int) bool {
+	if s.apiImages[i].Size == s.apiImages[j].Size {
+		return byID{s.apiImages}.Less(i, j)
+	}
 	return s.apiImages[i].Size < s.apiImages[j].Size
 }
 
 type byCreationDate struct{ apiImages }
 
 func (s byCreationDate) Less(i, j int) bool {
+	if s.apiImages[i].Created == s.apiImages[j].Created {
+		return byID{s.apiImages}.Less(i, j)
+	}
 	return s.apiImages[i].Created < s.apiImages[j].Created
 }
 
@@ -29,18 +29,18 @@ func (s networksByID) Less(i, j int) bool { return s.dockerNetworks[i].ID < s.do
 type networksByName struct{ dockerNetworks }
 
 func (s networksByName) Less(i, j int) bool {
-	if len(s.dockerNetworks[i].Name) > 0 {
-		if len(s.dockerNetworks[j].Name) > 0 {
-			return s.dockerNetworks[i].Name[0] < s.dockerNetworks[j].Name[0]
-		}
-		return true
+	if s.dockerNetworks[i].Name == s.dockerNetworks[j].Name {
+		return networksByID{s.dockerNetworks}.Less(i, j)
 	}
-	return false
+	return s.dockerNetworks[i].Name < s.dockerNetworks[j].Name
 }
 
 type networksByDriver struct{ dockerNetworks }
 
 func (s networksByDriver) Less(i, j int) bool {
+	if s.dockerNetworks[i].Driver == s.dockerNetworks[j].Driver {
+		return networksByID{s.dockerNetworks}.Less(i, j)
+	}
 	return s.dockerNetworks[i].Driver < s.dockerNetworks[j].Driver
 }
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 )
@@ -66,6 +67,44 @@ func TestSortByStatus(t *testing.T) {
 	}
 }
 
+func TestSortByUptime(t *testing.T) {
+	tests := []struct {
+		mode     SortMode
+		expected []string
+	}{
+		{SortByUptime, []string{"8dfafdbc3a40", "6dfafdbc3a40", "7dfafdbc3a40"}},
+		{SortByUptimeNewest, []string{"8dfafdbc3a40", "7dfafdbc3a40", "6dfafdbc3a40"}},
+	}
+	for _, test := range tests {
+		c, err := containersToSort()
+		if err != nil {
+			t.Error("Could not create container list")
+		}
+		SortContainers(c, test.mode)
+		sorted := containersAsString(c)
+		if strings.Join(sorted, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("Sorting by uptime did not work. Expected: %v, sorted to: %v", test.expected, sorted)
+		}
+	}
+}
+
+func TestSortByUptimeUsesStartTime(t *testing.T) {
+	containers := []*types.Container{
+		{ID: "restarted", Created: 100, Status: "Up 1 minute"},
+		{ID: "old", Created: 200, Status: "Up 2 days"},
+		{ID: "unknown", Created: 300, Status: "Up 1 day"},
+	}
+	started := map[string]time.Time{
+		"restarted": time.Unix(10000, 0),
+		"old":       time.Unix(250, 0),
+	}
+	sortContainers(containers, SortByUptime, func(c *types.Container) time.Time { return started[c.ID] })
+	expected := []string{"old", "unknown", "restarted"}
+	if sorted := containersAsString(containers); strings.Join(sorted, ",") != strings.Join(expected, ",") {
+		t.Errorf("Sorting by start time did not work. Expected: %v, sorted to: %v", expected, sorted)
+	}
+}
+
 func containersToSort() ([]*types.Container, error) {
 	jsonContainers := `[
      {
@@ -104,3 +143,50 @@ func containersAsString(containers []*types.Container) []string {
 	}
 	return result
 }
+
+func TestSortTiesAreBrokenByID(t *testing.T) {
+	tied := func(ids ...string) []*types.Container {
+		var containers []*types.Container
+		for _, id := range ids {
+			containers = append(containers, &types.Container{ID: id, Image: "base", Status: "Up 1 second", Created: 1})
+		}
+		return containers
+	}
+	modes := []SortMode{SortByImage, SortByStatus, SortByName, SortByUptime, SortByUptimeNewest}
+	for _, mode := range modes {
+		for _, c := range [][]*types.Container{tied("c", "a", "b"), tied("b", "c", "a")} {
+			SortContainers(c, mode)
+			if ids := strings.Join(containersAsString(c), ","); ids != "a,b,c" {
+				t.Errorf("Containers tied on sort mode %d are not sorted by ID: %s", mode, ids)
+			}
+		}
+	}
+}
+
+func TestSortImagesTiesAreBrokenByID(t *testing.T) {
+	modes := []SortImagesMode{SortImagesByRepo, SortImagesBySize, SortImagesByCreationDate}
+	for _, mode := range modes {
+		images := []types.ImageSummary{
+			{ID: "c", RepoTags: []string{"base:latest"}, Size: 1, Created: 1},
+			{ID: "a", RepoTags: []string{"base:latest"}, Size: 1, Created: 1},
+			{ID: "b", RepoTags: []string{"base:latest"}, Size: 1, Created: 1}}
+		SortImages(images, mode)
+		if images[0].ID != "a" || images[1].ID != "b" || images[2].ID != "c" {
+			t.Errorf("Images tied on sort mode %d are not sorted by ID: %v", mode, images)
+		}
+	}
+}
+
+func TestSortNetworksTiesAreBrokenByID(t *testing.T) {
+	modes := []SortNetworksMode{SortNetworksByName, SortNetworksByDriver}
+	for _, mode := range modes {
+		networks := []types.NetworkResource{
+			{ID: "c", Name: "net", Driver: "bridge"},
+			{ID: "a", Name: "net", Driver: "bridge"},
+			{ID: "b", Name: "net", Driver: "bridge"}}
+		SortNetworks(networks, mode)
+		if networks[0].ID != "a" || networks[1].ID != "b" || networks[2].ID != "c" {
+			t.Errorf("Networks tied on sort mode %d are not sorted by ID: %v", mode, networks)
+		}
+	}
+}
@@ -2,69 +2,316 @@ package docker
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	pkgError "github.com/pkg/errors"
 )
 
+//StatsInterval is the time between two consecutive container stats samples
+const StatsInterval = 1000 * time.Millisecond
+
+//topTimeout is the maximum time to wait for the process list of a container
+//when collecting stats, a slow response must not stall the stats of the container.
+var topTimeout = StatsInterval / 2
+
+//StatsStreamTimeout is the maximum time to wait for a sample of a stats stream,
+//if no sample is received the connection is assumed dead and the stream ends.
+var StatsStreamTimeout = 5 * StatsInterval
+
+//ErrStatsTimeout is the error sent when a stats stream ends because no sample
+//was received in StatsStreamTimeout
+var ErrStatsTimeout = errors.New("No stats received in time, the stats stream was closed")
+
 //StatsChannel is a container and its stats channel.
 //If the container is not running stats and done channel are nil.
 type StatsChannel struct {
 	Container *types.Container
 	Stats     <-chan *Stats
 	Done      chan<- struct{}
+	//Errors receives the error that ended the stats stream, if any, before
+	//the stats channel is closed. It is never closed.
+	Errors <-chan error
+	state  *streamState
+}
+
+//State returns the state of the stats stream of this channel, channels of
+//containers that are not running have no stream and are always ended.
+func (s *StatsChannel) State() StreamState {
+	if s.state == nil {
+		if s.Stats == nil {
+			return StreamEnded
+		}
+		return StreamOpen
+	}
+	return s.state.get()
 }
 
 //NewStatsChannel creates a channel on which to receive the runtime stats of the given container
 func NewStatsChannel(daemon *DockerDaemon, container *types.Container) *StatsChannel {
+	return daemon.statsSource().open(container)
+}
+
+//open creates a channel on which to receive the runtime stats of the given container
+func (source statsSource) open(container *types.Container) *StatsChannel {
 	if IsContainerRunning(container) {
 		stats := make(chan *Stats)
 		done := make(chan struct{})
+		errs := make(chan error, 1)
+		state := &streamState{}
 
 		go func() {
-			cli := daemon.client
-			ctx, cancel := context.WithCancel(context.Background())
-			containerStats, err := cli.ContainerStats(ctx, container.Names[0], true)
-			responseBody := containerStats.Body
-			defer responseBody.Close()
-			defer close(stats)
-			if err != nil {
+			defer state.set(StreamEnded)
+			//Opening streams is rate limited so that many streams opened at once,
+			//i.e. after a daemon restart, do not overload the daemon
+			select {
+			case <-time.After(source.limiter.reserve()):
+			case <-done:
+				close(stats)
 				return
 			}
+			state.set(StreamOpen)
+			fds := &fdCollector{count: -1}
+			if source.openFDs {
+				fds = newFDCollector(source.client, source.local, container.ID)
+			}
+			top := newTopProbe(source.client, container.ID)
+			if source.oneShot {
+				pollStats(source.client, fds, top, source.pollingPolicy(), container, stats, done)
+			} else {
+				streamStats(source.client, fds, top, container, stats, errs, done)
+			}
+		}()
+
+		return &StatsChannel{Container: container, Stats: stats, Done: done, Errors: errs, state: state}
+	}
+	return &StatsChannel{Container: container}
+
+}
+
+//streamStats sends to the given channel the stats of the given container read from a
+//stats stream, until the stream ends or done is signaled. The stats channel is closed
+//on return.
+//If no sample is read from the stream in StatsStreamTimeout, i.e. the connection is
+//half-open, the stream is cancelled and ErrStatsTimeout is sent to the errors channel.
+//Only the time spent reading counts, not the time waiting for the receiver of the
+//stats channel. If the stream cannot be opened the error is sent to the errors channel.
+func streamStats(client StatsClient, fds *fdCollector, top *topProbe, container *types.Container, stats chan<- *Stats, errs chan<- error, done <-chan struct{}) {
+	defer close(stats)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	//Names have a leading slash, the ID is used to identify the container
+	containerStats, err := client.ContainerStats(ctx, container.ID, true)
+	if err != nil {
+		select {
+		case errs <- err:
+		default:
+		}
+		return
+	}
+	responseBody := containerStats.Body
+	defer responseBody.Close()
 
-			var statsJSON *types.StatsJSON
-			dec := json.NewDecoder(responseBody)
+	var statsJSON *types.StatsJSON
+	dec := json.NewDecoder(responseBody)
+	//the watchdog is only armed while a sample is being read
+	watchdog := time.AfterFunc(StatsStreamTimeout, cancel)
+	watchdog.Stop()
+	decode := func() bool {
+		watchdog.Reset(StatsStreamTimeout)
+		err := dec.Decode(&statsJSON)
+		if !watchdog.Stop() {
+			select {
+			case errs <- ErrStatsTimeout:
+			default:
+			}
+			return false
+		}
+		return err == nil
+	}
 
-			if err := dec.Decode(&statsJSON); err != nil {
+	if !decode() {
+		return
+	}
+	timer := time.NewTicker(StatsInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case now := <-timer.C:
+			if !decode() {
 				return
 			}
-			timer := time.NewTicker(1000 * time.Millisecond)
-			for {
+			if statsJSON != nil {
+				s := buildStats(container, statsJSON, nil)
+				top.setProcessList(ctx, s)
+				s.OpenFDs = fds.openFDs(now)
+				//the receiver might be gone once done is signaled
 				select {
-				case <-timer.C:
-					if err := dec.Decode(&statsJSON); err != nil {
-						return
-					}
-					if statsJSON != nil {
-						top, _ := daemon.Top(container.ID)
-						stats <- buildStats(container, statsJSON, &top)
-					}
-				case <-ctx.Done():
-					return
+				case stats <- s:
 				case <-done:
-					cancel()
 					return
 				}
 			}
-		}()
+		case <-done:
+			return
+		}
+	}
+}
+
+//maxPollFailures is the number of consecutive failed one-shot stats requests
+//after which the stats of a container are no longer polled
+var maxPollFailures = 5
 
-		return &StatsChannel{container, stats, done}
+//maxPollBackoff is the maximum time to wait before requesting again the stats
+//of a container after a failed one-shot request
+var maxPollBackoff = 30 * StatsInterval
+
+//pollStats sends to the given channel the stats of the given container, a single sample
+//is requested at a time, as often as the given polling policy decides, and no
+//connection is held open between samples.
+//Failed requests are retried, waiting twice as long after each consecutive failure.
+//It returns after maxPollFailures consecutive failed requests, i.e. the container
+//is gone, or when done is signaled, closing the stats channel.
+func pollStats(client StatsClient, fds *fdCollector, top *topProbe, policy PollingPolicy, container *types.Container, stats chan<- *Stats, done <-chan struct{}) {
+	defer close(stats)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	timer := time.NewTimer(StatsInterval)
+	defer timer.Stop()
+	failures := 0
+	for {
+		select {
+		case now := <-timer.C:
+			statsJSON, err := oneShotStats(ctx, client, container.ID)
+			if err != nil {
+				failures++
+				if failures >= maxPollFailures {
+					return
+				}
+				timer.Reset(pollBackoff(failures))
+				continue
+			}
+			failures = 0
+			s := buildStats(container, statsJSON, nil)
+			top.setProcessList(ctx, s)
+			s.OpenFDs = fds.openFDs(now)
+			s.Interval = policy.Next(s)
+			select {
+			case stats <- s:
+			case <-done:
+				return
+			}
+			timer.Reset(s.Interval)
+		case <-done:
+			return
+		}
 	}
-	return &StatsChannel{Container: container}
+}
 
+//pollBackoff returns the time to wait before requesting the stats of a container
+//again after the given number of consecutive failed requests
+func pollBackoff(failures int) time.Duration {
+	backoff := StatsInterval
+	for i := 1; i < failures && backoff < maxPollBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxPollBackoff {
+		return maxPollBackoff
+	}
+	return backoff
+}
+
+//oneShotStats returns a single stats sample of the container with the given ID or name
+func oneShotStats(ctx context.Context, client StatsClient, idOrName string) (*types.StatsJSON, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultOperationTimeout)
+	defer cancel()
+	containerStats, err := client.ContainerStats(ctx, idOrName, false)
+	if err != nil {
+		return nil, err
+	}
+	defer containerStats.Body.Close()
+	var statsJSON *types.StatsJSON
+	if err := json.NewDecoder(containerStats.Body).Decode(&statsJSON); err != nil {
+		return nil, err
+	}
+	if statsJSON == nil {
+		return nil, fmt.Errorf("No stats received for container %s", idOrName)
+	}
+	return statsJSON, nil
+}
+
+//ContainerStats creates a StatsChannel on which to receive the runtime stats of
+//the container with the given ID or name. An error is returned if no container
+//is found or if it is not running.
+func ContainerStats(daemon *DockerDaemon, idOrName string) (*StatsChannel, error) {
+	container, err := findContainer(daemon.apiClient(), idOrName)
+	if err != nil {
+		return nil, err
+	}
+	if !IsContainerRunning(container) {
+		return nil, fmt.Errorf("Container %s is not running", idOrName)
+	}
+	return NewStatsChannel(daemon, container), nil
+}
+
+//findContainer returns the container with the given ID or name
+func findContainer(client StatsClient, idOrName string) (*types.Container, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	cjson, err := client.ContainerInspect(ctx, idOrName)
+	if err != nil {
+		return nil, pkgError.Wrap(err, "Error resolving container "+idOrName)
+	}
+	args := filters.NewArgs()
+	args.Add("id", cjson.ID)
+	containers, err := client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: args})
+	if err != nil {
+		return nil, pkgError.Wrap(err, "Error retrieving container "+idOrName)
+	}
+	for i := range containers {
+		if containers[i].ID == cjson.ID {
+			return &containers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("No such container: %s", idOrName)
+}
+
+//topWithTimeout returns the process list of the container with the given id,
+//nil and the context error if it cannot be retrieved before the given context
+//is done or topTimeout expires.
+func topWithTimeout(ctx context.Context, client StatsClient, id string) (*types.ContainerProcessList, error) {
+	ctx, cancel := context.WithTimeout(ctx, topTimeout)
+	defer cancel()
+	type topResult struct {
+		top *types.ContainerProcessList
+		err error
+	}
+	result := make(chan topResult, 1)
+	go func() {
+		top, err := client.ContainerTop(ctx, id, nil)
+		if err != nil {
+			result <- topResult{err: err}
+			return
+		}
+		result <- topResult{top: &top}
+	}()
+	select {
+	case r := <-result:
+		//clients wrap the context errors, a request ended by the context is a timeout
+		if r.err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return r.top, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 //buildStats builds Stats with the given information
@@ -74,6 +321,7 @@ func buildStats(container *types.Container, stats *types.StatsJSON, topResult *t
 		Command:     container.Command,
 		Stats:       stats,
 		ProcessList: topResult,
+		OpenFDs:     -1,
 	}
 	s.CPUPercentage = calculateCPUPercent(stats)
 	br, bw := calculateBlockIO(stats)
@@ -96,25 +344,58 @@ func buildStats(container *types.Container, stats *types.StatsJSON, topResult *t
 	cpuPercent = calculateCPUPercent(stats)
 	blkRead, blkWrite := calculateBlockIO(stats)
 	s.CPUPercentage = cpuPercent
+	s.CPUUserPercentage, s.CPUSystemPercentage = calculateCPUSplit(stats)
 	s.Memory = float64(stats.MemoryStats.Usage)
 	s.MemoryLimit = float64(stats.MemoryStats.Limit)
 	s.MemoryPercentage = memPercent
+	s.MemoryWorkingSet = calculateMemWorkingSet(stats)
 	s.NetworkRx, s.NetworkTx = calculateNetwork(stats)
 	s.BlockRead = float64(blkRead)
 	s.BlockWrite = float64(blkWrite)
+	readOps, writeOps := calculateBlockOps(stats)
+	s.BlockReadOps = float64(readOps)
+	s.BlockWriteOps = float64(writeOps)
 	s.PidsCurrent = stats.PidsStats.Current
+	sanitizeStats(s)
 	return s
 }
 
+//sanitizeStats makes sure that the metrics of the given Stats are valid,
+//NaN, infinite and negative values are set to zero.
+func sanitizeStats(s *Stats) {
+	s.CPUPercentage = finite(s.CPUPercentage)
+	s.CPUUserPercentage = finite(s.CPUUserPercentage)
+	s.CPUSystemPercentage = finite(s.CPUSystemPercentage)
+	s.Memory = finite(s.Memory)
+	s.MemoryLimit = finite(s.MemoryLimit)
+	s.MemoryPercentage = finite(s.MemoryPercentage)
+	s.MemoryWorkingSet = finite(s.MemoryWorkingSet)
+	s.NetworkRx = finite(s.NetworkRx)
+	s.NetworkTx = finite(s.NetworkTx)
+	s.BlockRead = finite(s.BlockRead)
+	s.BlockWrite = finite(s.BlockWrite)
+	s.BlockReadOps = finite(s.BlockReadOps)
+	s.BlockWriteOps = finite(s.BlockWriteOps)
+}
+
+//finite returns the given value if it is a finite, non-negative number, zero otherwise
+func finite(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+		return 0
+	}
+	return v
+}
+
 func calculateCPUPercent(stats *types.StatsJSON) float64 {
 	previousCPU := stats.PreCPUStats.CPUUsage.TotalUsage
 	previousSystem := stats.PreCPUStats.SystemUsage
 	var (
 		cpuPercent = 0.0
-		// calculate the change for the cpu usage of the container in between readings
-		cpuDelta = float64(stats.CPUStats.CPUUsage.TotalUsage - previousCPU)
+		// calculate the change for the cpu usage of the container in between readings,
+		// counters can be reset so the subtraction is done on floats to avoid wrapping
+		cpuDelta = float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(previousCPU)
 		// calculate the change for the entire system between readings
-		systemDelta = float64(stats.CPUStats.SystemUsage - previousSystem)
+		systemDelta = float64(stats.CPUStats.SystemUsage) - float64(previousSystem)
 	)
 
 	if systemDelta > 0.0 && cpuDelta > 0.0 {
@@ -123,6 +404,20 @@ func calculateCPUPercent(stats *types.StatsJSON) float64 {
 	return cpuPercent
 }
 
+//calculateCPUSplit returns the percentages of the CPU time used by the container
+//between readings that were spent in user mode and in kernel mode, zero if
+//there is no CPU time used, as on the first reading.
+func calculateCPUSplit(stats *types.StatsJSON) (float64, float64) {
+	usage, previous := stats.CPUStats.CPUUsage, stats.PreCPUStats.CPUUsage
+	cpuDelta := float64(usage.TotalUsage) - float64(previous.TotalUsage)
+	if cpuDelta <= 0.0 || previous.TotalUsage == 0 {
+		return 0.0, 0.0
+	}
+	userDelta := float64(usage.UsageInUsermode) - float64(previous.UsageInUsermode)
+	kernelDelta := float64(usage.UsageInKernelmode) - float64(previous.UsageInKernelmode)
+	return userDelta / cpuDelta * 100.0, kernelDelta / cpuDelta * 100.0
+}
+
 func calculateMemPercentage(stats *types.StatsJSON) float64 {
 	// MemoryStats.Limit will never be 0 unless the container is not running and we havn't
 	// got any data from cgroup
@@ -132,6 +427,16 @@ func calculateMemPercentage(stats *types.StatsJSON) float64 {
 	return 0.0
 }
 
+//calculateMemWorkingSet returns the memory used by the container that is not
+//page cache, as reported by the cgroup memory stats
+func calculateMemWorkingSet(stats *types.StatsJSON) float64 {
+	usage := stats.MemoryStats.Usage
+	if cache, ok := stats.MemoryStats.Stats["cache"]; ok && cache < usage {
+		return float64(usage - cache)
+	}
+	return float64(usage)
+}
+
 func calculateBlockIO(stats *types.StatsJSON) (blkRead uint64, blkWrite uint64) {
 	blkio := stats.BlkioStats
 	for _, bioEntry := range blkio.IoServiceBytesRecursive {
@@ -145,6 +450,20 @@ func calculateBlockIO(stats *types.StatsJSON) (blkRead uint64, blkWrite uint64)
 	return
 }
 
+//calculateBlockOps returns the number of read and write block IO operations,
+//zero if the stats have no IO serviced entries.
+func calculateBlockOps(stats *types.StatsJSON) (readOps uint64, writeOps uint64) {
+	for _, bioEntry := range stats.BlkioStats.IoServicedRecursive {
+		switch strings.ToLower(bioEntry.Op) {
+		case "read":
+			readOps += bioEntry.Value
+		case "write":
+			writeOps += bioEntry.Value
+		}
+	}
+	return
+}
+
 func calculateNetwork(stats *types.StatsJSON) (float64, float64) {
 	networks := stats.Networks
 	var rx, tx float64
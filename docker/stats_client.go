@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"github.com/docker/docker/api/types"
+	dockerAPI "github.com/docker/docker/client"
+	"golang.org/x/net/context"
+)
+
+//StatsClient is the part of the Docker API used to collect container stats, tests
+//can use a fake client returning canned responses instead of a Docker daemon.
+type StatsClient interface {
+	ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error)
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerStats(ctx context.Context, container string, stream bool) (types.ContainerStats, error)
+	ContainerTop(ctx context.Context, container string, arguments []string) (types.ContainerProcessList, error)
+	Info(ctx context.Context) (types.Info, error)
+}
+
+var _ StatsClient = (dockerAPI.APIClient)(nil)
+
+//statsSource is what a stats channel needs to collect the stats of a container
+type statsSource struct {
+	client StatsClient
+	//limiter limits how many stats streams are opened per second
+	limiter *rateLimiter
+	//local is true if the Docker daemon runs on the same host as dry
+	local bool
+	//oneShot makes stats to be requested one sample at a time
+	oneShot bool
+	policy  func() PollingPolicy
+	//openFDs makes the open file descriptors of containers to be counted
+	openFDs bool
+}
+
+//statsSource returns the source of the stats of the containers of this daemon
+func (daemon *DockerDaemon) statsSource() statsSource {
+	s := statsSource{
+		client:  daemon.apiClient(),
+		limiter: daemon.streamLimiter(),
+		local:   daemon.isLocal(),
+	}
+	if env := daemon.dockerEnv; env != nil {
+		s.oneShot = env.StatsOneShot
+		s.policy = env.StatsPollingPolicy
+		s.openFDs = env.StatsOpenFDs
+	}
+	return s
+}
+
+//pollingPolicy returns a new polling policy for a container
+func (s statsSource) pollingPolicy() PollingPolicy {
+	if s.policy != nil {
+		return s.policy()
+	}
+	return fixedPolling{}
+}
@@ -0,0 +1,118 @@
+package docker
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	pkgError "github.com/pkg/errors"
+)
+
+//StatsDumpReplayInterval is the time between two consecutive samples of a
+//container when a stats dump is replayed
+var StatsDumpReplayInterval = StatsInterval
+
+//untaggedContainerID is the ID given to the container of the samples of a
+//dump that are not tagged with a container
+const untaggedContainerID = "dump"
+
+//StatsDump is a set of container stats samples read from a file of concatenated
+//docker StatsJSON objects, i.e. a docker stats stream saved to disk.
+type StatsDump struct {
+	//Containers are the containers found on the dump, in order of appearance
+	Containers []*types.Container
+	samples    map[string][]*types.StatsJSON
+}
+
+//ReadStatsDumpFile reads the stats dump on the given file, see ReadStatsDump
+func ReadStatsDumpFile(path string) (*StatsDump, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadStatsDump(f)
+}
+
+//ReadStatsDump reads a stats dump from the given reader. Samples are grouped
+//by the container they are tagged with, so the samples of several containers
+//can be interleaved. Samples with no container tag belong to a single container.
+func ReadStatsDump(r io.Reader) (*StatsDump, error) {
+	dump := &StatsDump{samples: make(map[string][]*types.StatsJSON)}
+	dec := json.NewDecoder(r)
+	for {
+		var stats types.StatsJSON
+		if err := dec.Decode(&stats); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, pkgError.Wrap(err, "Error reading stats dump")
+		}
+		id := stats.ID
+		if id == "" {
+			id = stats.Name
+		}
+		if id == "" {
+			id = untaggedContainerID
+		}
+		if _, ok := dump.samples[id]; !ok {
+			dump.Containers = append(dump.Containers, dumpedContainer(id, stats.Name))
+		}
+		dump.samples[id] = append(dump.samples[id], &stats)
+	}
+	return dump, nil
+}
+
+//dumpedContainer returns the container of the samples of a dump with the given tags
+func dumpedContainer(id, name string) *types.Container {
+	if name == "" {
+		name = TruncateID(id)
+	}
+	return &types.Container{
+		ID:     id,
+		Names:  []string{name},
+		Status: "Up (stats dump)",
+	}
+}
+
+//Samples returns the number of samples of the given container on the dump
+func (d *StatsDump) Samples(id string) int {
+	return len(d.samples[id])
+}
+
+//OpenChannel creates a channel on which the samples of the given container are
+//replayed, one every StatsDumpReplayInterval. The stats channel is closed once every
+//sample is sent.
+func (d *StatsDump) OpenChannel(container *types.Container) *StatsChannel {
+	samples := d.samples[container.ID]
+	stats := make(chan *Stats)
+	done := make(chan struct{})
+	state := &streamState{}
+	state.set(StreamOpen)
+	go func() {
+		//the stream is ended before the channel is closed, so receivers
+		//see it ended once the channel is closed
+		defer func() {
+			state.set(StreamEnded)
+			close(stats)
+		}()
+		replay := time.NewTicker(StatsDumpReplayInterval)
+		defer replay.Stop()
+		for i, sample := range samples {
+			if i > 0 {
+				select {
+				case <-replay.C:
+				case <-done:
+					return
+				}
+			}
+			select {
+			case stats <- buildStats(container, sample, nil):
+			case <-done:
+				return
+			}
+		}
+	}()
+	return &StatsChannel{Container: container, Stats: stats, Done: done, Errors: make(chan error, 1), state: state}
+}
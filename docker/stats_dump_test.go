@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const interleavedDump = `{"id":"c1","name":"/web","memory_stats":{"usage":100,"limit":1000}}
+{"id":"c2","name":"/db","memory_stats":{"usage":200,"limit":1000}}
+{"id":"c1","name":"/web","memory_stats":{"usage":300,"limit":1000}}
+`
+
+func TestReadStatsDumpGroupsSamplesByContainer(t *testing.T) {
+	dump, err := ReadStatsDump(strings.NewReader(interleavedDump))
+	if err != nil {
+		t.Fatalf("Unexpected error reading a stats dump: %s", err.Error())
+	}
+	if len(dump.Containers) != 2 {
+		t.Fatalf("Unexpected number of containers, expected: 2, got: %d", len(dump.Containers))
+	}
+	if c := dump.Containers[0]; c.ID != "c1" || c.Names[0] != "/web" || !IsContainerRunning(c) {
+		t.Errorf("Unexpected first container: %+v", c)
+	}
+	if dump.Samples("c1") != 2 || dump.Samples("c2") != 1 {
+		t.Errorf("Unexpected samples, c1: %d, c2: %d", dump.Samples("c1"), dump.Samples("c2"))
+	}
+}
+
+func TestReadStatsDumpWithUntaggedSamples(t *testing.T) {
+	dump, err := ReadStatsDump(strings.NewReader(`{"read":"2017-01-01T00:00:00Z"}{"read":"2017-01-01T00:00:01Z"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error reading a stats dump: %s", err.Error())
+	}
+	if len(dump.Containers) != 1 || dump.Samples(untaggedContainerID) != 2 {
+		t.Errorf("Untagged samples were not read as a single container: %+v", dump.Containers)
+	}
+}
+
+func TestReadStatsDumpWithInvalidJSON(t *testing.T) {
+	if _, err := ReadStatsDump(strings.NewReader(`{"id":"c1"}{nope`)); err == nil {
+		t.Error("Reading an invalid stats dump did not return an error")
+	}
+}
+
+func TestStatsDumpReplay(t *testing.T) {
+	defer func(interval time.Duration) { StatsDumpReplayInterval = interval }(StatsDumpReplayInterval)
+	StatsDumpReplayInterval = time.Millisecond
+	dump, _ := ReadStatsDump(strings.NewReader(interleavedDump))
+
+	channel := dump.OpenChannel(dump.Containers[0])
+	var memory []float64
+	for s := range channel.Stats {
+		memory = append(memory, s.Memory)
+	}
+	if len(memory) != 2 || memory[0] != 100 || memory[1] != 300 {
+		t.Errorf("Unexpected replayed samples: %v", memory)
+	}
+	if channel.State() != StreamEnded {
+		t.Errorf("Unexpected stream state after the replay: %v", channel.State())
+	}
+}
@@ -0,0 +1,205 @@
+package docker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"golang.org/x/net/context"
+)
+
+//statsSampler is a source of single stats samples, a Collector whose source
+//is also a statsSampler can poll the stats of the containers over its
+//maximum number of concurrent streams.
+type statsSampler interface {
+	StatsSample(container *types.Container) (*Stats, error)
+}
+
+//StatsSample returns a single stats sample of the given container, no stats
+//stream is opened
+func (daemon *DockerDaemon) StatsSample(container *types.Container) (*Stats, error) {
+	statsJSON, err := oneShotStats(context.Background(), daemon.apiClient(), container.ID)
+	if err != nil {
+		return nil, err
+	}
+	return buildStats(container, statsJSON, nil), nil
+}
+
+//StatsPoller requests single stats samples of the containers of the channels it
+//opens, one container at a time and in turns, so their stats are received, less
+//often, with no stats stream opened for each one of them. It only polls while
+//it has channels open.
+type StatsPoller struct {
+	sampler  statsSampler
+	interval time.Duration
+	channels []*polledChannel
+	next     int
+	polling  bool
+	sync.Mutex
+}
+
+//polledChannel is a channel of a StatsPoller
+type polledChannel struct {
+	container *types.Container
+	stats     chan *Stats
+	done      chan struct{}
+	state     *streamState
+}
+
+//ended returns true if the receiver closed the channel
+func (c *polledChannel) ended() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+//NewStatsPoller creates a StatsPoller of the containers of the given daemon that
+//requests a sample every interval, nil is returned if the daemon cannot provide
+//single stats samples.
+func NewStatsPoller(daemon ContainerDaemon, interval time.Duration) *StatsPoller {
+	sampler, ok := daemon.(statsSampler)
+	if !ok {
+		return nil
+	}
+	return newStatsPoller(sampler, interval)
+}
+
+func newStatsPoller(sampler statsSampler, interval time.Duration) *StatsPoller {
+	return &StatsPoller{sampler: sampler, interval: interval}
+}
+
+//OpenChannel opens a channel that receives the polled stats of the given container,
+//the stats channel is closed once the done channel is closed.
+func (p *StatsPoller) OpenChannel(container *types.Container) *StatsChannel {
+	channel := &polledChannel{
+		container: container,
+		stats:     make(chan *Stats, 1),
+		done:      make(chan struct{}),
+		state:     &streamState{}}
+	channel.state.set(StreamOpen)
+	p.Lock()
+	defer p.Unlock()
+	p.channels = append(p.channels, channel)
+	if !p.polling {
+		p.polling = true
+		go p.poll()
+	}
+	return &StatsChannel{Container: container, Stats: channel.stats, Done: channel.done, state: channel.state}
+}
+
+//poll requests, every interval, a stats sample of the container of the next
+//channel, until every channel has been closed.
+func (p *StatsPoller) poll() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		channel := p.nextChannel()
+		if channel == nil {
+			return
+		}
+		stats, err := p.sampler.StatsSample(channel.container)
+		if err != nil {
+			continue
+		}
+		//samples not received before the next one are dropped
+		select {
+		case channel.stats <- stats:
+		default:
+		}
+	}
+}
+
+//nextChannel returns the next channel to poll, the channels closed by their
+//receivers are ended first. If there are no channels left, polling stops and
+//nil is returned.
+func (p *StatsPoller) nextChannel() *polledChannel {
+	p.Lock()
+	defer p.Unlock()
+	open := p.channels[:0]
+	for _, channel := range p.channels {
+		if channel.ended() {
+			channel.state.set(StreamEnded)
+			close(channel.stats)
+			continue
+		}
+		open = append(open, channel)
+	}
+	p.channels = open
+	if len(p.channels) == 0 {
+		p.polling = false
+		return nil
+	}
+	p.next %= len(p.channels)
+	channel := p.channels[p.next]
+	p.next++
+	return channel
+}
+
+//Polled returns the number of containers being polled
+func (p *StatsPoller) Polled() int {
+	p.Lock()
+	defer p.Unlock()
+	return len(p.channels)
+}
+
+//SetMaxConcurrentStreams sets the maximum number of stats streams open at
+//once, the stats of the running containers over the maximum are polled, one
+//container at a time, in turns. Polled containers get samples less often.
+//0, the default, means no maximum. It must be set before the collector starts.
+func (c *Collector) SetMaxConcurrentStreams(max int) {
+	c.Lock()
+	defer c.Unlock()
+	c.maxStreams = max
+}
+
+//Polled returns the number of containers whose stats are polled instead of streamed
+func (c *Collector) Polled() int {
+	c.RLock()
+	defer c.RUnlock()
+	return len(c.polled)
+}
+
+//streamsExhausted returns true if no more stats streams can be opened and the
+//stats of the containers that are not streamed can be polled.
+//Must be called with the collector locked.
+func (c *Collector) streamsExhausted() bool {
+	return c.maxStreams > 0 && c.sampler != nil && len(c.channels) >= c.maxStreams
+}
+
+//poll requests, every pollInterval, a stats sample of the next polled container
+//and delivers it to the subscribers, until stop is closed.
+func (c *Collector) poll(stop <-chan struct{}) {
+	defer c.finished()
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			container := c.nextPolled()
+			if container == nil {
+				continue
+			}
+			if stats, err := c.sampler.StatsSample(container); err == nil {
+				c.publish(container, stats)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+//nextPolled returns the next container whose stats are polled, nil if there is none
+func (c *Collector) nextPolled() *types.Container {
+	c.Lock()
+	defer c.Unlock()
+	if len(c.polled) == 0 {
+		return nil
+	}
+	c.nextPoll %= len(c.polled)
+	container := c.polled[c.nextPoll]
+	c.nextPoll++
+	return container
+}
@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+func (s *fakeSource) StatsSample(container *types.Container) (*Stats, error) {
+	return &Stats{CID: container.ID}, nil
+}
+
+func TestCollectorPollsContainersOverMaxStreams(t *testing.T) {
+	source := &fakeSource{containers: []*types.Container{
+		{ID: "1", Names: []string{"/web"}, Status: "Up 1 hour"},
+		{ID: "2", Names: []string{"/db"}, Status: "Up 1 hour"},
+		{ID: "3", Names: []string{"/cache"}, Status: "Up 1 hour"},
+	}}
+	c := newCollector(source, time.Hour)
+	c.pollInterval = time.Millisecond
+	c.SetMaxConcurrentStreams(1)
+	var lock sync.Mutex
+	received := make(map[string]bool)
+	c.Subscribe(func(s *Stats) {
+		lock.Lock()
+		defer lock.Unlock()
+		received[s.CID] = true
+	})
+	c.Start()
+	defer c.Stop()
+
+	if c.Channels() != 1 || c.Polled() != 2 {
+		t.Errorf("Unexpected streamed and polled containers: %d, %d", c.Channels(), c.Polled())
+	}
+	timeout := time.After(time.Second)
+	for {
+		lock.Lock()
+		done := received["1"] && received["2"] && received["3"]
+		lock.Unlock()
+		if done {
+			break
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("Stats of every container were not collected: %v", received)
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	//The streamed container stops, a polled one is streamed instead
+	source.Lock()
+	source.containers = source.containers[1:]
+	source.Unlock()
+	c.reconcile()
+	if c.Channels() != 1 || c.Polled() != 1 {
+		t.Errorf("Unexpected streamed and polled containers after a container stopped: %d, %d", c.Channels(), c.Polled())
+	}
+}
+
+func TestStatsPoller(t *testing.T) {
+	p := newStatsPoller(&fakeSource{}, time.Millisecond)
+	web := p.OpenChannel(&types.Container{ID: "1", Names: []string{"/web"}})
+	db := p.OpenChannel(&types.Container{ID: "2", Names: []string{"/db"}})
+	if p.Polled() != 2 {
+		t.Errorf("Unexpected number of polled containers: %d", p.Polled())
+	}
+	for _, channel := range []*StatsChannel{web, db} {
+		if s := <-channel.Stats; s.CID != channel.Container.ID {
+			t.Errorf("Unexpected stats received: %s", s.CID)
+		}
+	}
+
+	close(web.Done)
+	for range web.Stats {
+	}
+	if web.State() != StreamEnded {
+		t.Errorf("Unexpected state of a closed channel: %v", web.State())
+	}
+	if s := <-db.Stats; s.CID != "2" {
+		t.Errorf("Unexpected stats received: %s", s.CID)
+	}
+	close(db.Done)
+	for range db.Stats {
+	}
+	p.Lock()
+	polling := p.polling
+	p.Unlock()
+	if polling || p.Polled() != 0 {
+		t.Errorf("Poller is still polling with no channels open, polled: %d", p.Polled())
+	}
+	//polling starts again with a new channel
+	cache := p.OpenChannel(&types.Container{ID: "3", Names: []string{"/cache"}})
+	if s := <-cache.Stats; s.CID != "3" {
+		t.Errorf("Unexpected stats received: %s", s.CID)
+	}
+	close(cache.Done)
+}
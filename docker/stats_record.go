@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"strings"
+	"time"
+)
+
+//StatsSchemaVersion is the version of the StatsRecord schema, it changes only
+//when fields are removed or their meaning changes.
+const StatsSchemaVersion = 1
+
+//StatsRecord is a flat, versioned representation of a container stats sample.
+//It holds only computed values and stable identifiers, so unlike Stats it does
+//not depend on the Docker API version. Exporters use it.
+type StatsRecord struct {
+	SchemaVersion    int       `json:"schema_version"`
+	Timestamp        time.Time `json:"timestamp"`
+	ContainerID      string    `json:"container_id"`
+	ContainerName    string    `json:"container_name,omitempty"`
+	CPUPercentage    float64   `json:"cpu_percent"`
+	Memory           float64   `json:"memory_bytes"`
+	MemoryLimit      float64   `json:"memory_limit_bytes"`
+	MemoryPercentage float64   `json:"memory_percent"`
+	NetworkRx        float64   `json:"network_rx_bytes"`
+	NetworkTx        float64   `json:"network_tx_bytes"`
+	BlockRead        float64   `json:"block_read_bytes"`
+	BlockWrite       float64   `json:"block_write_bytes"`
+	BlockReadOps     float64   `json:"block_read_ops"`
+	BlockWriteOps    float64   `json:"block_write_ops"`
+	Pids             uint64    `json:"pids"`
+}
+
+//Record returns the StatsRecord of this stats sample, the record is identified
+//by the truncated ID of the container.
+func (s *Stats) Record() StatsRecord {
+	r := StatsRecord{
+		SchemaVersion:    StatsSchemaVersion,
+		ContainerID:      s.CID,
+		CPUPercentage:    s.CPUPercentage,
+		Memory:           s.Memory,
+		MemoryLimit:      s.MemoryLimit,
+		MemoryPercentage: s.MemoryPercentage,
+		NetworkRx:        s.NetworkRx,
+		NetworkTx:        s.NetworkTx,
+		BlockRead:        s.BlockRead,
+		BlockWrite:       s.BlockWrite,
+		BlockReadOps:     s.BlockReadOps,
+		BlockWriteOps:    s.BlockWriteOps,
+		Pids:             s.PidsCurrent,
+	}
+	if s.Stats != nil {
+		r.Timestamp = s.Stats.Read
+	}
+	return r
+}
+
+//Record returns the StatsRecord of this snapshot, the record is identified by
+//the full ID and the name of the container.
+func (s StatsSnapshot) Record() StatsRecord {
+	r := s.Stats.Record()
+	if c := s.Container; c != nil {
+		r.ContainerID = c.ID
+		if len(c.Names) > 0 {
+			r.ContainerName = strings.TrimPrefix(c.Names[0], "/")
+		}
+	}
+	return r
+}
@@ -0,0 +1,39 @@
+package docker
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestStatsRecord(t *testing.T) {
+	snapshot := StatsSnapshot{
+		Container: &types.Container{ID: "0123456789abcdef", Names: []string{"/web"}},
+		Stats:     &Stats{CID: "0123456789", CPUPercentage: 12.5, PidsCurrent: 3, Stats: &types.StatsJSON{}},
+	}
+	r := snapshot.Record()
+	if r.SchemaVersion != StatsSchemaVersion {
+		t.Errorf("Unexpected schema version: %d", r.SchemaVersion)
+	}
+	if r.ContainerID != "0123456789abcdef" || r.ContainerName != "web" {
+		t.Errorf("Unexpected container identifiers: %s, %s", r.ContainerID, r.ContainerName)
+	}
+	if r.CPUPercentage != 12.5 || r.Pids != 3 {
+		t.Errorf("Unexpected record values: %+v", r)
+	}
+	if cid := snapshot.Stats.Record().ContainerID; cid != "0123456789" {
+		t.Errorf("Unexpected container id on a record from stats: %s", cid)
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"schema_version":1`, `"container_name":"web"`, `"cpu_percent":12.5`} {
+		if !strings.Contains(string(b), field) {
+			t.Errorf("Field %s not found on JSON record: %s", field, string(b))
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package docker
+
+import (
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"golang.org/x/net/context"
+)
+
+//StatsRound returns a stats sample of each running container, samples are
+//requested at once and no stats stream is opened. Containers whose sample
+//cannot be retrieved are left out.
+func (daemon *DockerDaemon) StatsRound() ([]StatsSnapshot, error) {
+	if err := daemon.Refresh(false); err != nil {
+		return nil, err
+	}
+	containers := daemon.ContainerStore().Filter(ContainerFilters.ByRunningState(true))
+	return statsRound(daemon.apiClient(), containers), nil
+}
+
+//statsRound requests a stats sample of each one of the given containers, it
+//returns the samples retrieved following the order of the containers.
+func statsRound(client StatsClient, containers []*types.Container) []StatsSnapshot {
+	samples := make([]*Stats, len(containers))
+	var wg sync.WaitGroup
+	for i, c := range containers {
+		wg.Add(1)
+		go func(i int, c *types.Container) {
+			defer wg.Done()
+			statsJSON, err := oneShotStats(context.Background(), client, c.ID)
+			if err != nil {
+				return
+			}
+			samples[i] = buildStats(c, statsJSON, nil)
+		}(i, c)
+	}
+	wg.Wait()
+	var snapshots []StatsSnapshot
+	for i, s := range samples {
+		if s != nil {
+			snapshots = append(snapshots, StatsSnapshot{Container: containers[i], Stats: s})
+		}
+	}
+	return snapshots
+}
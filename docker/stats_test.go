@@ -0,0 +1,519 @@
+package docker
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker/mock"
+	"golang.org/x/net/context"
+)
+
+func TestContainerStatsOnUnknownContainer(t *testing.T) {
+	daemon := &DockerDaemon{client: createClient()}
+
+	sc, err := ContainerStats(daemon, "nope")
+	if err == nil {
+		t.Error("Stats on an unknown container did not return an error")
+	}
+	if sc != nil {
+		t.Error("Stats on an unknown container returned a stats channel")
+	}
+}
+
+func TestContainerStatsOnStoppedContainer(t *testing.T) {
+	daemon := &DockerDaemon{client: createClient()}
+
+	_, err := ContainerStats(daemon, "1")
+	if err == nil {
+		t.Error("Stats on a container that is not running did not return an error")
+	} else if !strings.Contains(err.Error(), "not running") {
+		t.Errorf("Unexpected error on a container that is not running: %s", err.Error())
+	}
+}
+
+func TestBuildStatsWithPathologicalData(t *testing.T) {
+	var tests = []struct {
+		name  string
+		stats *types.StatsJSON
+	}{
+		{"empty stats", &types.StatsJSON{}},
+		{"cpu counters reset", func() *types.StatsJSON {
+			s := &types.StatsJSON{}
+			s.PreCPUStats.CPUUsage.TotalUsage = 2000
+			s.PreCPUStats.SystemUsage = 1000
+			s.CPUStats.CPUUsage.TotalUsage = 1000
+			s.CPUStats.SystemUsage = 2000
+			s.CPUStats.CPUUsage.PercpuUsage = []uint64{1000}
+			return s
+		}()},
+		{"no memory limit", func() *types.StatsJSON {
+			s := &types.StatsJSON{}
+			s.MemoryStats.Usage = 1024
+			return s
+		}()},
+	}
+	container := &types.Container{ID: "CID"}
+	for _, test := range tests {
+		s := buildStats(container, test.stats, nil)
+		for _, v := range []float64{s.CPUPercentage, s.CPUUserPercentage, s.CPUSystemPercentage, s.Memory, s.MemoryLimit, s.MemoryPercentage,
+			s.NetworkRx, s.NetworkTx, s.BlockRead, s.BlockWrite} {
+			if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 || v > 1e12 {
+				t.Errorf("Invalid metric value on %s: %f", test.name, v)
+			}
+		}
+	}
+}
+
+func TestCalculateCPUSplit(t *testing.T) {
+	s := &types.StatsJSON{}
+	s.CPUStats.CPUUsage.TotalUsage = 1000
+	s.CPUStats.CPUUsage.UsageInUsermode = 700
+	s.CPUStats.CPUUsage.UsageInKernelmode = 300
+	if user, system := calculateCPUSplit(s); user != 0 || system != 0 {
+		t.Errorf("Unexpected CPU split on the first sample: %f / %f", user, system)
+	}
+	s.PreCPUStats = s.CPUStats
+	s.CPUStats.CPUUsage.TotalUsage = 2000
+	s.CPUStats.CPUUsage.UsageInUsermode = 1500
+	s.CPUStats.CPUUsage.UsageInKernelmode = 500
+	if user, system := calculateCPUSplit(s); user != 80 || system != 20 {
+		t.Errorf("Unexpected CPU split. Expected: 80 / 20, got: %f / %f", user, system)
+	}
+	s.PreCPUStats = s.CPUStats
+	if user, system := calculateCPUSplit(s); user != 0 || system != 0 {
+		t.Errorf("Unexpected CPU split with no CPU used: %f / %f", user, system)
+	}
+}
+
+func TestSanitizeStats(t *testing.T) {
+	s := &Stats{
+		CPUPercentage:    math.NaN(),
+		MemoryPercentage: math.Inf(1),
+		NetworkRx:        math.Inf(-1),
+		BlockRead:        -1,
+		Memory:           1024,
+	}
+	sanitizeStats(s)
+	if s.CPUPercentage != 0 || s.MemoryPercentage != 0 || s.NetworkRx != 0 || s.BlockRead != 0 {
+		t.Errorf("Invalid values were not set to zero: %+v", s)
+	}
+	if s.Memory != 1024 {
+		t.Errorf("A valid value was changed. Expected: %f, got: %f", 1024.0, s.Memory)
+	}
+}
+
+//slowTopClient is a client whose ContainerTop takes longer than the
+//top timeout and ignores the context
+type slowTopClient struct {
+	mock.APIClientMock
+}
+
+func (c slowTopClient) ContainerTop(ctx context.Context, id string, arguments []string) (types.ContainerProcessList, error) {
+	time.Sleep(200 * time.Millisecond)
+	return types.ContainerProcessList{Titles: []string{"PID"}}, nil
+}
+
+func TestTopWithTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { topTimeout = timeout }(topTimeout)
+	topTimeout = 10 * time.Millisecond
+	start := time.Now()
+	if top, _ := topWithTimeout(context.Background(), slowTopClient{}, "0"); top != nil {
+		t.Errorf("A process list was returned after the timeout: %v", top)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Top did not time out, it took %s", elapsed)
+	}
+}
+
+//oneShotClient is a client that returns a single stats sample per request
+type oneShotClient struct {
+	slowTopClient
+	requests chan bool
+}
+
+func (c oneShotClient) ContainerStats(ctx context.Context, id string, stream bool) (types.ContainerStats, error) {
+	c.requests <- stream
+	body := ioutil.NopCloser(strings.NewReader(`{"pids_stats": {"current": 3}}`))
+	return types.ContainerStats{Body: body}, nil
+}
+
+func TestPollStats(t *testing.T) {
+	defer func(timeout time.Duration) { topTimeout = timeout }(topTimeout)
+	topTimeout = time.Millisecond
+	client := oneShotClient{requests: make(chan bool, 1)}
+	stats := make(chan *Stats)
+	done := make(chan struct{})
+	go pollStats(client, &fdCollector{count: -1}, newTopProbe(client, "0"), fixedPolling{}, &types.Container{ID: "0", Names: []string{"/web"}}, stats, done)
+
+	if stream := <-client.requests; stream {
+		t.Error("Stats were requested as a stream")
+	}
+	if s := <-stats; s.PidsCurrent != 3 || s.Interval != StatsInterval {
+		t.Errorf("Unexpected stats sample: %+v", s)
+	}
+	close(done)
+	for range stats {
+	}
+}
+
+//failingOneShotClient is a client whose first stats requests fail
+type failingOneShotClient struct {
+	slowTopClient
+	failures int
+	requests *int
+}
+
+func (c failingOneShotClient) ContainerStats(ctx context.Context, id string, stream bool) (types.ContainerStats, error) {
+	*c.requests++
+	if *c.requests <= c.failures {
+		return types.ContainerStats{}, errors.New("daemon is busy")
+	}
+	return types.ContainerStats{Body: ioutil.NopCloser(strings.NewReader(`{"pids_stats": {"current": 3}}`))}, nil
+}
+
+func TestPollStatsRetriesFailedRequests(t *testing.T) {
+	defer func(timeout, backoff time.Duration) { topTimeout, maxPollBackoff = timeout, backoff }(topTimeout, maxPollBackoff)
+	topTimeout, maxPollBackoff = time.Millisecond, time.Millisecond
+	container := &types.Container{ID: "0", Names: []string{"/web"}}
+
+	requests := 0
+	client := failingOneShotClient{failures: maxPollFailures - 1, requests: &requests}
+	stats := make(chan *Stats)
+	done := make(chan struct{})
+	go pollStats(client, &fdCollector{count: -1}, newTopProbe(client, "0"), fixedPolling{}, container, stats, done)
+	if s, ok := <-stats; !ok || s.PidsCurrent != 3 {
+		t.Errorf("No sample was received after retrying, requests: %d", requests)
+	}
+	close(done)
+	for range stats {
+	}
+
+	requests = 0
+	client = failingOneShotClient{failures: maxPollFailures, requests: &requests}
+	stats = make(chan *Stats)
+	go pollStats(client, &fdCollector{count: -1}, newTopProbe(client, "0"), fixedPolling{}, container, stats, make(chan struct{}))
+	if _, ok := <-stats; ok || requests != maxPollFailures {
+		t.Errorf("Polling did not stop after %d failed requests, requests: %d", maxPollFailures, requests)
+	}
+}
+
+func TestPollBackoff(t *testing.T) {
+	expected := []time.Duration{StatsInterval, 2 * StatsInterval, 4 * StatsInterval}
+	for i, backoff := range expected {
+		if b := pollBackoff(i + 1); b != backoff {
+			t.Errorf("Unexpected backoff after %d failures, expected: %s, got: %s", i+1, backoff, b)
+		}
+	}
+	if b := pollBackoff(100); b != maxPollBackoff {
+		t.Errorf("Backoff is not capped, got: %s", b)
+	}
+}
+
+//fakeStatsClient is a stats client that streams canned stats
+type fakeStatsClient struct {
+	slowTopClient
+	stream string
+}
+
+func (c fakeStatsClient) ContainerStats(ctx context.Context, id string, stream bool) (types.ContainerStats, error) {
+	return types.ContainerStats{Body: ioutil.NopCloser(strings.NewReader(c.stream))}, nil
+}
+
+func TestStatsSourceStreamsCannedStats(t *testing.T) {
+	defer func(timeout time.Duration) { topTimeout = timeout }(topTimeout)
+	topTimeout = time.Millisecond
+	source := statsSource{
+		client:  fakeStatsClient{stream: `{"pids_stats": {"current": 3}} {"pids_stats": {"current": 4}}`},
+		limiter: newRateLimiter(0),
+	}
+	channel := source.open(&types.Container{ID: "0", Names: []string{"/web"}, Status: "Up 1 hour"})
+
+	var pids []uint64
+	for s := range channel.Stats {
+		pids = append(pids, s.PidsCurrent)
+	}
+	if len(pids) != 1 || pids[0] != 4 {
+		t.Errorf("Unexpected stats samples, expected the second sample only, got: %v", pids)
+	}
+}
+
+//fdInspectClient is a stats client that streams canned stats and counts the
+//containers inspected
+type fdInspectClient struct {
+	fakeStatsClient
+	inspected *int
+}
+
+func (c fdInspectClient) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	*c.inspected++
+	return types.ContainerJSON{}, errors.New("not found")
+}
+
+func TestStatsSourceCountsOpenFDsOnlyIfAsked(t *testing.T) {
+	defer func(timeout time.Duration) { topTimeout = timeout }(topTimeout)
+	topTimeout = time.Millisecond
+	for _, openFDs := range []bool{false, true} {
+		inspected := 0
+		source := statsSource{
+			client:  fdInspectClient{fakeStatsClient{stream: `{"pids_stats": {"current": 3}}`}, &inspected},
+			limiter: newRateLimiter(0),
+			local:   true,
+			openFDs: openFDs,
+		}
+		for range source.open(&types.Container{ID: "0", Names: []string{"/web"}, Status: "Up 1 hour"}).Stats {
+		}
+		if counted := inspected > 0; counted != openFDs {
+			t.Errorf("Containers inspected to count open file descriptors: %t, expected: %t", counted, openFDs)
+		}
+	}
+}
+
+func TestCalculateBlockOps(t *testing.T) {
+	stats := &types.StatsJSON{}
+	if read, write := calculateBlockOps(stats); read != 0 || write != 0 {
+		t.Errorf("Unexpected block IO operations with no IO serviced entries: %d / %d", read, write)
+	}
+	stats.BlkioStats.IoServicedRecursive = []types.BlkioStatEntry{
+		{Op: "Read", Value: 10}, {Op: "Write", Value: 4}, {Op: "Read", Value: 5}, {Op: "Sync", Value: 100}}
+	if read, write := calculateBlockOps(stats); read != 15 || write != 4 {
+		t.Errorf("Unexpected block IO operations, expected 15 / 4, got: %d / %d", read, write)
+	}
+}
+
+func TestCalculateMemWorkingSet(t *testing.T) {
+	stats := &types.StatsJSON{}
+	stats.MemoryStats.Usage = 1000
+	if ws := calculateMemWorkingSet(stats); ws != 1000 {
+		t.Errorf("Unexpected working set with no cache reported: %f", ws)
+	}
+	stats.MemoryStats.Stats = map[string]uint64{"cache": 400}
+	if ws := calculateMemWorkingSet(stats); ws != 600 {
+		t.Errorf("Unexpected working set, expected 600, got: %f", ws)
+	}
+	stats.MemoryStats.Stats["cache"] = 2000
+	if ws := calculateMemWorkingSet(stats); ws != 1000 {
+		t.Errorf("Unexpected working set with more cache than usage: %f", ws)
+	}
+}
+
+//halfOpenClient is a stats client whose stream sends a sample and then blocks
+//until the request is cancelled, like a connection to a daemon that is gone
+type halfOpenClient struct {
+	slowTopClient
+}
+
+func (c halfOpenClient) ContainerStats(ctx context.Context, id string, stream bool) (types.ContainerStats, error) {
+	return types.ContainerStats{Body: ioutil.NopCloser(io.MultiReader(
+		strings.NewReader(`{"pids_stats": {"current": 3}}`),
+		&blockingReader{ctx: ctx}))}, nil
+}
+
+//blockingReader blocks on read until its context is done
+type blockingReader struct {
+	ctx context.Context
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	<-r.ctx.Done()
+	return 0, r.ctx.Err()
+}
+
+func TestStreamStatsTimesOutOnHalfOpenConnections(t *testing.T) {
+	defer func(timeout time.Duration) { StatsStreamTimeout = timeout }(StatsStreamTimeout)
+	StatsStreamTimeout = 50 * time.Millisecond
+	stats := make(chan *Stats)
+	errs := make(chan error, 1)
+	go streamStats(halfOpenClient{}, &fdCollector{count: -1}, newTopProbe(halfOpenClient{}, "0"), &types.Container{ID: "0", Names: []string{"/web"}}, stats, errs, make(chan struct{}))
+
+	select {
+	case _, ok := <-stats:
+		if ok {
+			t.Error("A sample was received from a stream with no samples")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stream on a half-open connection did not end")
+	}
+	select {
+	case err := <-errs:
+		if err != ErrStatsTimeout {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	default:
+		t.Error("No timeout error was sent")
+	}
+}
+
+func TestStreamStatsReportsStreamsNotOpened(t *testing.T) {
+	requests := 0
+	client := failingOneShotClient{failures: 1, requests: &requests}
+	stats := make(chan *Stats)
+	errs := make(chan error, 1)
+	go streamStats(client, &fdCollector{count: -1}, newTopProbe(client, "0"), &types.Container{ID: "0", Names: []string{"/web"}}, stats, errs, make(chan struct{}))
+
+	if _, ok := <-stats; ok {
+		t.Error("A sample was received from a stream that was not opened")
+	}
+	select {
+	case err := <-errs:
+		if err == nil || err.Error() != "daemon is busy" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	default:
+		t.Error("The error opening the stream was not sent")
+	}
+}
+
+func TestStreamStatsDoesNotTimeOutOnSlowReceivers(t *testing.T) {
+	defer func(timeout, top time.Duration) { StatsStreamTimeout, topTimeout = timeout, top }(StatsStreamTimeout, topTimeout)
+	StatsStreamTimeout, topTimeout = 50*time.Millisecond, time.Millisecond
+	stats := make(chan *Stats)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	go streamStats(endlessStatsClient{}, &fdCollector{count: -1}, newTopProbe(endlessStatsClient{}, "0"), &types.Container{ID: "0", Names: []string{"/web"}}, stats, errs, done)
+
+	<-stats
+	time.Sleep(3 * StatsStreamTimeout)
+	if _, ok := <-stats; !ok {
+		t.Error("Stream ended while the receiver was busy")
+	}
+	close(done)
+	for range stats {
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("Unexpected error: %v", err)
+	default:
+	}
+}
+
+//endlessStatsClient is a stats client whose stream never ends
+type endlessStatsClient struct {
+	slowTopClient
+}
+
+func (c endlessStatsClient) ContainerStats(ctx context.Context, id string, stream bool) (types.ContainerStats, error) {
+	return types.ContainerStats{Body: ioutil.NopCloser(&repeatingReader{sample: `{"pids_stats": {"current": 3}} `})}, nil
+}
+
+//repeatingReader reads the same sample over and over
+type repeatingReader struct {
+	sample string
+	r      io.Reader
+}
+
+func (r *repeatingReader) Read(p []byte) (int, error) {
+	if r.r == nil {
+		r.r = strings.NewReader(r.sample)
+	}
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		r.r = nil
+		err = nil
+	}
+	return n, err
+}
+
+func TestStatsProducersExitWhenDoneWhileSending(t *testing.T) {
+	defer func(timeout time.Duration) { topTimeout = timeout }(topTimeout)
+	topTimeout = time.Millisecond
+	container := &types.Container{ID: "0", Names: []string{"/web"}}
+	producers := map[string]func(stats chan<- *Stats, done <-chan struct{}){
+		"stream": func(stats chan<- *Stats, done <-chan struct{}) {
+			streamStats(endlessStatsClient{}, &fdCollector{count: -1}, newTopProbe(endlessStatsClient{}, "0"), container, stats, make(chan error, 1), done)
+		},
+		"poll": func(stats chan<- *Stats, done <-chan struct{}) {
+			client := oneShotClient{requests: make(chan bool, 10)}
+			pollStats(client, &fdCollector{count: -1}, newTopProbe(client, "0"), fixedPolling{}, container, stats, done)
+		},
+	}
+	for name, produce := range producers {
+		stats := make(chan *Stats)
+		done := make(chan struct{})
+		returned := make(chan struct{})
+		go func() {
+			produce(stats, done)
+			close(returned)
+		}()
+		//Nobody receives, so the producer ends up blocked sending a sample
+		time.Sleep(StatsInterval + 200*time.Millisecond)
+		close(done)
+		select {
+		case <-returned:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s: producer blocked on send did not exit after done was closed", name)
+		}
+		//The stats channel is closed, so a receiver ranging over it exits too
+		consumed := make(chan struct{})
+		go func() {
+			for range stats {
+			}
+			close(consumed)
+		}()
+		select {
+		case <-consumed:
+		case <-time.After(time.Second):
+			t.Errorf("%s: stats channel was not closed", name)
+		}
+	}
+}
+
+func TestStatsRound(t *testing.T) {
+	client := fakeStatsClient{stream: `{"pids_stats": {"current": 3}}`}
+	containers := []*types.Container{{ID: "1", Names: []string{"/web"}}, {ID: "2", Names: []string{"/db"}}}
+	snapshots := statsRound(client, containers)
+	if len(snapshots) != 2 {
+		t.Fatalf("Unexpected number of samples: %d", len(snapshots))
+	}
+	for i, s := range snapshots {
+		if s.Container != containers[i] || s.Stats.PidsCurrent != 3 {
+			t.Errorf("Unexpected sample of container %s: %+v", containers[i].ID, s.Stats)
+		}
+	}
+	if snapshots := statsRound(fakeStatsClient{stream: "not json"}, containers); len(snapshots) != 0 {
+		t.Errorf("Samples returned for containers with no stats: %d", len(snapshots))
+	}
+}
+
+//idRecordingClient is a stats client that records the container each stats
+//request was made for and fails it
+type idRecordingClient struct {
+	slowTopClient
+	requested chan string
+}
+
+func (c idRecordingClient) ContainerStats(ctx context.Context, id string, stream bool) (types.ContainerStats, error) {
+	c.requested <- id
+	return types.ContainerStats{}, errors.New("no stats")
+}
+
+func TestStatsAreRequestedByIDAndNamesShownWithoutSlash(t *testing.T) {
+	container := &types.Container{ID: "8dfafdbc3a40", Names: []string{"/web"}}
+	client := idRecordingClient{requested: make(chan string, 2)}
+
+	streamStats(client, &fdCollector{count: -1}, newTopProbe(client, container.ID), container, make(chan *Stats), make(chan error, 1), make(chan struct{}))
+	if id := <-client.requested; id != container.ID {
+		t.Errorf("Stats stream was not requested by container ID, got: %s", id)
+	}
+	done := make(chan struct{})
+	go pollStats(client, &fdCollector{count: -1}, newTopProbe(client, container.ID), fixedPolling{}, container, make(chan *Stats), done)
+	if id := <-client.requested; id != container.ID {
+		t.Errorf("Stats sample was not requested by container ID, got: %s", id)
+	}
+	close(done)
+
+	if name := NewContainerFormatter(container, true).Names(); name != "web" {
+		t.Errorf("Unexpected container name shown, expected web, got: %s", name)
+	}
+	if name := ContainerName(container); name != "web" {
+		t.Errorf("Unexpected container name, expected web, got: %s", name)
+	}
+	if container.Names[0] != "/web" {
+		t.Errorf("Container names were modified while formatting: %v", container.Names)
+	}
+}
@@ -0,0 +1,34 @@
+package docker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+//Labels set by Docker on the containers of swarm tasks
+const (
+	swarmServiceNameLabel = "com.docker.swarm.service.name"
+	swarmTaskNameLabel    = "com.docker.swarm.task.name"
+)
+
+//SwarmReplica returns the swarm service and the replica slot of the task of the
+//given container, i.e. web.2, read from its labels. Tasks of global services,
+//which have no slot, are shown as the service. False is returned if the container
+//is not a swarm task.
+func SwarmReplica(c *types.Container) (string, bool) {
+	service := c.Labels[swarmServiceNameLabel]
+	if service == "" {
+		return "", false
+	}
+	//Task names are <service>.<slot>.<task id>, or <service>.<node id>.<task id>
+	//for global services
+	task := strings.TrimPrefix(c.Labels[swarmTaskNameLabel], service+".")
+	if i := strings.Index(task, "."); i > 0 {
+		if slot, err := strconv.Atoi(task[:i]); err == nil {
+			return service + "." + strconv.Itoa(slot), true
+		}
+	}
+	return service, true
+}
@@ -0,0 +1,27 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestSwarmReplica(t *testing.T) {
+	tests := []struct {
+		labels   map[string]string
+		expected string
+		ok       bool
+	}{
+		{map[string]string{swarmServiceNameLabel: "web", swarmTaskNameLabel: "web.2.x8jm9bs0c7wqg6y5kmyorm0bn"}, "web.2", true},
+		{map[string]string{swarmServiceNameLabel: "agent", swarmTaskNameLabel: "agent.ndb1uqf8l6u6ihwsd1sejyq2i.x8jm9bs0c7wqg6y5kmyorm0bn"}, "agent", true},
+		{map[string]string{swarmServiceNameLabel: "web"}, "web", true},
+		{map[string]string{"com.docker.compose.service": "web"}, "", false},
+		{nil, "", false},
+	}
+	for _, test := range tests {
+		replica, ok := SwarmReplica(&types.Container{Labels: test.labels})
+		if replica != test.expected || ok != test.ok {
+			t.Errorf("Unexpected replica of a container with labels %v. Expected: %s, got: %s", test.labels, test.expected, replica)
+		}
+	}
+}
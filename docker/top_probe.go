@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	pkgError "github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+//maxTopFailures is the number of consecutive requests for the process list of
+//a container failing as unsupported after which top is assumed to be unsupported
+//by the daemon
+const maxTopFailures = 3
+
+//topReprobeInterval is the time after which the process list of a container is
+//requested again once top is assumed to be unsupported
+var topReprobeInterval = time.Minute
+
+//topProbe requests the process list of a container when collecting its stats
+//and stops doing so once top fails persistently as unsupported, i.e. on the
+//platform or configuration of the daemon, trying again every topReprobeInterval.
+//It is not safe for concurrent use.
+type topProbe struct {
+	client      StatsClient
+	id          string
+	failures    int
+	unsupported bool
+	reprobeAt   time.Time
+}
+
+func newTopProbe(client StatsClient, id string) *topProbe {
+	return &topProbe{client: client, id: id}
+}
+
+//processList returns the process list of the container, nil if it cannot be
+//retrieved in time or if top is unsupported.
+//Only requests failing as unsupported count as failures, slow responses and
+//other errors, i.e. the container is restarting, do not.
+func (p *topProbe) processList(ctx context.Context) *types.ContainerProcessList {
+	if p.unsupported && time.Now().Before(p.reprobeAt) {
+		return nil
+	}
+	top, err := topWithTimeout(ctx, p.client, p.id)
+	switch {
+	case err == nil:
+		p.failures = 0
+		p.unsupported = false
+	case isTopUnsupported(err):
+		p.failures++
+		if p.failures >= maxTopFailures {
+			p.unsupported = true
+			p.reprobeAt = time.Now().Add(topReprobeInterval)
+		}
+	}
+	return top
+}
+
+//setProcessList sets the process list of the given stats
+func (p *topProbe) setProcessList(ctx context.Context, s *Stats) {
+	s.ProcessList = p.processList(ctx)
+	s.TopUnsupported = p.unsupported
+}
+
+//isTopUnsupported returns true if the given error of a top request says
+//that top is not supported or not implemented by the daemon
+func isTopUnsupported(err error) bool {
+	cause := pkgError.Cause(err)
+	if cause == context.DeadlineExceeded || cause == context.Canceled {
+		return false
+	}
+	msg := strings.ToLower(cause.Error())
+	return strings.Contains(msg, "not implemented") ||
+		strings.Contains(msg, "not supported") ||
+		strings.Contains(msg, "does not support")
+}
@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker/mock"
+	pkgError "github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+//unsupportedTopClient is a client whose ContainerTop fails with the given
+//error, "top is not supported" if not set
+type unsupportedTopClient struct {
+	mock.APIClientMock
+	requests *int
+	err      error
+}
+
+func (c unsupportedTopClient) ContainerTop(ctx context.Context, id string, arguments []string) (types.ContainerProcessList, error) {
+	*c.requests++
+	if c.err != nil {
+		return types.ContainerProcessList{}, c.err
+	}
+	return types.ContainerProcessList{}, errors.New("top is not supported")
+}
+
+//deadlineTopClient is a client whose ContainerTop waits for the context to
+//end and returns its error wrapped, as real clients do
+type deadlineTopClient struct {
+	mock.APIClientMock
+}
+
+func (c deadlineTopClient) ContainerTop(ctx context.Context, id string, arguments []string) (types.ContainerProcessList, error) {
+	<-ctx.Done()
+	return types.ContainerProcessList{}, pkgError.Wrap(ctx.Err(), "error during connect")
+}
+
+func TestTopProbeStopsAfterPersistentFailures(t *testing.T) {
+	requests := 0
+	probe := newTopProbe(unsupportedTopClient{requests: &requests}, "0")
+	for i := 0; i < maxTopFailures*2; i++ {
+		s := &Stats{}
+		probe.setProcessList(context.Background(), s)
+		if s.ProcessList != nil {
+			t.Errorf("A process list was set after a failed top: %v", s.ProcessList)
+		}
+		if unsupported := i >= maxTopFailures-1; s.TopUnsupported != unsupported {
+			t.Errorf("Unexpected top state after %d failures. Expected unsupported: %t, got: %t", i+1, unsupported, s.TopUnsupported)
+		}
+	}
+	if requests != maxTopFailures {
+		t.Errorf("Unexpected number of top requests. Expected: %d, got: %d", maxTopFailures, requests)
+	}
+}
+
+func TestTopProbeIgnoresTimeouts(t *testing.T) {
+	defer func(timeout time.Duration) { topTimeout = timeout }(topTimeout)
+	topTimeout = time.Millisecond
+	probe := newTopProbe(slowTopClient{}, "0")
+	for i := 0; i < maxTopFailures; i++ {
+		probe.processList(context.Background())
+	}
+	if probe.unsupported {
+		t.Error("Top was marked as unsupported after timing out")
+	}
+	probe = newTopProbe(deadlineTopClient{}, "0")
+	for i := 0; i < maxTopFailures; i++ {
+		probe.processList(context.Background())
+	}
+	if probe.unsupported || probe.failures != 0 {
+		t.Errorf("Top was marked as unsupported after wrapped timeouts, failures: %d", probe.failures)
+	}
+}
+
+func TestTopProbeIgnoresOtherErrors(t *testing.T) {
+	requests := 0
+	probe := newTopProbe(unsupportedTopClient{requests: &requests, err: errors.New("Error response from daemon: Container 0 is not running")}, "0")
+	for i := 0; i < maxTopFailures*2; i++ {
+		probe.processList(context.Background())
+	}
+	if probe.unsupported || requests != maxTopFailures*2 {
+		t.Errorf("Top was marked as unsupported after errors other than unsupported, requests: %d", requests)
+	}
+}
+
+func TestTopProbeProbesAgain(t *testing.T) {
+	requests := 0
+	client := &unsupportedTopClient{requests: &requests}
+	probe := newTopProbe(client, "0")
+	for i := 0; i < maxTopFailures; i++ {
+		probe.processList(context.Background())
+	}
+	if !probe.unsupported {
+		t.Fatal("Top was not marked as unsupported")
+	}
+	probe.reprobeAt = time.Now().Add(-time.Second)
+	probe.client = slowTopClient{}
+	defer func(timeout time.Duration) { topTimeout = timeout }(topTimeout)
+	topTimeout = time.Second
+	if top := probe.processList(context.Background()); top == nil || probe.unsupported {
+		t.Errorf("Top was not probed again, process list: %v", top)
+	}
+}
+
+func TestIsTopUnsupported(t *testing.T) {
+	tests := []struct {
+		err         error
+		unsupported bool
+	}{
+		{errors.New("Error response from daemon: Windows does not support top"), true},
+		{errors.New("Error: request returned Not Implemented for API route and version /containers/0/top"), true},
+		{pkgError.Wrap(errors.New("top is not supported"), "top"), true},
+		{errors.New("Error response from daemon: Container 0 is not running"), false},
+		{pkgError.Wrap(context.DeadlineExceeded, "error during connect"), false},
+	}
+	for _, test := range tests {
+		if unsupported := isTopUnsupported(test.err); unsupported != test.unsupported {
+			t.Errorf("Unexpected classification of %q. Expected unsupported: %t, got: %t", test.err, test.unsupported, unsupported)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/events"
@@ -9,12 +10,17 @@ import (
 
 //ContainerDaemon describes what is expected from the container daemon
 type ContainerDaemon interface {
+	ContainerLimits(id string) (ContainerLimits, error)
+	ContainerSizes() (map[string]int64, error)
 	ContainerStore() *ContainerStore
+	ContainerTimeline(c *types.Container) (*ContainerTimeline, chan<- struct{}, error)
 	DiskUsage() (types.DiskUsage, error)
 	DockerEnv() *Env
 	Events() (<-chan events.Message, chan<- struct{}, error)
 	EventLog() *EventLog
 	History(id string) ([]types.ImageHistory, error)
+	HostCPUs() int
+	HostMemory() int64
 	ImageAt(pos int) (*types.ImageSummary, error)
 	Images() ([]types.ImageSummary, error)
 	ImagesCount() int
@@ -30,7 +36,9 @@ type ContainerDaemon interface {
 	NetworkInspect(id string) (types.NetworkResource, error)
 	Ok() (bool, error)
 	OpenChannel(container *types.Container) *StatsChannel
+	Ping() error
 	Prune() (*PruneReport, error)
+	ReloadTLS() error
 	RestartContainer(id string) error
 	Rm(id string) error
 	Rmi(id string, force bool) ([]types.ImageDelete, error)
@@ -51,19 +59,46 @@ type ContainerDaemon interface {
 
 //Stats holds runtime stats for a container
 type Stats struct {
-	CID              string
-	Command          string
-	CPUPercentage    float64
-	Memory           float64
-	MemoryLimit      float64
-	MemoryPercentage float64
+	CID           string
+	Command       string
+	CPUPercentage float64
+	//CPUUserPercentage and CPUSystemPercentage are the percentages of the CPU
+	//time of the container spent in user and in kernel mode
+	CPUUserPercentage   float64
+	CPUSystemPercentage float64
+	Memory              float64
+	MemoryLimit         float64
+	MemoryPercentage    float64
+	//MemoryWorkingSet is the memory used minus the page cache, which the
+	//kernel reclaims before killing the container for running out of memory
+	MemoryWorkingSet float64
 	NetworkRx        float64
 	NetworkTx        float64
 	BlockRead        float64
 	BlockWrite       float64
-	PidsCurrent      uint64
-	Stats            *types.StatsJSON
-	ProcessList      *types.ContainerProcessList
+	//BlockReadOps and BlockWriteOps are the number of block IO operations
+	//performed by the container
+	BlockReadOps  float64
+	BlockWriteOps float64
+	PidsCurrent   uint64
+	//OpenFDs is the number of open file descriptors of the container main
+	//process, -1 if unknown
+	OpenFDs     int
+	Stats       *types.StatsJSON
+	ProcessList *types.ContainerProcessList
+	//TopUnsupported is true if the process list of the container is not
+	//requested anymore as it failed persistently
+	TopUnsupported bool
+	//Interval is the time until the next sample of the container, as decided
+	//by the polling policy. It is zero for samples read from a stats stream,
+	//which are received every StatsInterval.
+	Interval time.Duration
+}
+
+//StatsSnapshot is the last stats sample received of a container
+type StatsSnapshot struct {
+	Container *types.Container
+	Stats     *Stats
 }
 
 //PruneReport represents the result of a prune operation
@@ -0,0 +1,64 @@
+package export
+
+import "errors"
+
+//DefaultNotificationQueueSize is the default number of breaches waiting to be
+//delivered by an AsyncNotifier
+const DefaultNotificationQueueSize = 100
+
+//ErrNotificationQueueFull is returned when a breach cannot be queued for delivery
+var ErrNotificationQueueFull = errors.New("Too many alerts waiting to be notified, alert dropped")
+
+//AsyncNotifier is a Notifier that delivers breaches to another notifier on its
+//own goroutine, so slow notifiers, i.e. webhooks retrying a request, do not
+//delay checking the thresholds. Breaches are delivered in order.
+type AsyncNotifier struct {
+	notifier Notifier
+	queue    chan Breach
+	onError  func(b Breach, err error)
+	done     chan struct{}
+}
+
+//NewAsyncNotifier creates an AsyncNotifier delivering breaches to the given
+//notifier, up to queueSize breaches wait to be delivered. If given, onError is
+//called with the breaches whose delivery failed.
+func NewAsyncNotifier(n Notifier, queueSize int, onError func(b Breach, err error)) *AsyncNotifier {
+	if queueSize <= 0 {
+		queueSize = DefaultNotificationQueueSize
+	}
+	a := &AsyncNotifier{
+		notifier: n,
+		queue:    make(chan Breach, queueSize),
+		onError:  onError,
+		done:     make(chan struct{}),
+	}
+	go a.deliver()
+	return a
+}
+
+//Notify queues the given breach for delivery, ErrNotificationQueueFull is
+//returned if it cannot be queued.
+func (a *AsyncNotifier) Notify(b Breach) error {
+	select {
+	case a.queue <- b:
+		return nil
+	default:
+		return ErrNotificationQueueFull
+	}
+}
+
+//Close stops delivering breaches once the queued ones are delivered, it returns
+//when they are. No breach can be notified after closing.
+func (a *AsyncNotifier) Close() {
+	close(a.queue)
+	<-a.done
+}
+
+func (a *AsyncNotifier) deliver() {
+	defer close(a.done)
+	for b := range a.queue {
+		if err := a.notifier.Notify(b); err != nil && a.onError != nil {
+			a.onError(b, err)
+		}
+	}
+}
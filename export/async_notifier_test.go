@@ -0,0 +1,60 @@
+package export
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAsyncNotifierDoesNotWaitForDelivery(t *testing.T) {
+	release := make(chan struct{})
+	var delivered []string
+	slow := NotifierFunc(func(b Breach) error {
+		<-release
+		delivered = append(delivered, b.Container)
+		if b.Container == "db" {
+			return errors.New("webhook is down")
+		}
+		return nil
+	})
+	var failed []string
+	notifier := NewAsyncNotifier(slow, 2, func(b Breach, err error) {
+		failed = append(failed, b.Container)
+	})
+
+	start := time.Now()
+	for _, container := range []string{"web", "db"} {
+		if err := notifier.Notify(Breach{Container: container}); err != nil {
+			t.Fatalf("Breach was not queued: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Notify waited for the delivery, it took %s", elapsed)
+	}
+	close(release)
+	notifier.Close()
+	if len(delivered) != 2 || delivered[0] != "web" || delivered[1] != "db" {
+		t.Errorf("Unexpected breaches delivered: %v", delivered)
+	}
+	if len(failed) != 1 || failed[0] != "db" {
+		t.Errorf("Unexpected failed deliveries: %v", failed)
+	}
+}
+
+func TestAsyncNotifierQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	notifier := NewAsyncNotifier(NotifierFunc(func(b Breach) error {
+		<-release
+		return nil
+	}), 1, nil)
+	defer notifier.Close()
+	defer close(release)
+
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = notifier.Notify(Breach{Container: "web"})
+	}
+	if err != ErrNotificationQueueFull {
+		t.Errorf("Unexpected error with a full queue: %v", err)
+	}
+}
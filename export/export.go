@@ -0,0 +1,11 @@
+//Package export sends the container stats collected by dry to external
+//monitoring systems.
+package export
+
+import "github.com/moncho/dry/docker"
+
+//SnapshotSource provides the last stats sample of each monitored container,
+//it is shared by every exporter.
+type SnapshotSource interface {
+	Snapshot() []docker.StatsSnapshot
+}
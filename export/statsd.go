@@ -0,0 +1,105 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/moncho/dry/docker"
+)
+
+//DefaultStatsDPrefix is the default prefix of the metrics sent to StatsD
+const DefaultStatsDPrefix = "dry"
+
+//StatsD sends container stats, as gauges, to a StatsD server over UDP.
+//A nil StatsD does nothing, so it can be used when no server is configured.
+type StatsD struct {
+	prefix string
+	conn   net.Conn
+}
+
+//NewStatsD creates a StatsD emitter that sends metrics to the server on the given
+//address, host:port, with the given prefix. If no address is given, nil is returned.
+func NewStatsD(address, prefix string) (*StatsD, error) {
+	if address == "" {
+		return nil, nil
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	if prefix == "" {
+		prefix = DefaultStatsDPrefix
+	}
+	return &StatsD{prefix: prefix, conn: conn}, nil
+}
+
+//Run sends the stats provided by the given source on every interval until the
+//given context is done.
+func (s *StatsD) Run(ctx context.Context, source SnapshotSource, interval time.Duration) {
+	if s == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Emit(source.Snapshot())
+		}
+	}
+}
+
+//Emit sends the given stats, one UDP packet per container
+func (s *StatsD) Emit(snapshots []docker.StatsSnapshot) error {
+	if s == nil {
+		return nil
+	}
+	for _, snapshot := range snapshots {
+		if _, err := s.conn.Write(s.metrics(snapshot)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Close closes the connection with the StatsD server
+func (s *StatsD) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+//metrics returns the gauges of the given snapshot in the StatsD line format,
+//metric names are <prefix>.<container name>.<metric>.
+func (s *StatsD) metrics(snapshot docker.StatsSnapshot) []byte {
+	r := snapshot.Record()
+	name := r.ContainerName
+	if name == "" {
+		name = docker.TruncateID(r.ContainerID)
+	}
+	path := s.prefix + "." + metricName(name)
+	buf := new(bytes.Buffer)
+	gauge := func(name string, value float64) {
+		fmt.Fprintf(buf, "%s.%s:%g|g\n", path, name, value)
+	}
+	gauge("cpu", r.CPUPercentage)
+	gauge("mem", r.Memory)
+	gauge("mem_percent", r.MemoryPercentage)
+	gauge("net_rx", r.NetworkRx)
+	gauge("net_tx", r.NetworkTx)
+	gauge("block_read", r.BlockRead)
+	gauge("block_write", r.BlockWrite)
+	gauge("pids", float64(r.Pids))
+	return buf.Bytes()
+}
+
+//metricName replaces the characters that have a meaning in StatsD metric names
+var metricName = strings.NewReplacer(".", "_", ":", "_", "|", "_", "@", "_", "/", "_", " ", "_").Replace
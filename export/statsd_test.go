@@ -0,0 +1,55 @@
+package export
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestStatsDEmit(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	statsd, err := NewStatsD(server.LocalAddr().String(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer statsd.Close()
+
+	snapshot := docker.StatsSnapshot{
+		Container: &types.Container{ID: "CID", Names: []string{"/web.1"}},
+		Stats:     &docker.Stats{CPUPercentage: 12.5, PidsCurrent: 3},
+	}
+	if err := statsd.Emit([]docker.StatsSnapshot{snapshot}); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := server.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packet := string(buf[:n])
+	for _, metric := range []string{"test.web_1.cpu:12.5|g", "test.web_1.pids:3|g"} {
+		if !strings.Contains(packet, metric) {
+			t.Errorf("Metric %s was not sent, got: %s", metric, packet)
+		}
+	}
+}
+
+func TestStatsDIsNoOpWhenUnconfigured(t *testing.T) {
+	statsd, err := NewStatsD("", "")
+	if err != nil || statsd != nil {
+		t.Errorf("Unexpected emitter with no address: %v, error: %v", statsd, err)
+	}
+	if err := statsd.Emit([]docker.StatsSnapshot{{}}); err != nil {
+		t.Errorf("An unconfigured emitter returned an error: %s", err.Error())
+	}
+}
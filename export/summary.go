@@ -0,0 +1,63 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	units "github.com/docker/go-units"
+	"github.com/moncho/dry/docker"
+)
+
+//DefaultSummaryFormat is the default format of the stats summary
+const DefaultSummaryFormat = `{{.Containers}} containers CPU {{percent .CPU}} MEM {{bytes .Memory}}`
+
+//Summary are the totals of the stats of a set of containers
+type Summary struct {
+	Containers int
+	CPU        float64
+	Memory     float64
+	NetworkRx  float64
+	NetworkTx  float64
+	BlockRead  float64
+	BlockWrite float64
+	Pids       uint64
+}
+
+//summaryFuncs are the functions available on summary formats
+var summaryFuncs = template.FuncMap{
+	"bytes":   func(v float64) string { return units.BytesSize(v) },
+	"percent": func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+}
+
+//Summarize returns the totals of the given stats samples
+func Summarize(snapshots []docker.StatsSnapshot) Summary {
+	var s Summary
+	for _, snapshot := range snapshots {
+		r := snapshot.Record()
+		s.Containers++
+		s.CPU += r.CPUPercentage
+		s.Memory += r.Memory
+		s.NetworkRx += r.NetworkRx
+		s.NetworkTx += r.NetworkTx
+		s.BlockRead += r.BlockRead
+		s.BlockWrite += r.BlockWrite
+		s.Pids += r.Pids
+	}
+	return s
+}
+
+//WriteSummary writes the given summary on a line using the given format, a
+//text/template whose fields are the ones of Summary. The bytes and percent
+//functions format sizes and percentages, i.e. {{bytes .Memory}}.
+func WriteSummary(w io.Writer, format string, s Summary) error {
+	t, err := template.New("summary").Funcs(summaryFuncs).Parse(format)
+	if err != nil {
+		return fmt.Errorf("Invalid summary format: %s", err)
+	}
+	if err := t.Execute(w, s); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestSummary(t *testing.T) {
+	s := Summarize([]docker.StatsSnapshot{
+		{Container: &types.Container{ID: "1"}, Stats: &docker.Stats{CPUPercentage: 10.5, Memory: 1024, PidsCurrent: 3}},
+		{Container: &types.Container{ID: "2"}, Stats: &docker.Stats{CPUPercentage: 4.5, Memory: 2048, PidsCurrent: 2}},
+	})
+	if s.Containers != 2 || s.CPU != 15 || s.Memory != 3072 || s.Pids != 5 {
+		t.Errorf("Unexpected summary: %+v", s)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSummary(&buf, DefaultSummaryFormat, s); err != nil {
+		t.Fatalf("Unexpected error writing the summary: %s", err)
+	}
+	if buf.String() != "2 containers CPU 15.00% MEM 3 KiB\n" {
+		t.Errorf("Unexpected summary line: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteSummary(&buf, "{{.Containers}}|{{.Pids}}", Summarize(nil)); err != nil || buf.String() != "0|0\n" {
+		t.Errorf("Unexpected summary line with no containers: %q, %v", buf.String(), err)
+	}
+	if err := WriteSummary(&buf, "{{.Containers", s); err == nil {
+		t.Error("No error writing a summary with an invalid format")
+	}
+}
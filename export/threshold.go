@@ -0,0 +1,204 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/moncho/dry/docker"
+)
+
+//DefaultAlertCooldown is the default minimum time between two notifications
+//of a breach of the same threshold by the same container
+const DefaultAlertCooldown = 5 * time.Minute
+
+//DefaultAlertHysteresis is the default fraction of a threshold that a value has
+//to drop below the threshold for the breach to be over
+const DefaultAlertHysteresis = 0.1
+
+//thresholdMetrics are the metrics that can have a threshold, by name
+var thresholdMetrics = map[string]func(r docker.StatsRecord) float64{
+	"cpu":         func(r docker.StatsRecord) float64 { return r.CPUPercentage },
+	"mem":         func(r docker.StatsRecord) float64 { return r.Memory },
+	"mem_percent": func(r docker.StatsRecord) float64 { return r.MemoryPercentage },
+	"net_rx":      func(r docker.StatsRecord) float64 { return r.NetworkRx },
+	"net_tx":      func(r docker.StatsRecord) float64 { return r.NetworkTx },
+	"block_read":  func(r docker.StatsRecord) float64 { return r.BlockRead },
+	"block_write": func(r docker.StatsRecord) float64 { return r.BlockWrite },
+	"pids":        func(r docker.StatsRecord) float64 { return float64(r.Pids) },
+}
+
+//Threshold is the maximum value of a container metric
+type Threshold struct {
+	Metric string
+	Value  float64
+}
+
+//ParseThreshold parses a threshold given as metric=value, i.e. cpu=90. The
+//metrics are cpu, mem, mem_percent, net_rx, net_tx, block_read, block_write
+//and pids.
+func ParseThreshold(s string) (Threshold, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return Threshold{}, fmt.Errorf("Invalid threshold, expected metric=value: %s", s)
+	}
+	metric := strings.TrimSpace(parts[0])
+	if _, ok := thresholdMetrics[metric]; !ok {
+		return Threshold{}, fmt.Errorf("Unknown metric %s, known metrics: %s", metric, strings.Join(metricNames(), ", "))
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return Threshold{}, fmt.Errorf("Invalid value for threshold %s: %s", metric, parts[1])
+	}
+	return Threshold{Metric: metric, Value: value}, nil
+}
+
+func metricNames() []string {
+	var names []string
+	for name := range thresholdMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//Breach is a container metric over its threshold
+type Breach struct {
+	Container string    `json:"container"`
+	Metric    string    `json:"metric"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (b Breach) String() string {
+	return fmt.Sprintf("%s: %s is %.2f, over %.2f", b.Container, b.Metric, b.Value, b.Threshold)
+}
+
+//Notifier is notified of threshold breaches
+type Notifier interface {
+	Notify(b Breach) error
+}
+
+//NotifierFunc is a function that can be used as a Notifier
+type NotifierFunc func(b Breach) error
+
+//Notify calls f(b)
+func (f NotifierFunc) Notify(b Breach) error {
+	return f(b)
+}
+
+//breachKey identifies the breach of a threshold by a container
+type breachKey struct {
+	container string
+	metric    string
+}
+
+//ThresholdWatcher checks container stats against thresholds and notifies its
+//notifiers when a container goes over a threshold.
+//A breach is notified once, and it is over once the value drops below the
+//threshold by the Hysteresis fraction of the threshold. A new breach of the
+//same threshold by the same container is not notified until Cooldown has
+//passed since the last notification.
+type ThresholdWatcher struct {
+	Cooldown   time.Duration
+	Hysteresis float64
+	thresholds []Threshold
+	notifiers  []Notifier
+	//active breaches
+	active map[breachKey]bool
+	//time of the last notification of each breach
+	notified map[breachKey]time.Time
+	sync.Mutex
+}
+
+//NewThresholdWatcher creates a watcher of the given thresholds, it uses the
+//default cooldown and hysteresis.
+func NewThresholdWatcher(thresholds []Threshold, notifiers ...Notifier) *ThresholdWatcher {
+	return &ThresholdWatcher{
+		Cooldown:   DefaultAlertCooldown,
+		Hysteresis: DefaultAlertHysteresis,
+		thresholds: thresholds,
+		notifiers:  notifiers,
+		active:     make(map[breachKey]bool),
+		notified:   make(map[breachKey]time.Time),
+	}
+}
+
+//AddNotifier adds the given notifier to the ones notified of breaches
+func (w *ThresholdWatcher) AddNotifier(n Notifier) {
+	w.Lock()
+	defer w.Unlock()
+	w.notifiers = append(w.notifiers, n)
+}
+
+//Run checks the stats provided by the given source on every interval until
+//the given context is done. Notifiers are called on the goroutine running the
+//checks, slow notifiers can be wrapped with NewAsyncNotifier.
+func (w *ThresholdWatcher) Run(ctx context.Context, source SnapshotSource, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.Check(source.Snapshot(), now)
+		}
+	}
+}
+
+//Check checks the given stats against the thresholds and notifies the new
+//breaches, the first error returned by a notifier is returned.
+func (w *ThresholdWatcher) Check(snapshots []docker.StatsSnapshot, now time.Time) error {
+	w.Lock()
+	var breaches []Breach
+	for _, snapshot := range snapshots {
+		r := snapshot.Record()
+		container := r.ContainerName
+		if container == "" {
+			container = docker.TruncateID(r.ContainerID)
+		}
+		for _, t := range w.thresholds {
+			key := breachKey{container: container, metric: t.Metric}
+			value := thresholdMetrics[t.Metric](r)
+			if w.active[key] {
+				if value < t.Value-t.Value*w.Hysteresis {
+					delete(w.active, key)
+				}
+				continue
+			}
+			if value <= t.Value {
+				continue
+			}
+			w.active[key] = true
+			if last, ok := w.notified[key]; ok && now.Sub(last) < w.Cooldown {
+				continue
+			}
+			w.notified[key] = now
+			breaches = append(breaches, Breach{
+				Container: container,
+				Metric:    t.Metric,
+				Value:     value,
+				Threshold: t.Value,
+				Timestamp: now})
+		}
+	}
+	notifiers := w.notifiers
+	w.Unlock()
+
+	var err error
+	for _, b := range breaches {
+		for _, n := range notifiers {
+			if errN := n.Notify(b); errN != nil && err == nil {
+				err = errN
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/moncho/dry/docker"
+)
+
+func TestParseThreshold(t *testing.T) {
+	if th, err := ParseThreshold("cpu=90.5"); err != nil || th != (Threshold{Metric: "cpu", Value: 90.5}) {
+		t.Errorf("Unexpected threshold: %+v, error: %v", th, err)
+	}
+	for _, s := range []string{"cpu", "nope=1", "cpu=high"} {
+		if _, err := ParseThreshold(s); err == nil {
+			t.Errorf("Invalid threshold %q was parsed", s)
+		}
+	}
+}
+
+func TestThresholdWatcher(t *testing.T) {
+	var breaches []Breach
+	w := NewThresholdWatcher([]Threshold{{Metric: "cpu", Value: 90}},
+		NotifierFunc(func(b Breach) error {
+			breaches = append(breaches, b)
+			return nil
+		}))
+	w.Cooldown = time.Minute
+	snapshot := func(cpu float64) []docker.StatsSnapshot {
+		return []docker.StatsSnapshot{{
+			Container: &types.Container{ID: "CID", Names: []string{"/web"}},
+			Stats:     &docker.Stats{CPUPercentage: cpu}}}
+	}
+	now := time.Now()
+	var tests = []struct {
+		cpu       float64
+		elapsed   time.Duration
+		notified  int
+		rationale string
+	}{
+		{50, 0, 0, "under the threshold"},
+		{95, time.Second, 1, "breach"},
+		{99, 2 * time.Second, 1, "breach in progress"},
+		{85, 3 * time.Second, 1, "under the threshold but not under the hysteresis margin"},
+		{95, 4 * time.Second, 1, "breach in progress"},
+		{50, 5 * time.Second, 1, "breach is over"},
+		{95, 6 * time.Second, 1, "new breach during the cooldown"},
+		{50, 7 * time.Second, 1, "breach is over"},
+		{95, 2 * time.Minute, 2, "new breach after the cooldown"},
+	}
+	for _, test := range tests {
+		w.Check(snapshot(test.cpu), now.Add(test.elapsed))
+		if len(breaches) != test.notified {
+			t.Errorf("%s: unexpected number of notifications. Expected: %d, got: %d", test.rationale, test.notified, len(breaches))
+		}
+	}
+	if b := breaches[0]; b.Container != "web" || b.Metric != "cpu" || b.Value != 95 || b.Threshold != 90 {
+		t.Errorf("Unexpected breach: %+v", b)
+	}
+}
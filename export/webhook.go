@@ -0,0 +1,64 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//DefaultWebhookRetries is the default number of times a notification is sent
+//again after failing
+const DefaultWebhookRetries = 3
+
+//Webhook is a Notifier that POSTs each breach, as JSON, to a URL. Failed
+//notifications are retried.
+type Webhook struct {
+	URL string
+	//Retries is the number of times a failed notification is sent again
+	Retries int
+	//RetryDelay is the time to wait before the first retry, it doubles on
+	//each retry
+	RetryDelay time.Duration
+	client     *http.Client
+}
+
+//NewWebhook creates a Webhook notifier that posts breaches to the given URL
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		URL:        url,
+		Retries:    DefaultWebhookRetries,
+		RetryDelay: time.Second,
+		client:     &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+//Notify posts the given breach to the webhook URL
+func (w *Webhook) Notify(b Breach) error {
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	delay := w.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err = w.post(payload)
+		if err == nil || attempt >= w.Retries {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (w *Webhook) post(payload []byte) error {
+	resp, err := w.client.Post(w.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhook %s returned %s", w.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookRetries(t *testing.T) {
+	requests := 0
+	var received Breach
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	webhook.RetryDelay = time.Millisecond
+	b := Breach{Container: "web", Metric: "cpu", Value: 95, Threshold: 90, Timestamp: time.Now().UTC()}
+	if err := webhook.Notify(b); err != nil {
+		t.Fatalf("Notification failed: %s", err)
+	}
+	if requests != 3 {
+		t.Errorf("Unexpected number of requests: %d", requests)
+	}
+	if received.Container != "web" || received.Value != 95 || !received.Timestamp.Equal(b.Timestamp) {
+		t.Errorf("Unexpected payload: %+v", received)
+	}
+
+	webhook.Retries = 0
+	requests = 0
+	if err := webhook.Notify(b); err == nil {
+		t.Error("A failed notification did not return an error")
+	}
+}
@@ -3,6 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"net/http"
@@ -10,12 +13,16 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/jessevdk/go-flags"
+	homedir "github.com/mitchellh/go-homedir"
 	"github.com/moncho/dry/app"
 	"github.com/moncho/dry/appui"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/export"
 	"github.com/moncho/dry/ui"
+	"github.com/moncho/dry/ui/termui"
 	"github.com/moncho/dry/version"
 	"github.com/nsf/termbox-go"
+	"golang.org/x/net/context"
 )
 
 const (
@@ -47,12 +54,50 @@ type dryOptions struct {
 	Description bool `short:"d" long:"description" description:"Dry description"`
 	MonitorMode bool `short:"m" long:"monitor" description:"Starts dry in monitor mode"`
 	// enable profiling
-	Profile bool `short:"p" long:"profile" description:"Enable profiling"`
-	Version bool `short:"v" long:"version" description:"Dry version"`
+	Profile bool   `short:"p" long:"profile" description:"Enable profiling"`
+	Version bool   `short:"v" long:"version" description:"Dry version"`
+	Theme   string `long:"theme" description:"Color theme: default16, black256, dark256, light256 or colorblind256"`
 	//Docker-related properties
-	DockerHost       string `short:"H" long:"docker_host" description:"Docker Host"`
-	DockerCertPath   string `short:"c" long:"docker_certpath" description:"Docker cert path"`
-	DockerTLSVerifiy string `short:"t" long:"docker_tls" description:"Docker TLS verify"`
+	DockerHost       string        `short:"H" long:"docker_host" description:"Docker Host"`
+	DockerCertPath   string        `short:"c" long:"docker_certpath" description:"Docker cert path"`
+	DockerTLSVerifiy string        `short:"t" long:"docker_tls" description:"Docker TLS verify"`
+	StatsStreamRate  int           `long:"stats_rate" description:"Maximum number of container stats streams opened per second"`
+	StatsOneShot     bool          `long:"stats_one_shot" description:"Request container stats one sample at a time instead of streaming them"`
+	StatsOneShotHost []string      `long:"stats_one_shot_host" description:"Docker Host whose container stats are requested one sample at a time, can be given more than once"`
+	WatchdogFailures int           `long:"watchdog_failures" description:"Number of container stats streams failing within --watchdog_window that makes the monitor open every stream again, a negative number disables it"`
+	WatchdogWindow   time.Duration `long:"watchdog_window" description:"Period over which failed container stats streams are counted, streams are not opened again more than once per period"`
+	MaxStreams       int           `long:"max_streams" description:"Maximum number of container stats streams opened on each Docker Host, the stats of the rest of running containers are requested in turns, so they are shown less often"`
+	StatsAdaptive    bool          `long:"stats_adaptive" description:"Request the stats of idle containers less often, implies --stats_one_shot"`
+	MonitorKeys      []string      `long:"monitor_key" description:"Binds a key to a monitor action (key=action), can be given more than once. Actions: scroll-up, scroll-down, sort, detail, filter, label-filter, search, show-all, expand-stopped, note, kill, restart, stop, pin, io-rates, id-length, copy-id, dismiss, diagnostics, reset-stats, mounts, group-by-image, group-by-pid-namespace, infra, raw-stats, watch, watch-previous, watch-next, compare, none"`
+	CPUScale         string        `long:"cpu_scale" description:"CPU usage shown as a full CPU gauge on the monitor: core, host or a percentage of a core (i.e. 200 for two cores)"`
+	OOMRiskMargin    int           `long:"oom_risk_margin" description:"Percentage of the memory limit that the memory used, not counting the page cache, of a container has to stay over to show it at risk of running out of memory" default:"95"`
+	OOMRiskPeriod    time.Duration `long:"oom_risk_period" description:"How long the memory used by a container has to stay over the OOM risk margin to show it at risk" default:"30s"`
+	IOPrecision      int           `long:"io_precision" description:"Number of significant digits of the network and block IO values of the monitor, shown on decimal units if set"`
+	ColumnSeparator  string        `long:"column_separator" description:"Character shown between the columns of the monitor, i.e. │"`
+	NameDisplay      string        `long:"name_display" description:"How container names are shown on the monitor: raw, service (the Compose service) or project (the Compose project and service)"`
+	MonitorScroll    string        `long:"monitor_scroll" description:"Where the selected container is kept when the monitor scrolls: paged, visible, top, center or bottom"`
+	Notes            string        `long:"notes" description:"File where the notes about containers are kept" default:"~/.dry/notes.json"`
+	Infra            string        `long:"infra" description:"How infrastructure containers, such as Kubernetes pause containers, are shown on the monitor: hide, group or show" default:"hide"`
+	InfraImages      []string      `long:"infra_image" description:"Image pattern, i.e. k8s.gcr.io/pause*, of infrastructure containers, can be given more than once. Replaces the default patterns, the default labels are still used unless --infra_label is given"`
+	InfraLabels      []string      `long:"infra_label" description:"Label key, or key=value, of infrastructure containers, can be given more than once. Replaces the default labels, the default image patterns are still used unless --infra_image is given"`
+	KeyHints         bool          `long:"key_hints" description:"Shows hints of the keys that can be used on the last line of the monitor"`
+	Columns          []string      `long:"columns" description:"Comma-separated optional columns shown on the monitor: cpu-avg, cpu-split, cpuset, fds, host, iops, limits, mem-host, note, nofile, ports, replica, restart, size-rw, started-at and updated"`
+	LabelColumns     []string      `long:"label_column" description:"Container label shown as a column on the monitor, can be given more than once"`
+	Smoothing        float64       `long:"smoothing" description:"Smoothing factor, between 0 and 1, of the CPU and memory gauges of the monitor, lower values smooth more" default:"1"`
+	RawValues        bool          `long:"raw_values" description:"Shows the last stats sample on the CPU and memory gauge labels instead of the smoothed value"`
+	HistorySize      int           `long:"history_size" description:"Number of stats samples kept on the history of each container of the monitor, the history is kept while dry runs"`
+	StartedAtLayout  string        `long:"started_at_layout" description:"Layout, in Go time format, of the time shown on the started-at column of the monitor, i.e. 15:04:05"`
+	IDLength         string        `long:"id_length" description:"How long the container IDs shown on the monitor are: short, medium or full"`
+	Summary          bool          `long:"summary" description:"Prints a line with the totals of the stats of the running containers and exits, see --summary_format"`
+	SummaryFormat    string        `long:"summary_format" description:"Format of the summary line, a Go template with the fields Containers, CPU, Memory, NetworkRx, NetworkTx, BlockRead, BlockWrite and Pids, and the functions bytes and percent. Shows the number of containers, CPU and memory by default"`
+	MonitorHosts     []string      `long:"monitor_host" description:"Docker Host whose containers are also shown on the monitor, can be given more than once"`
+	StatsDump        string        `long:"stats_dump" description:"Replays on the monitor the container stats saved on the given file (concatenated docker stats JSON), no Docker Host is used"`
+	//Stats exporters
+	StatsD       string `long:"statsd" description:"StatsD server (host:port) to send the stats of the monitored containers to"`
+	StatsDPrefix string `long:"statsd_prefix" description:"Prefix of the metrics sent to StatsD" default:"dry"`
+	//Alerts
+	Alerts       []string `long:"alert" description:"Alerts when a container metric goes over a threshold (metric=value, i.e. cpu=90), can be given more than once. Metrics: cpu, mem, mem_percent, net_rx, net_tx, block_read, block_write, pids"`
+	AlertWebhook string   `long:"alert_webhook" description:"URL to POST alerts to, as JSON"`
 }
 
 //-----------------------------------------------------------------------------
@@ -80,9 +125,63 @@ func newDockerEnv(opts dryOptions) *docker.Env {
 		dockerEnv.DockerTLSVerify = docker.GetBool(opts.DockerTLSVerifiy)
 		dockerEnv.DockerCertPath = opts.DockerCertPath
 	}
+	if opts.StatsStreamRate > 0 {
+		dockerEnv.StatsStreamRate = opts.StatsStreamRate
+	}
+	dockerEnv.StatsOneShot = statsOneShot(opts, dockerEnv.DockerHost)
+	if opts.StatsAdaptive {
+		dockerEnv.StatsOneShot = true
+		dockerEnv.StatsPollingPolicy = docker.NewAdaptivePolling
+	}
 	return dockerEnv
 }
 
+//statsOneShot returns true if the stats of the containers of the given Docker host
+//are requested one sample at a time
+func statsOneShot(opts dryOptions, host string) bool {
+	if opts.StatsOneShot {
+		return true
+	}
+	for _, h := range opts.StatsOneShotHost {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
+//newThresholdWatcher creates a watcher of the alert thresholds given, alerts are
+//shown by dry and, if given, sent to the alert webhook. Nil is returned if no
+//thresholds are given.
+func newThresholdWatcher(opts dryOptions, dry *app.Dry) *export.ThresholdWatcher {
+	var thresholds []export.Threshold
+	for _, alert := range opts.Alerts {
+		threshold, err := export.ParseThreshold(alert)
+		if err != nil {
+			log.WithField("error", err).Error("Alert will not be checked")
+			continue
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	if len(thresholds) == 0 {
+		return nil
+	}
+	watcher := export.NewThresholdWatcher(thresholds,
+		export.NotifierFunc(func(b export.Breach) error {
+			dry.Alert(b.String())
+			return nil
+		}))
+	if opts.AlertWebhook != "" {
+		//Webhooks retry failed requests, they must not delay checking the thresholds
+		watcher.AddNotifier(export.NewAsyncNotifier(export.NewWebhook(opts.AlertWebhook),
+			export.DefaultNotificationQueueSize,
+			func(b export.Breach, err error) {
+				log.WithField("error", err).Errorf("Alert %s was not sent to the webhook", b)
+			}))
+	}
+	return watcher
+}
+
 func showLoadingScreen(screen *ui.Screen, dockerEnv *docker.Env, stop <-chan struct{}) {
 	screen.Clear()
 	midscreen := screen.Width / 2
@@ -132,6 +231,52 @@ func showLoadingScreen(screen *ui.Screen, dockerEnv *docker.Env, stop <-chan str
 		}
 	}()
 }
+
+//printSummary prints a line with the totals of a stats sample of each running
+//container of the given Docker host, using the default format if none is given
+func printSummary(dockerEnv *docker.Env, format string) error {
+	if format == "" {
+		format = export.DefaultSummaryFormat
+	}
+	daemon, err := docker.ConnectToDaemon(dockerEnv)
+	if err != nil {
+		return err
+	}
+	snapshots, err := daemon.StatsRound()
+	if err != nil {
+		return err
+	}
+	return export.WriteSummary(os.Stdout, format, export.Summarize(snapshots))
+}
+
+//replayStatsDump shows the monitor replaying the given stats dump until the user quits
+func replayStatsDump(dump *docker.StatsDump) {
+	screen := ui.NewScreen(appui.DryTheme)
+	defer screen.Close()
+	screen.Clear()
+	screen.RenderLine(0, 0, fmt.Sprintf("<b><blue>Stats dump replay: </><yellow>%d</><blue> containers</></> <white>(q to quit)</>", len(dump.Containers)))
+	screen.Flush()
+	monitor := appui.NewStatsDumpMonitor(screen, dump, appui.MainScreenHeaderSize+2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor.RenderLoop(ctx)
+	events, done := ui.EventChannel()
+	defer close(done)
+	for event := range events {
+		if event.Type != termbox.EventKey {
+			continue
+		}
+		switch {
+		case event.Ch == 'q' || event.Key == termbox.KeyEsc || event.Key == termbox.KeyCtrlC:
+			return
+		case event.Key == termbox.KeyArrowUp:
+			monitor.ScrollUp()
+		case event.Key == termbox.KeyArrowDown:
+			monitor.ScrollDown()
+		}
+	}
+}
+
 func main() {
 	running := false
 	defer func() {
@@ -169,8 +314,15 @@ func main() {
 		fmt.Printf("dry version %s, build %s\n", version.VERSION, version.GITCOMMIT)
 		return
 	}
-	log.Info("Launching dry")
 	dockerEnv := newDockerEnv(opts)
+	if opts.Summary {
+		if err := printSummary(dockerEnv, opts.SummaryFormat); err != nil {
+			log.WithField("error", err).Error("Cannot print the summary")
+			os.Exit(1)
+		}
+		return
+	}
+	log.Info("Launching dry")
 
 	// Start profiling (if required)
 	if opts.Profile {
@@ -178,6 +330,111 @@ func main() {
 			log.Info(http.ListenAndServe("localhost:6060", nil))
 		}()
 	}
+	for _, binding := range opts.MonitorKeys {
+		kv := strings.SplitN(binding, "=", 2)
+		if len(kv) != 2 {
+			log.Errorf("Invalid monitor key binding %s, expected key=action", binding)
+			return
+		}
+		if err := app.BindMonitorKey(kv[0], kv[1]); err != nil {
+			log.Error(err.Error())
+			return
+		}
+	}
+	if path, errP := homedir.Expand(opts.Notes); errP == nil {
+		notes, errN := docker.LoadContainerNotes(path)
+		if errN != nil {
+			log.WithField("error", errN).Error("Container notes will not be shown")
+		} else {
+			appui.DefaultStatsRowOptions.Notes = notes
+		}
+	}
+	if opts.MonitorScroll != "" {
+		policy, err := termui.ParseScrollPolicy(opts.MonitorScroll)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		appui.DefaultStatsRowOptions.ScrollPolicy = policy
+	}
+	if opts.Infra != "" {
+		infra, err := appui.ParseInfraDisplay(opts.Infra)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		appui.DefaultStatsRowOptions.InfraDisplay = infra
+	}
+	if len(opts.InfraImages) > 0 || len(opts.InfraLabels) > 0 {
+		appui.DefaultStatsRowOptions.InfraClassifier = docker.NewInfraClassifier(opts.InfraImages, opts.InfraLabels)
+	}
+	appui.DefaultStatsRowOptions.ShowKeyHints = opts.KeyHints
+	for _, columns := range opts.Columns {
+		if err := appui.DefaultStatsRowOptions.ShowColumns(strings.Split(columns, ",")...); err != nil {
+			log.Error(err.Error())
+			return
+		}
+	}
+	dockerEnv.StatsOpenFDs = appui.DefaultStatsRowOptions.ShowOpenFDs
+	appui.DefaultStatsRowOptions.Labels = opts.LabelColumns
+	appui.DefaultStatsRowOptions.CPUSmoothing = opts.Smoothing
+	appui.DefaultStatsRowOptions.MemorySmoothing = opts.Smoothing
+	appui.DefaultStatsRowOptions.ShowRawValues = opts.RawValues
+	appui.DefaultStatsRowOptions.StartedAtLayout = opts.StartedAtLayout
+	appui.DefaultStatsRowOptions.HistorySize = opts.HistorySize
+	appui.DefaultStatsRowOptions.MaxStreams = opts.MaxStreams
+	if opts.WatchdogFailures != 0 {
+		appui.DefaultStatsRowOptions.Watchdog.MaxFailures = opts.WatchdogFailures
+	}
+	if opts.WatchdogWindow > 0 {
+		appui.DefaultStatsRowOptions.Watchdog.Window = opts.WatchdogWindow
+	}
+	if opts.IDLength != "" {
+		display, err := appui.ParseIDDisplay(opts.IDLength)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		appui.DefaultStatsRowOptions.IDDisplay = display
+	}
+	if opts.NameDisplay != "" {
+		display, err := docker.ParseNameDisplay(opts.NameDisplay)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		appui.DefaultStatsRowOptions.NameDisplay = display
+	}
+	if opts.CPUScale != "" {
+		if err := appui.DefaultStatsRowOptions.SetCPUGaugeScale(opts.CPUScale); err != nil {
+			log.Error(err.Error())
+			return
+		}
+	}
+	appui.DefaultStatsRowOptions.OOMRiskMargin = float64(opts.OOMRiskMargin)
+	appui.DefaultStatsRowOptions.OOMRiskPeriod = opts.OOMRiskPeriod
+	if opts.ColumnSeparator != "" {
+		appui.DefaultStatsRowOptions.ColumnSeparator = []rune(opts.ColumnSeparator)[0]
+	}
+	if opts.IOPrecision > 0 {
+		appui.DefaultStatsRowOptions.IOByteFormat = appui.HumanSizeWithPrecision(opts.IOPrecision)
+	}
+	if opts.Theme != "" {
+		if err := appui.SetActiveTheme(opts.Theme); err != nil {
+			log.Error(err.Error())
+			return
+		}
+	}
+	if opts.StatsDump != "" {
+		dump, errD := docker.ReadStatsDumpFile(opts.StatsDump)
+		if errD != nil {
+			log.WithField("error", errD).Error("Cannot read the stats dump")
+			return
+		}
+		running = true
+		replayStatsDump(dump)
+		return
+	}
 	screen := ui.NewScreen(appui.DryTheme)
 	running = true
 
@@ -189,11 +446,49 @@ func main() {
 	dry, err := newApp(screen, dockerEnv)
 	//dry has loaded, loading screen should not be shown
 	close(stopLoadScreen)
+	if err == nil {
+		for _, host := range opts.MonitorHosts {
+			env := *dockerEnv
+			env.DockerHost = host
+			env.StatsOneShot = env.StatsPollingPolicy != nil || statsOneShot(opts, host)
+			if errH := dry.AddMonitorHost(&env); errH != nil {
+				log.WithField("error", errH).Errorf("Containers of %s will not be monitored", host)
+			}
+		}
+	}
 	if opts.MonitorMode {
 		dry.ShowMonitor()
 	}
 	if err == nil {
+		statsd, errS := export.NewStatsD(opts.StatsD, opts.StatsDPrefix)
+		if errS != nil {
+			log.WithField("error", errS).Error("Stats will not be sent to StatsD")
+		}
+		//TLS certificates are reloaded on SIGHUP, so they can be rotated
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
+		go func() {
+			for range reload {
+				dry.ReloadTLS()
+			}
+		}()
+		ctx, cancel := context.WithCancel(context.Background())
+		//stats are sent and checked whatever the view shown, not only while
+		//the monitor is shown
+		watcher := newThresholdWatcher(opts, dry)
+		var collectors docker.Collectors
+		if statsd != nil || watcher != nil {
+			collectors = dry.CollectStats()
+		}
+		if statsd != nil {
+			go statsd.Run(ctx, collectors, docker.StatsInterval)
+		}
+		if watcher != nil {
+			go watcher.Run(ctx, collectors, docker.StatsInterval)
+		}
 		app.RenderLoop(dry, screen)
+		cancel()
+		statsd.Close()
 		dry.Close()
 		screen.Close()
 	} else {
@@ -13,6 +13,21 @@ import (
 type ContainerDaemonMock struct {
 }
 
+//ContainerLimits mock
+func (_m *ContainerDaemonMock) ContainerLimits(id string) (drydocker.ContainerLimits, error) {
+	return drydocker.ContainerLimits{}, nil
+}
+
+//ContainerSizes mock
+func (_m *ContainerDaemonMock) ContainerSizes() (map[string]int64, error) {
+	return nil, nil
+}
+
+//ContainerTimeline mock
+func (_m *ContainerDaemonMock) ContainerTimeline(c *types.Container) (*drydocker.ContainerTimeline, chan<- struct{}, error) {
+	return drydocker.NewContainerTimeline(), make(chan struct{}), nil
+}
+
 //ContainerStore mock
 func (_m *ContainerDaemonMock) ContainerStore() *drydocker.ContainerStore {
 	return nil
@@ -44,6 +59,16 @@ func (_m *ContainerDaemonMock) History(id string) ([]types.ImageHistory, error)
 	return nil, nil
 }
 
+//HostCPUs mock
+func (_m *ContainerDaemonMock) HostCPUs() int {
+	return 1
+}
+
+//HostMemory mock
+func (_m *ContainerDaemonMock) HostMemory() int64 {
+	return 1024 * 1024 * 1024
+}
+
 //ImageAt mock
 func (_m *ContainerDaemonMock) ImageAt(pos int) (*types.ImageSummary, error) {
 	return nil, nil
@@ -160,6 +185,11 @@ func (_m *ContainerDaemonMock) NetworkInspect(id string) (types.NetworkResource,
 	return types.NetworkResource{}, nil
 }
 
+//Ping mock
+func (_m *ContainerDaemonMock) Ping() error {
+	return nil
+}
+
 // Ok mocks OK
 func (_m *ContainerDaemonMock) Ok() (bool, error) {
 
@@ -176,6 +206,11 @@ func (_m *ContainerDaemonMock) Prune() (*drydocker.PruneReport, error) {
 	return nil, nil
 }
 
+//ReloadTLS mock
+func (_m *ContainerDaemonMock) ReloadTLS() error {
+	return nil
+}
+
 // RestartContainer provides a mock function with given fields: id
 func (_m *ContainerDaemonMock) RestartContainer(id string) error {
 
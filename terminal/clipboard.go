@@ -0,0 +1,41 @@
+package terminal
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+//clipboardCommands are the commands, by OS, that can write to the system
+//clipboard, the first one found on the PATH is used.
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux": {
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	},
+}
+
+//ErrNoClipboard is returned if there is no way to write to the system clipboard
+var ErrNoClipboard = errors.New("No clipboard command found")
+
+//CopyToClipboard writes the given text to the system clipboard
+func CopyToClipboard(text string) error {
+	return copyToClipboard(text, clipboardCommands[runtime.GOOS])
+}
+
+func copyToClipboard(text string, commands [][]string) error {
+	for _, command := range commands {
+		path, err := exec.LookPath(command[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, command[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return ErrNoClipboard
+}
@@ -0,0 +1,17 @@
+package terminal
+
+import "testing"
+
+func TestCopyToClipboardWithNoCommand(t *testing.T) {
+	err := copyToClipboard("text", [][]string{{"surely-not-a-clipboard-command"}})
+	if err != ErrNoClipboard {
+		t.Errorf("Unexpected error when no clipboard command is found: %v", err)
+	}
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	//cat reads the text from stdin, as clipboard commands do
+	if err := copyToClipboard("text", [][]string{{"cat"}}); err != nil {
+		t.Errorf("Unexpected error copying to the clipboard: %s", err.Error())
+	}
+}
@@ -0,0 +1,42 @@
+package ui
+
+//ansiColors are the RGB values of the first 16 colors of the palette
+var ansiColors = [16][3]int{
+	{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+	{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+	{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+//cubeLevels are the values of each RGB component of the 6x6x6 color cube of the palette
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+//rgb returns the RGB values of the given palette color, false is returned
+//if the color is not on the 256 colors palette.
+func rgb(c Color) (int, int, int, bool) {
+	switch {
+	case c < 16:
+		return ansiColors[c][0], ansiColors[c][1], ansiColors[c][2], true
+	case c < 232:
+		n := int(c) - 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6], true
+	case c < 256:
+		grey := 8 + 10*(int(c)-232)
+		return grey, grey, grey, true
+	}
+	return 0, 0, 0, false
+}
+
+//ContrastColor returns the color, either black or white, of text that is
+//legible on the given background color. Colors are palette indexes, not the
+//termbox attributes that render them.
+func ContrastColor(bg Color) Color {
+	r, g, b, ok := rgb(bg)
+	if !ok {
+		return Color231
+	}
+	if luminance := 299*r + 587*g + 114*b; luminance > 128*1000 {
+		return Color16
+	}
+	return Color231
+}
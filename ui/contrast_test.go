@@ -0,0 +1,25 @@
+package ui
+
+import "testing"
+
+func TestContrastColor(t *testing.T) {
+	var tests = []struct {
+		bg       Color
+		expected Color
+	}{
+		{ColorBlack, Color231},
+		{ColorYellow, Color16},
+		{ColorGreen, Color231},
+		{ColorRed, Color231},
+		{ColorWhite, Color16},
+		{Color214, Color16},
+		{Color23, Color231},
+		{Color232, Color231},
+		{Color255, Color16},
+	}
+	for _, test := range tests {
+		if c := ContrastColor(test.bg); c != test.expected {
+			t.Errorf("Unexpected contrast color for %d. Expected: %d, got: %d", test.bg, test.expected, c)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/nsf/termbox-go"
+)
+
+//RenderTarget is where a Screen renders its content, the terminal unless a
+//screen is created with NewScreenWithTarget.
+type RenderTarget interface {
+	Size() (int, int)
+	SetCell(x, y int, ch rune, fg, bg termbox.Attribute)
+	Clear(fg, bg termbox.Attribute)
+	Flush()
+	Sync()
+}
+
+//termboxTarget renders on the terminal, using termbox
+type termboxTarget struct{}
+
+func (termboxTarget) Size() (int, int) {
+	return termbox.Size()
+}
+
+func (termboxTarget) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	termbox.SetCell(x, y, ch, fg, bg)
+}
+
+func (termboxTarget) Clear(fg, bg termbox.Attribute) {
+	termbox.Clear(fg, bg)
+}
+
+func (termboxTarget) Flush() {
+	termbox.Flush()
+}
+
+func (termboxTarget) Sync() {
+	termbox.Sync()
+}
+
+//Frame is a RenderTarget of fixed dimensions kept in memory, what is rendered
+//on a screen can be captured with it, i.e. to compare a view with a golden file.
+type Frame struct {
+	width, height int
+	cells         []termbox.Cell
+	sync.RWMutex
+}
+
+//NewFrame creates a blank frame with the given dimensions
+func NewFrame(width, height int) *Frame {
+	f := &Frame{width: width, height: height, cells: make([]termbox.Cell, width*height)}
+	f.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	return f
+}
+
+//Size returns the dimensions of the frame
+func (f *Frame) Size() (int, int) {
+	return f.width, f.height
+}
+
+//SetCell sets the cell at the given position, cells outside the frame are ignored
+func (f *Frame) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	if x < 0 || x >= f.width || y < 0 || y >= f.height {
+		return
+	}
+	f.Lock()
+	defer f.Unlock()
+	f.cells[y*f.width+x] = termbox.Cell{Ch: ch, Fg: fg, Bg: bg}
+}
+
+//Cell returns the cell at the given position
+func (f *Frame) Cell(x, y int) termbox.Cell {
+	if x < 0 || x >= f.width || y < 0 || y >= f.height {
+		return termbox.Cell{}
+	}
+	f.RLock()
+	defer f.RUnlock()
+	return f.cells[y*f.width+x]
+}
+
+//Clear fills the frame with blank cells of the given colors
+func (f *Frame) Clear(fg, bg termbox.Attribute) {
+	f.Lock()
+	defer f.Unlock()
+	for i := range f.cells {
+		f.cells[i] = termbox.Cell{Ch: ' ', Fg: fg, Bg: bg}
+	}
+}
+
+//Flush does nothing, the frame always has what was rendered
+func (f *Frame) Flush() {}
+
+//Sync does nothing, the frame always has what was rendered
+func (f *Frame) Sync() {}
+
+//String returns the text of the frame, a line per row with no trailing spaces
+func (f *Frame) String() string {
+	f.RLock()
+	defer f.RUnlock()
+	var buf bytes.Buffer
+	line := make([]rune, f.width)
+	for y := 0; y < f.height; y++ {
+		for x := 0; x < f.width; x++ {
+			line[x] = f.cells[y*f.width+x].Ch
+			if line[x] == 0 {
+				line[x] = ' '
+			}
+		}
+		buf.WriteString(strings.TrimRight(string(line), " "))
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+//WriteTo writes the text of the frame to the given writer
+func (f *Frame) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, f.String())
+	return int64(n), err
+}
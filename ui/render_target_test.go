@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gizak/termui"
+	"github.com/nsf/termbox-go"
+)
+
+func TestScreenRendersOnFrame(t *testing.T) {
+	frame := NewFrame(12, 4)
+	screen := NewScreenWithTarget(&ColorTheme{Fg: 7, Bg: 0}, frame)
+
+	if screen.Width != 12 || screen.Height != 4 {
+		t.Fatalf("Screen dimensions do not match the frame. Got: %dx%d", screen.Width, screen.Height)
+	}
+
+	screen.Clear()
+	screen.Render(0, "<b>dry</b>")
+	screen.RenderLine(2, 1, "test")
+	screen.RenderLineWithBackGround(1, 2, "bg", Color(termbox.ColorRed))
+	p := termui.NewPar("par")
+	p.Border = false
+	p.X, p.Y, p.Width, p.Height = 4, 3, 3, 1
+	screen.RenderBufferer(p)
+	screen.Flush()
+
+	expected := "dry\n  test\n bg\n    par\n"
+	if frame.String() != expected {
+		t.Errorf("Unexpected frame content. Got:\n%q\nexpected:\n%q", frame.String(), expected)
+	}
+	if c := frame.Cell(11, 2); c.Bg != termbox.ColorRed {
+		t.Errorf("Line background was not filled until the end of the frame. Got: %v", c.Bg)
+	}
+
+	var buf bytes.Buffer
+	if _, err := frame.WriteTo(&buf); err != nil {
+		t.Errorf("Unexpected error writing the frame: %s", err)
+	}
+	if buf.String() != expected {
+		t.Errorf("Unexpected frame content written. Got:\n%q", buf.String())
+	}
+}
+
+func TestFrameIgnoresCellsOutside(t *testing.T) {
+	frame := NewFrame(2, 1)
+	frame.SetCell(-1, 0, 'a', 0, 0)
+	frame.SetCell(2, 0, 'b', 0, 0)
+	frame.SetCell(0, 1, 'c', 0, 0)
+	frame.SetCell(1, 0, 'd', 0, 0)
+
+	if frame.String() != " d\n" {
+		t.Errorf("Unexpected frame content. Got: %q", frame.String())
+	}
+}
@@ -18,7 +18,8 @@ type Screen struct {
 	pausedAt *time.Time
 	Cursor   *Cursor // Pointer to cursor (gets created by screen).
 	sync.RWMutex
-	theme *ColorTheme
+	theme  *ColorTheme
+	target RenderTarget
 }
 
 //Cursor represents the cursor position on the screen
@@ -36,23 +37,32 @@ func NewScreen(theme *ColorTheme) *Screen {
 		panic(err)
 	}
 	termbox.SetOutputMode(termbox.Output256)
+	return NewScreenWithTarget(theme, termboxTarget{})
+}
+
+//NewScreenWithTarget creates a screen that renders on the given target instead
+//of the terminal, termbox is not initialized.
+func NewScreenWithTarget(theme *ColorTheme, target RenderTarget) *Screen {
 	screen := &Screen{}
 	screen.markup = NewMarkup(theme)
 	screen.Cursor = &Cursor{line: 0}
 	screen.theme = theme
+	screen.target = target
 	return screen.Resize()
 }
 
 // Close gets called upon program termination to close the Termbox.
 func (screen *Screen) Close() *Screen {
-	termbox.Close()
+	if _, ok := screen.target.(termboxTarget); ok {
+		termbox.Close()
+	}
 	return screen
 }
 
 // Resize gets called when the screen is being resized. It recalculates screen
 // dimensions and requests to clear the screen on next update.
 func (screen *Screen) Resize() *Screen {
-	screen.Width, screen.Height = termbox.Size()
+	screen.Width, screen.Height = screen.target.Size()
 	return screen
 }
 
@@ -60,7 +70,7 @@ func (screen *Screen) Resize() *Screen {
 func (screen *Screen) Clear() *Screen {
 	screen.Lock()
 	defer screen.Unlock()
-	termbox.Clear(termbox.Attribute(screen.theme.Fg), termbox.Attribute(screen.theme.Bg))
+	screen.target.Clear(termbox.Attribute(screen.theme.Fg), termbox.Attribute(screen.theme.Bg))
 	return screen
 }
 
@@ -75,7 +85,7 @@ func (screen *Screen) ClearAndFlush() *Screen {
 func (screen *Screen) Sync() *Screen {
 	screen.Lock()
 	defer screen.Unlock()
-	termbox.Sync()
+	screen.target.Sync()
 	return screen
 }
 
@@ -85,7 +95,7 @@ func (screen *Screen) ClearLine(x int, y int) *Screen {
 	screen.RLock()
 	defer screen.RUnlock()
 	for i := x; i < screen.Width; i++ {
-		termbox.SetCell(i, y, ' ', termbox.Attribute(screen.theme.Fg), termbox.Attribute(screen.theme.Bg))
+		screen.target.SetCell(i, y, ' ', termbox.Attribute(screen.theme.Fg), termbox.Attribute(screen.theme.Bg))
 	}
 	screen.Flush()
 
@@ -104,7 +114,7 @@ func (screen *Screen) ColorTheme(theme *ColorTheme) *Screen {
 func (screen *Screen) Flush() *Screen {
 	screen.Lock()
 	defer screen.Unlock()
-	termbox.Flush()
+	screen.target.Flush()
 	return screen
 }
 
@@ -126,7 +136,7 @@ func (screen *Screen) RenderBufferer(bs ...termui.Bufferer) {
 		// set cels in buf
 		for p, c := range buf.CellMap {
 			if p.In(buf.Area) {
-				termbox.SetCell(p.X, p.Y, c.Ch, toTmAttr(c.Fg), toTmAttr(c.Bg))
+				screen.target.SetCell(p.X, p.Y, c.Ch, toTmAttr(c.Fg), toTmAttr(c.Bg))
 			}
 		}
 	}
@@ -153,7 +163,7 @@ func (screen *Screen) RenderLine(x int, y int, str string) {
 			} else {
 				start = screen.Width - len(token) + i
 			}
-			termbox.SetCell(start, y, char, screen.markup.Foreground, screen.markup.Background)
+			screen.target.SetCell(start, y, char, screen.markup.Foreground, screen.markup.Background)
 		}
 	}
 }
@@ -165,7 +175,7 @@ func (screen *Screen) RenderLineWithBackGround(x int, y int, str string, bgColor
 	defer screen.Unlock()
 	start, column := 0, 0
 	if x > 0 {
-		fill(0, y, x, y, termbox.Cell{Ch: ' ', Bg: termbox.Attribute(bgColor)})
+		screen.fill(0, y, x, termbox.Cell{Ch: ' ', Bg: termbox.Attribute(bgColor)})
 	}
 	for _, token := range Tokenize(str, SupportedTags) {
 		// First check if it's a tag. Tags are eaten up and not displayed.
@@ -181,10 +191,10 @@ func (screen *Screen) RenderLineWithBackGround(x int, y int, str string, bgColor
 			} else {
 				start = screen.Width - len(token) + i
 			}
-			termbox.SetCell(start, y, char, screen.markup.Foreground, termbox.Attribute(bgColor))
+			screen.target.SetCell(start, y, char, screen.markup.Foreground, termbox.Attribute(bgColor))
 		}
 	}
-	fill(start+1, y, screen.Width, y, termbox.Cell{Ch: ' ', Bg: termbox.Attribute(bgColor)})
+	screen.fill(start+1, y, screen.Width, termbox.Cell{Ch: ' ', Bg: termbox.Attribute(bgColor)})
 }
 
 //Render renders the given content starting from the given row
@@ -238,6 +248,14 @@ func (cursor *Cursor) ScrollTo(pos int) {
 
 }
 
+//fill fills the given line of the screen with the given cell, starting at x,
+//for the given width
+func (screen *Screen) fill(x, y, w int, cell termbox.Cell) {
+	for lx := 0; lx < w; lx++ {
+		screen.target.SetCell(x+lx, y, cell.Ch, cell.Fg, cell.Bg)
+	}
+}
+
 func toTmAttr(x termui.Attribute) termbox.Attribute {
 	return termbox.Attribute(x)
 }
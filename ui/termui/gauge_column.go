@@ -9,11 +9,14 @@ import (
 //borderless, has a height of 1 and its label its left-aligned.
 type GaugeColumn struct {
 	termui.Gauge
+	theme *ui.ColorTheme
 }
 
-//NewThemedGaugeColumn creates a new GaugeColumn using the given theme
+//NewThemedGaugeColumn creates a new GaugeColumn using the given theme.
+//The theme colors are read on each render, so changes on the theme are shown on the column.
 func NewThemedGaugeColumn(theme *ui.ColorTheme) *GaugeColumn {
 	c := NewGaugeColumn()
+	c.theme = theme
 	c.Bg = termui.Attribute(theme.Bg)
 	return c
 }
@@ -26,7 +29,15 @@ func NewGaugeColumn() *GaugeColumn {
 	g.Percent = 0
 	g.PaddingBottom = 0
 
-	return &GaugeColumn{*g}
+	return &GaugeColumn{Gauge: *g}
+}
+
+//Buffer returns the content of this column as a termui.Buffer
+func (w *GaugeColumn) Buffer() termui.Buffer {
+	if w.theme != nil {
+		w.Bg = termui.Attribute(w.theme.Bg)
+	}
+	return w.Gauge.Buffer()
 }
 
 //Reset resets this GaugeColumn
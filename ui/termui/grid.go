@@ -1,14 +1,85 @@
 package termui
 
-import ui "github.com/gizak/termui"
+import (
+	"fmt"
+	"image"
+
+	ui "github.com/gizak/termui"
+	dryui "github.com/moncho/dry/ui"
+)
+
+//ScrollPolicy decides which rows of a Grid are shown so that the row at the
+//Offset position, the selected row, is visible
+type ScrollPolicy int
+
+const (
+	//ScrollPaged shows the rows from the first one, unless the selected row does
+	//not fit, then the selected row is the last one shown
+	ScrollPaged ScrollPolicy = iota
+	//ScrollKeepVisible keeps the rows shown until the selected row would not be
+	//visible, then scrolls just enough to show it
+	ScrollKeepVisible
+	//ScrollAnchorTop shows the selected row as the first one
+	ScrollAnchorTop
+	//ScrollAnchorCenter shows the selected row on the center
+	ScrollAnchorCenter
+	//ScrollAnchorBottom shows the selected row as the last one
+	ScrollAnchorBottom
+)
+
+//scrollPolicies are the scroll policies by name
+var scrollPolicies = map[string]ScrollPolicy{
+	"paged":   ScrollPaged,
+	"visible": ScrollKeepVisible,
+	"top":     ScrollAnchorTop,
+	"center":  ScrollAnchorCenter,
+	"bottom":  ScrollAnchorBottom,
+}
+
+//ParseScrollPolicy returns the scroll policy with the given name: paged, visible,
+//top, center or bottom
+func ParseScrollPolicy(name string) (ScrollPolicy, error) {
+	if policy, ok := scrollPolicies[name]; ok {
+		return policy, nil
+	}
+	return ScrollPaged, fmt.Errorf("Unknown scroll policy: %s", name)
+}
+
+//minRowLines are the lines kept for rows, the hints, the footer and then the header
+//are not shown if the grid is too short to show them and this many lines of rows
+const minRowLines = 1
+
+//TruncatedMark is shown on the bottom right corner of a grid too short to show
+//its header, its footer or its hints
+const TruncatedMark = '…'
 
 //Grid is a custom termui.Grid which expects rows as GridBufferer(s).
 type Grid struct {
 	ui.GridBufferer
+	header        ui.GridBufferer
+	footer        ui.GridBufferer
+	hints         ui.GridBufferer
 	rows          []ui.GridBufferer
 	X, Y          int
 	Height, Width int
 	Offset        int
+	//EmptyMessage is shown, centered, when the grid has no rows
+	EmptyMessage string
+	//Theme is the color theme used to render the EmptyMessage
+	Theme *dryui.ColorTheme
+	//AlternateRows tells rows implementing Alternating whether they are
+	//on an odd position of the page, so alternate rows can be shaded
+	AlternateRows bool
+	//MaxRows, if set, is the maximum number of rows shown, the rest of rows are
+	//not shown, not even by paging, a row created with Remainder is shown instead.
+	MaxRows int
+	//Remainder creates the row that summarizes the rows not shown because of MaxRows
+	Remainder func(hidden []ui.GridBufferer) ui.GridBufferer
+	remainder ui.GridBufferer
+	//ScrollPolicy decides the rows shown, the selected row is always visible
+	ScrollPolicy ScrollPolicy
+	//first is the position of the first row shown on the last page
+	first int
 }
 
 //NewGrid creates a new Grid
@@ -27,16 +98,44 @@ func (g *Grid) Align() {
 	if g.Offset >= len(g.rows) {
 		g.Offset = 0
 	}
-	for _, r := range g.pageRows() {
+	header, footer, hints := g.shownChrome()
+	if header != nil {
+		header.SetY(y)
+		header.SetX(g.X)
+		y += header.GetHeight()
+		header.SetWidth(g.Width)
+	}
+	//the page is decided here, rendering must not change the grid
+	rows, start, end := g.page()
+	g.first = start
+	for i, r := range rows[start:end] {
+		if a, ok := r.(Alternating); ok {
+			a.SetOdd(g.AlternateRows && i%2 == 1)
+		}
 		r.SetY(y)
 		r.SetX(g.X)
 		y += r.GetHeight()
 		r.SetWidth(g.Width)
 	}
+	bottom := g.Y + g.Height
+	if hints != nil {
+		bottom -= hints.GetHeight()
+		hints.SetY(bottom)
+		hints.SetX(g.X)
+		hints.SetWidth(g.Width)
+	}
+	if footer != nil {
+		footer.SetY(bottom - footer.GetHeight())
+		footer.SetX(g.X)
+		footer.SetWidth(g.Width)
+	}
 }
 
 //Clear this Grid content
-func (g *Grid) Clear() { g.rows = []ui.GridBufferer{} }
+func (g *Grid) Clear() {
+	g.rows = []ui.GridBufferer{}
+	g.remainder = nil
+}
 
 //GetHeight return this Grid height
 func (g *Grid) GetHeight() int {
@@ -54,39 +153,256 @@ func (g *Grid) SetWidth(w int) { g.Width = w }
 
 //Buffer returns the content of this Grid as a Buffer
 func (g *Grid) Buffer() ui.Buffer {
-	buf := ui.NewBuffer()
-	for _, r := range g.pageRows() {
-		buf.Merge(r.Buffer())
+	buf := NewSizedBuffer(g.Width, g.Height)
+	header, footer, hints := g.shownChrome()
+	if header != nil {
+		buf.Merge(header.Buffer())
+	}
+	if footer != nil {
+		buf.Merge(footer.Buffer())
+	}
+	if hints != nil {
+		buf.Merge(hints.Buffer())
+	}
+	if len(g.rows) == 0 {
+		if g.EmptyMessage != "" {
+			buf.Merge(g.emptyMessage().Buffer())
+		}
+	} else {
+		bottom := g.rowsBottom()
+		for _, r := range g.pageRows() {
+			mergeAbove(&buf, r.Buffer(), bottom)
+		}
+	}
+	if header != g.header || footer != g.footer || hints != g.hints {
+		g.markTruncated(&buf)
 	}
 	return buf
 }
 
+//markTruncated shows the TruncatedMark on the given buffer
+func (g *Grid) markTruncated(buf *ui.Buffer) {
+	if g.Width <= 0 || g.Height <= 0 {
+		return
+	}
+	cell := ui.Cell{Ch: TruncatedMark, Fg: ui.ColorDefault, Bg: ui.ColorDefault}
+	if g.Theme != nil {
+		cell.Fg, cell.Bg = ui.Attribute(g.Theme.Fg), ui.Attribute(g.Theme.Bg)
+	}
+	buf.Set(g.X+g.Width-1, g.Y+g.Height-1, cell)
+}
+
+//shownChrome returns the header, the footer and the hints that fit on the grid, if
+//the grid is too short to show them and minRowLines of rows the hints are dropped
+//first, then the footer and then the header. Dropped ones are returned as nil.
+func (g *Grid) shownChrome() (header, footer, hints ui.GridBufferer) {
+	header, footer, hints = g.header, g.footer, g.hints
+	lines := g.GetHeight() - minRowLines
+	if heightOf(header)+heightOf(footer)+heightOf(hints) > lines {
+		hints = nil
+	}
+	if heightOf(header)+heightOf(footer) > lines {
+		footer = nil
+	}
+	if heightOf(header) > lines {
+		header = nil
+	}
+	return header, footer, hints
+}
+
+//heightOf returns the height of the given GridBufferer, 0 if nil
+func heightOf(b ui.GridBufferer) int {
+	if b == nil {
+		return 0
+	}
+	return b.GetHeight()
+}
+
+//NewSizedBuffer creates a Buffer with room for the cells of an area of the given size,
+//so that the Buffer does not grow when the cells are set.
+func NewSizedBuffer(width, height int) ui.Buffer {
+	cells := width * height
+	if cells < 0 {
+		cells = 0
+	}
+	return ui.Buffer{CellMap: make(map[image.Point]ui.Cell, cells)}
+}
+
+//mergeAbove merges into dst the cells of src above the given line
+func mergeAbove(dst *ui.Buffer, src ui.Buffer, bottom int) {
+	for p, c := range src.CellMap {
+		if p.Y < bottom {
+			dst.CellMap[p] = c
+		}
+	}
+	area := src.Area
+	if area.Max.Y > bottom {
+		area.Max.Y = bottom
+	}
+	if !area.Empty() {
+		dst.SetArea(dst.Area.Union(area))
+	}
+}
+
 //AddRows adds the given GridBufferer(s) as rows of this Grid
 func (g *Grid) AddRows(rows ...ui.GridBufferer) {
 	for _, r := range rows {
 		g.rows = append(g.rows, r)
 	}
+	g.remainder = nil
 }
 
-func (g *Grid) pageRows() []ui.GridBufferer {
-	rows := g.rows
-	availableLines := g.GetHeight() - 1
+//ShownRowCount returns the number of rows of this Grid that can be shown, rows
+//over MaxRows are not counted.
+func (g *Grid) ShownRowCount() int {
+	if g.MaxRows > 0 && len(g.rows) > g.MaxRows {
+		return g.MaxRows
+	}
+	return len(g.rows)
+}
 
-	if len(rows) < availableLines {
+//shownRows returns the rows that can be shown, if there are more rows than
+//MaxRows the row summarizing the rest of rows is the last one.
+func (g *Grid) shownRows() []ui.GridBufferer {
+	if g.MaxRows <= 0 || len(g.rows) <= g.MaxRows {
+		return g.rows
+	}
+	rows := g.rows[:g.MaxRows:g.MaxRows]
+	if g.Remainder == nil {
 		return rows
 	}
+	if g.remainder == nil {
+		g.remainder = g.Remainder(g.rows[g.MaxRows:])
+	}
+	return append(rows, g.remainder)
+}
+
+//RowCount returns the number of rows of this Grid, the header is not counted
+func (g *Grid) RowCount() int {
+	return len(g.rows)
+}
 
-	start, end := 0, 0
-	cursorPos := g.Offset
-	if cursorPos > availableLines {
-		start = cursorPos + 1 - availableLines
-		end = cursorPos + 1
-	} else if cursorPos == availableLines {
-		start = 1
-		end = availableLines + 1
+//SetHeader sets the header of this Grid, the header is always
+//rendered on the first line of the grid.
+func (g *Grid) SetHeader(header ui.GridBufferer) {
+	g.header = header
+}
+
+//SetFooter sets the footer of this Grid, the footer is always
+//rendered on the last line(s) of the grid, above the hints.
+func (g *Grid) SetFooter(footer ui.GridBufferer) {
+	g.footer = footer
+}
+
+//SetHints sets the hints of this Grid, i.e. KeyHints, they are rendered
+//on the last line(s) of the grid.
+func (g *Grid) SetHints(hints ui.GridBufferer) {
+	g.hints = hints
+}
+
+//emptyMessage returns the EmptyMessage as a ParColumn centered on
+//the grid
+func (g *Grid) emptyMessage() *ParColumn {
+	var p *ParColumn
+	if g.Theme != nil {
+		p = NewThemedParColumn(g.Theme, g.EmptyMessage)
 	} else {
-		start = 0
-		end = availableLines
+		p = NewParColumn(g.EmptyMessage)
+	}
+	width := len([]rune(g.EmptyMessage))
+	if width > g.Width {
+		width = g.Width
 	}
+	p.Height = 1
+	p.SetWidth(width)
+	p.SetX(g.X + (g.Width-width)/2)
+	p.SetY(g.Y + g.Height/2)
+	return p
+}
+
+//pageRows returns the rows of the page that includes the row at the Offset position.
+//Rows can have any height, the page has as many rows as fit in the lines available
+//between the header and the footer, its first row is decided by the ScrollPolicy.
+//If the Offset row does not fit on the page, the Offset row is the last one of the page.
+func (g *Grid) pageRows() []ui.GridBufferer {
+	rows, start, end := g.page()
 	return rows[start:end]
 }
+
+//page returns the rows shown and the positions, on them, of the first row of the
+//page and of the row after the last one, see pageRows. The grid is not changed,
+//the first row is remembered by Align.
+func (g *Grid) page() (rows []ui.GridBufferer, start, end int) {
+	rows = g.shownRows()
+	if len(rows) == 0 {
+		return rows, 0, 0
+	}
+	availableLines := g.availableLines()
+	cursor := g.Offset
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+
+	switch g.ScrollPolicy {
+	case ScrollKeepVisible:
+		start = g.first
+		if start > cursor || start >= len(rows) {
+			start = cursor
+		}
+	case ScrollAnchorTop:
+		start = cursor
+	case ScrollAnchorCenter:
+		start = cursor
+		lines := (availableLines - rows[cursor].GetHeight()) / 2
+		for start > 0 && lines-rows[start-1].GetHeight() >= 0 {
+			start--
+			lines -= rows[start].GetHeight()
+		}
+	case ScrollAnchorBottom:
+		start = len(rows)
+	}
+
+	end = start
+	lines := 0
+	for end < len(rows) && lines+rows[end].GetHeight() <= availableLines {
+		lines += rows[end].GetHeight()
+		end++
+	}
+	//No lines are left empty if there are rows before the page
+	for end == len(rows) && start > 0 && lines+rows[start-1].GetHeight() <= availableLines {
+		start--
+		lines += rows[start].GetHeight()
+	}
+	if cursor >= start && cursor < end {
+		return rows, start, end
+	}
+	//The page ends with the cursor row, a row taller than the available
+	//lines is shown alone, clipped.
+	start, lines = cursor, rows[cursor].GetHeight()
+	for start > 0 && lines+rows[start-1].GetHeight() <= availableLines {
+		start--
+		lines += rows[start].GetHeight()
+	}
+	return rows, start, cursor + 1
+}
+
+//availableLines returns the number of lines available to show rows, never negative
+func (g *Grid) availableLines() int {
+	lines := g.GetHeight()
+	header, footer, hints := g.shownChrome()
+	lines -= heightOf(header) + heightOf(footer) + heightOf(hints)
+	if lines < 0 {
+		return 0
+	}
+	return lines
+}
+
+//rowsBottom returns the line below the lines available to show rows, rows are
+//clipped on it
+func (g *Grid) rowsBottom() int {
+	_, footer, hints := g.shownChrome()
+	return g.Y + g.GetHeight() - heightOf(footer) - heightOf(hints)
+}
@@ -0,0 +1,343 @@
+package termui
+
+import (
+	"strings"
+	"testing"
+
+	ui "github.com/gizak/termui"
+)
+
+func TestGridEmptyMessage(t *testing.T) {
+	g := NewGrid(0, 0, 10, 80)
+	g.EmptyMessage = "Nothing to show"
+	g.Align()
+
+	if content := bufferContent(g.Buffer()); !strings.Contains(content, "Nothing to show") {
+		t.Errorf("Empty grid does not show its message, got: %q", content)
+	}
+
+	g.AddRows(NewParColumn(text))
+	g.Align()
+	if content := bufferContent(g.Buffer()); strings.Contains(content, "Nothing to show") {
+		t.Errorf("Grid with rows shows the empty message, got: %q", content)
+	}
+}
+
+func bufferContent(buf ui.Buffer) string {
+	var runes []rune
+	for y := buf.Area.Min.Y; y < buf.Area.Max.Y; y++ {
+		for x := buf.Area.Min.X; x < buf.Area.Max.X; x++ {
+			runes = append(runes, buf.At(x, y).Ch)
+		}
+	}
+	return string(runes)
+}
+
+type alternatingRow struct {
+	*ParColumn
+	odd bool
+}
+
+func (r *alternatingRow) SetOdd(odd bool) {
+	r.odd = odd
+}
+
+func TestGridAlternateRows(t *testing.T) {
+	g := NewGrid(0, 0, 10, 80)
+	var rows []*alternatingRow
+	for i := 0; i < 3; i++ {
+		r := &alternatingRow{ParColumn: NewParColumn(text)}
+		rows = append(rows, r)
+		g.AddRows(r)
+	}
+	g.Align()
+	for i, r := range rows {
+		if r.odd {
+			t.Errorf("Row %d is marked as odd with alternate rows disabled", i)
+		}
+	}
+	g.AlternateRows = true
+	g.Align()
+	for i, r := range rows {
+		if r.odd != (i%2 == 1) {
+			t.Errorf("Unexpected parity of row %d: odd is %t", i, r.odd)
+		}
+	}
+}
+
+func TestGridMaxRows(t *testing.T) {
+	g := NewGrid(0, 0, 10, 80)
+	g.MaxRows = 2
+	var hidden []ui.GridBufferer
+	remainder := NewParColumn("+2 more")
+	g.Remainder = func(rows []ui.GridBufferer) ui.GridBufferer {
+		hidden = rows
+		return remainder
+	}
+	for i := 0; i < 4; i++ {
+		g.AddRows(NewParColumn(text))
+	}
+	rows := g.pageRows()
+	if len(rows) != 3 || rows[2] != remainder {
+		t.Errorf("Unexpected rows shown with a maximum of 2 rows: %d", len(rows))
+	}
+	if len(hidden) != 2 || g.ShownRowCount() != 2 || g.RowCount() != 4 {
+		t.Errorf("Unexpected hidden rows: %d, shown: %d", len(hidden), g.ShownRowCount())
+	}
+	g.MaxRows = 0
+	if rows := g.pageRows(); len(rows) != 4 {
+		t.Errorf("Rows are not all shown without a maximum: %d", len(rows))
+	}
+}
+
+func TestGridMixedHeightRows(t *testing.T) {
+	g := NewGrid(0, 0, 10, 80)
+	header := NewParColumn(text)
+	header.Height = 1
+	g.SetHeader(header)
+	var rows []*ParColumn
+	for _, height := range []int{1, 3, 2, 4, 1} {
+		r := NewParColumn(text)
+		r.Height = height
+		rows = append(rows, r)
+		g.AddRows(r)
+	}
+	g.Align()
+	//9 lines are available below the header: rows of height 1, 3, 2 fit
+	if page := g.pageRows(); len(page) != 3 {
+		t.Fatalf("Unexpected number of rows on the first page: %d", len(page))
+	}
+	for i, expected := range []int{1, 2, 5} {
+		if rows[i].Y != expected {
+			t.Errorf("Unexpected Y position of row %d. Expected: %d, got: %d", i, expected, rows[i].Y)
+		}
+	}
+
+	//The cursor row is the last one of the page
+	g.Offset = 3
+	g.Align()
+	page := g.pageRows()
+	if len(page) != 3 || page[0] != rows[1] || page[2] != rows[3] {
+		t.Fatalf("Unexpected rows on the page of row 3: %d", len(page))
+	}
+	if rows[1].Y != 1 || rows[2].Y != 4 || rows[3].Y != 6 {
+		t.Errorf("Unexpected Y positions on the page of row 3: %d, %d, %d", rows[1].Y, rows[2].Y, rows[3].Y)
+	}
+
+	//A row taller than the grid is shown alone, clipped
+	rows[3].Height = 20
+	g.Align()
+	if page := g.pageRows(); len(page) != 1 || page[0] != rows[3] {
+		t.Fatalf("Unexpected rows on the page of a row taller than the grid: %d", len(page))
+	}
+	if buf := g.Buffer(); buf.Area.Max.Y > g.Y+g.Height {
+		t.Errorf("Rows are not clipped to the grid height, buffer ends at line %d", buf.Area.Max.Y)
+	}
+}
+
+func TestGridScrollPolicies(t *testing.T) {
+	newGrid := func(policy ScrollPolicy) *Grid {
+		g := NewGrid(0, 0, 4, 80)
+		g.ScrollPolicy = policy
+		for i := 0; i < 10; i++ {
+			r := NewParColumn(text)
+			r.Height = 1
+			g.AddRows(r)
+		}
+		return g
+	}
+	first := func(g *Grid) int {
+		page := g.pageRows()
+		for i, r := range g.rows {
+			if r == page[0] {
+				return i
+			}
+		}
+		return -1
+	}
+	var tests = []struct {
+		policy   ScrollPolicy
+		offsets  []int
+		expected []int
+	}{
+		{ScrollPaged, []int{0, 3, 4, 5, 2}, []int{0, 0, 1, 2, 0}},
+		{ScrollKeepVisible, []int{0, 3, 4, 5, 3, 1, 9}, []int{0, 0, 1, 2, 2, 1, 6}},
+		{ScrollAnchorTop, []int{0, 3, 8}, []int{0, 3, 6}},
+		{ScrollAnchorCenter, []int{0, 1, 5, 9}, []int{0, 0, 4, 6}},
+		{ScrollAnchorBottom, []int{0, 3, 5}, []int{0, 0, 2}},
+	}
+	for _, test := range tests {
+		g := newGrid(test.policy)
+		for i, offset := range test.offsets {
+			g.Offset = offset
+			g.Align()
+			if f := first(g); f != test.expected[i] {
+				t.Errorf("Unexpected first row with policy %d and offset %d. Expected: %d, got: %d", test.policy, offset, test.expected[i], f)
+			}
+		}
+	}
+}
+
+func TestGridBufferDoesNotChangeThePage(t *testing.T) {
+	g := NewGrid(0, 0, 4, 80)
+	g.ScrollPolicy = ScrollKeepVisible
+	for i := 0; i < 10; i++ {
+		r := NewParColumn(text)
+		r.Height = 1
+		g.AddRows(r)
+	}
+	g.Offset = 9
+	g.Align()
+	first := g.first
+	//Rendering happens concurrently, only Align decides the page
+	g.Offset = 0
+	g.Buffer()
+	if g.first != first {
+		t.Errorf("Rendering changed the first row of the page from %d to %d", first, g.first)
+	}
+}
+
+func TestParseScrollPolicy(t *testing.T) {
+	if policy, err := ParseScrollPolicy("center"); err != nil || policy != ScrollAnchorCenter {
+		t.Errorf("Unexpected scroll policy: %d, error: %v", policy, err)
+	}
+	if _, err := ParseScrollPolicy("middle"); err == nil {
+		t.Error("An unknown scroll policy was parsed")
+	}
+}
+
+func BenchmarkGridBuffer(b *testing.B) {
+	g := NewGrid(0, 0, 60, 200)
+	for i := 0; i < 200; i++ {
+		r := NewParColumn(text)
+		r.Height = 1
+		g.AddRows(r)
+	}
+	g.Offset = 150
+	g.Align()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Buffer()
+	}
+}
+
+func TestGridBufferHasThePageRowsCells(t *testing.T) {
+	g := NewGrid(0, 0, 6, 40)
+	footer := NewParColumn("footer")
+	footer.Height = 1
+	g.SetFooter(footer)
+	for _, height := range []int{1, 2, 1, 3} {
+		r := NewParColumn(text)
+		r.Height = height
+		g.AddRows(r)
+	}
+	g.Align()
+
+	expected := ui.NewBuffer()
+	expected.Merge(footer.Buffer())
+	for _, r := range g.pageRows() {
+		for p, c := range r.Buffer().CellMap {
+			if p.Y < g.Height-1 {
+				expected.Set(p.X, p.Y, c)
+			}
+		}
+	}
+	buf := g.Buffer()
+	if len(buf.CellMap) != len(expected.CellMap) {
+		t.Fatalf("Unexpected number of cells. Expected: %d, got: %d", len(expected.CellMap), len(buf.CellMap))
+	}
+	for p, c := range expected.CellMap {
+		if buf.CellMap[p] != c {
+			t.Errorf("Unexpected cell at %v. Expected: %v, got: %v", p, c, buf.CellMap[p])
+		}
+	}
+	if buf.Area.Max.Y != g.Height {
+		t.Errorf("Unexpected buffer area: %v", buf.Area)
+	}
+}
+
+func TestGridShortHeightShowsRowsFirst(t *testing.T) {
+	newGrid := func(height int) (*Grid, *ParColumn, *ParColumn, *ParColumn) {
+		g := NewGrid(0, 0, height, 20)
+		header := NewParColumn("header")
+		header.Height = 1
+		footer := NewParColumn("footer")
+		footer.Height = 1
+		row := NewParColumn("row")
+		row.Height = 1
+		g.SetHeader(header)
+		g.SetFooter(footer)
+		another := NewParColumn("another")
+		another.Height = 1
+		g.AddRows(row, another)
+		g.Align()
+		return g, header, footer, row
+	}
+	tests := []struct {
+		height         int
+		header, footer bool
+		rows           int
+	}{
+		{4, true, true, 2},
+		{3, true, true, 1},
+		{2, true, false, 1},
+		{1, false, false, 1},
+		{0, false, false, 1},
+	}
+	for _, test := range tests {
+		g, header, footer, row := newGrid(test.height)
+		h, f, _ := g.shownChrome()
+		if (h == header) != test.header || (f == footer) != test.footer {
+			t.Errorf("Height %d: unexpected header or footer shown: %v, %v", test.height, h != nil, f != nil)
+		}
+		if lines := g.availableLines(); lines < 0 {
+			t.Errorf("Height %d: negative lines available: %d", test.height, lines)
+		}
+		if page := g.pageRows(); len(page) != test.rows || page[0] != row {
+			t.Errorf("Height %d: unexpected rows shown: %d", test.height, len(page))
+		}
+		content := bufferContent(g.Buffer())
+		truncated := strings.ContainsRune(content, TruncatedMark)
+		if test.height > 0 && truncated == (test.header && test.footer) {
+			t.Errorf("Height %d: unexpected truncation mark, shown: %v, content: %q", test.height, truncated, content)
+		}
+		if test.height > 0 && !strings.Contains(content, "row") {
+			t.Errorf("Height %d: row not shown, content: %q", test.height, content)
+		}
+	}
+}
+
+func TestGridPageRowsBoundaries(t *testing.T) {
+	tests := []struct {
+		rows          int
+		offset        int
+		expectedFirst int
+		expectedCount int
+	}{
+		{4, 0, 0, 4},
+		{4, 3, 0, 4},
+		{5, 0, 0, 5},
+		{5, 4, 0, 5},
+		{6, 0, 0, 5},
+		{6, 4, 0, 5},
+		{6, 5, 1, 5},
+	}
+	for _, test := range tests {
+		//5 lines are available for rows
+		g := NewGrid(0, 0, 5, 80)
+		var rows []*ParColumn
+		for i := 0; i < test.rows; i++ {
+			r := NewParColumn(text)
+			r.Height = 1
+			rows = append(rows, r)
+			g.AddRows(r)
+		}
+		g.Offset = test.offset
+		g.Align()
+		page := g.pageRows()
+		if len(page) != test.expectedCount || page[0] != rows[test.expectedFirst] {
+			t.Errorf("%d rows, offset %d: unexpected page of %d rows", test.rows, test.offset, len(page))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package termui
+
+import (
+	ui "github.com/gizak/termui"
+	dryui "github.com/moncho/dry/ui"
+)
+
+//keyHintSpacing is the number of cells between two hints
+const keyHintSpacing = 2
+
+//KeyHint is a key and the action it triggers
+type KeyHint struct {
+	Key    string
+	Action string
+}
+
+//KeyHints is a single line showing key hints, i.e. "F3: filter", the hints
+//that do not fit on its width are not shown.
+type KeyHints struct {
+	hints []KeyHint
+	theme *dryui.ColorTheme
+	X, Y  int
+	Width int
+}
+
+//NewKeyHints creates a KeyHints component styled with the given theme
+func NewKeyHints(theme *dryui.ColorTheme, hints ...KeyHint) *KeyHints {
+	return &KeyHints{theme: theme, hints: hints}
+}
+
+//SetHints sets the hints shown
+func (k *KeyHints) SetHints(hints ...KeyHint) {
+	k.hints = hints
+}
+
+//Hints returns the hints shown
+func (k *KeyHints) Hints() []KeyHint {
+	return k.hints
+}
+
+//GetHeight returns this KeyHints height
+func (k *KeyHints) GetHeight() int {
+	return 1
+}
+
+//SetX sets the x position of this KeyHints
+func (k *KeyHints) SetX(x int) {
+	k.X = x
+}
+
+//SetY sets the y position of this KeyHints
+func (k *KeyHints) SetY(y int) {
+	k.Y = y
+}
+
+//SetWidth sets the width of this KeyHints
+func (k *KeyHints) SetWidth(width int) {
+	k.Width = width
+}
+
+//Buffer returns this KeyHints content as a termui.Buffer
+func (k *KeyHints) Buffer() ui.Buffer {
+	buf := NewSizedBuffer(k.Width, 1)
+	bg := ui.Attribute(k.theme.Footer)
+	keyFg := ui.Attribute(k.theme.Key) | ui.AttrBold
+	actionFg := ui.Attribute(k.theme.Fg)
+	for x := k.X; x < k.X+k.Width; x++ {
+		buf.Set(x, k.Y, ui.Cell{Ch: ' ', Bg: bg})
+	}
+	x := k.X
+	for i, hint := range k.hints {
+		key, action := []rune(hint.Key+":"), []rune(" "+hint.Action)
+		start := x
+		if i > 0 {
+			start += keyHintSpacing
+		}
+		if start+len(key)+len(action) > k.X+k.Width {
+			break
+		}
+		x = start
+		for _, r := range key {
+			buf.Set(x, k.Y, ui.Cell{Ch: r, Fg: keyFg, Bg: bg})
+			x++
+		}
+		for _, r := range action {
+			buf.Set(x, k.Y, ui.Cell{Ch: r, Fg: actionFg, Bg: bg})
+			x++
+		}
+	}
+	return buf
+}
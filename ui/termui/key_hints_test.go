@@ -0,0 +1,73 @@
+package termui
+
+import (
+	"strings"
+	"testing"
+
+	ui "github.com/gizak/termui"
+	dryui "github.com/moncho/dry/ui"
+)
+
+//lineContent returns the characters of the given line of the given buffer
+func lineContent(buf ui.Buffer, y, width int) string {
+	var runes []rune
+	for x := 0; x < width; x++ {
+		runes = append(runes, buf.At(x, y).Ch)
+	}
+	return strings.TrimRight(string(runes), " ")
+}
+
+func TestKeyHintsDropHintsThatDoNotFit(t *testing.T) {
+	hints := NewKeyHints(&dryui.ColorTheme{Fg: 7, Key: 3, Footer: 0},
+		KeyHint{"F3", "filter"}, KeyHint{"/", "search"}, KeyHint{"q", "quit"})
+	tests := []struct {
+		width    int
+		expected string
+	}{
+		{40, "F3: filter  /: search  q: quit"},
+		{25, "F3: filter  /: search"},
+		{5, ""},
+	}
+	for _, test := range tests {
+		hints.SetWidth(test.width)
+		if content := lineContent(hints.Buffer(), 0, test.width); content != test.expected {
+			t.Errorf("Width %d: unexpected hints. Expected: %q, got: %q", test.width, test.expected, content)
+		}
+	}
+	hints.SetHints(KeyHint{"esc", "cancel"})
+	hints.SetWidth(40)
+	buf := hints.Buffer()
+	if content := lineContent(buf, 0, 40); content != "esc: cancel" {
+		t.Errorf("Unexpected hints after setting them: %q", content)
+	}
+	if key, action := buf.At(0, 0), buf.At(5, 0); key.Fg != ui.Attribute(3)|ui.AttrBold || action.Fg != ui.Attribute(7) {
+		t.Errorf("Hints are not styled with the theme, key: %v, action: %v", key, action)
+	}
+}
+
+func TestGridHints(t *testing.T) {
+	g := NewGrid(0, 0, 4, 20)
+	header := NewParColumn("header")
+	header.Height = 1
+	footer := NewParColumn("footer")
+	footer.Height = 1
+	row := NewParColumn("row")
+	row.Height = 1
+	g.SetHeader(header)
+	g.SetFooter(footer)
+	g.SetHints(NewKeyHints(&dryui.ColorTheme{}, KeyHint{"q", "quit"}))
+	g.AddRows(row)
+	g.Align()
+	buf := g.Buffer()
+	for y, expected := range []string{"header", "row", "footer", "q: quit"} {
+		if content := lineContent(buf, y, 20); content != expected {
+			t.Errorf("Line %d: expected: %q, got: %q", y, expected, content)
+		}
+	}
+
+	g.Height = 3
+	g.Align()
+	if _, f, h := g.shownChrome(); f != footer || h != nil {
+		t.Error("Hints are not dropped first on a short grid")
+	}
+}
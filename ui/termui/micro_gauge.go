@@ -0,0 +1,86 @@
+package termui
+
+import (
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/ui"
+)
+
+//microGaugeLevels are the glyphs used to show how full a cell of a MicroGauge is
+var microGaugeLevels = []rune(" ▁▂▃▄▅▆▇█")
+
+//MicroGauge shows a percentage as a few block characters, it is meant to be
+//used on columns too narrow for a GaugeColumn.
+type MicroGauge struct {
+	X, Y, Width int
+	Percent     int
+	BarColor    termui.Attribute
+	theme       *ui.ColorTheme
+}
+
+//NewThemedMicroGauge creates a MicroGauge using the given color theme
+func NewThemedMicroGauge(theme *ui.ColorTheme) *MicroGauge {
+	return &MicroGauge{theme: theme, BarColor: termui.Attribute(theme.Fg)}
+}
+
+//GetHeight returns the height of this MicroGauge, which is always 1
+func (g *MicroGauge) GetHeight() int {
+	return 1
+}
+
+//SetX sets the x position of this MicroGauge
+func (g *MicroGauge) SetX(x int) {
+	g.X = x
+}
+
+//SetY sets the y position of this MicroGauge
+func (g *MicroGauge) SetY(y int) {
+	g.Y = y
+}
+
+//SetWidth sets the width of this MicroGauge
+func (g *MicroGauge) SetWidth(w int) {
+	g.Width = w
+}
+
+//Text returns the glyphs that show the percentage of this MicroGauge
+func (g *MicroGauge) Text() string {
+	return string(g.cells())
+}
+
+//Buffer returns the content of this MicroGauge as a termui.Buffer
+func (g *MicroGauge) Buffer() termui.Buffer {
+	buf := termui.NewBuffer()
+	bg := termui.ColorDefault
+	if g.theme != nil {
+		bg = termui.Attribute(g.theme.Bg)
+	}
+	for i, r := range g.cells() {
+		buf.Set(g.X+i, g.Y, termui.Cell{Ch: r, Fg: g.BarColor, Bg: bg})
+	}
+	return buf
+}
+
+//cells returns a glyph per cell, cells are filled from left to right and the
+//level of each glyph shows how full the cell is.
+func (g *MicroGauge) cells() []rune {
+	percent := g.Percent
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	levels := len(microGaugeLevels) - 1
+	//the number of levels that are full, considering every cell
+	filled := percent * g.Width * levels / 100
+	cells := make([]rune, g.Width)
+	for i := range cells {
+		level := filled - i*levels
+		if level < 0 {
+			level = 0
+		} else if level > levels {
+			level = levels
+		}
+		cells[i] = microGaugeLevels[level]
+	}
+	return cells
+}
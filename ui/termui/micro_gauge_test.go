@@ -0,0 +1,25 @@
+package termui
+
+import "testing"
+
+func TestMicroGauge(t *testing.T) {
+	var tests = []struct {
+		percent  int
+		width    int
+		expected string
+	}{
+		{0, 3, "   "},
+		{100, 3, "███"},
+		{50, 1, "▄"},
+		{50, 2, "█ "},
+		{75, 2, "█▄"},
+		{150, 2, "██"},
+	}
+	for _, test := range tests {
+		g := &MicroGauge{Percent: test.percent, Width: test.width}
+		if text := g.Text(); text != test.expected {
+			t.Errorf("Unexpected micro gauge for %d%% on %d cells. Expected: %q, got: %q",
+				test.percent, test.width, test.expected, text)
+		}
+	}
+}
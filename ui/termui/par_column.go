@@ -8,14 +8,19 @@ import (
 //ParColumn is a termui.Par that can be used in a grid to show text
 type ParColumn struct {
 	termui.Par
+	theme *ui.ColorTheme
+	//themeFg is the text color last taken from the theme
+	themeFg termui.Attribute
 }
 
-//NewThemedParColumn creates a new paragraph column with the given text using the given color theme
+//NewThemedParColumn creates a new paragraph column with the given text using the given color theme.
+//The theme colors are read on each render, so changes on the theme are shown on the column.
 func NewThemedParColumn(theme *ui.ColorTheme, s string) *ParColumn {
 	p := NewParColumn(s)
-	p.Bg = termui.Attribute(theme.Bg)
-	p.TextBgColor = termui.Attribute(theme.Bg)
+	p.theme = theme
 	p.TextFgColor = termui.Attribute(theme.Fg)
+	p.themeFg = p.TextFgColor
+	p.applyTheme()
 	return p
 }
 
@@ -23,7 +28,29 @@ func NewThemedParColumn(theme *ui.ColorTheme, s string) *ParColumn {
 func NewParColumn(s string) *ParColumn {
 	p := termui.NewPar(s)
 	p.Border = false
-	return &ParColumn{*p}
+	return &ParColumn{Par: *p}
+}
+
+//Buffer returns the content of this column as a termui.Buffer
+func (w *ParColumn) Buffer() termui.Buffer {
+	if w.theme != nil {
+		w.applyTheme()
+	}
+	return w.Par.Buffer()
+}
+
+//applyTheme sets the theme colors on this column. The text color is only
+//changed if it was not changed after it was taken from the theme, text
+//attributes are kept.
+func (w *ParColumn) applyTheme() {
+	bg := termui.Attribute(w.theme.Bg)
+	w.Bg = bg
+	w.TextBgColor = bg
+	fg := termui.Attribute(w.theme.Fg)
+	if colorOf(w.TextFgColor) == w.themeFg {
+		w.TextFgColor = fg | attributesOf(w.TextFgColor)
+		w.themeFg = fg
+	}
 }
 
 //Reset resets the text on this Par
@@ -35,3 +62,16 @@ func (w *ParColumn) Reset() {
 func (w *ParColumn) Content(s string) {
 	w.Text = s
 }
+
+//textAttributes are the text attributes that can be combined with a color
+const textAttributes = termui.AttrBold | termui.AttrUnderline | termui.AttrReverse
+
+//colorOf returns the color of the given attribute, with no text attributes
+func colorOf(a termui.Attribute) termui.Attribute {
+	return a &^ textAttributes
+}
+
+//attributesOf returns the text attributes of the given attribute
+func attributesOf(a termui.Attribute) termui.Attribute {
+	return a & textAttributes
+}
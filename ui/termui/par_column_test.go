@@ -1,6 +1,11 @@
 package termui
 
-import "testing"
+import (
+	"testing"
+
+	termui "github.com/gizak/termui"
+	"github.com/moncho/dry/ui"
+)
 
 const text = "Move along, nothing to see here"
 
@@ -19,3 +24,26 @@ func TestParColumn(t *testing.T) {
 
 	}
 }
+
+func TestThemedParColumnFollowsTheme(t *testing.T) {
+	theme := &ui.ColorTheme{Fg: ui.Color255, Bg: ui.Color234}
+	p := NewThemedParColumn(theme, text)
+	p.TextFgColor |= termui.AttrReverse
+
+	*theme = ui.ColorTheme{Fg: ui.Color241, Bg: ui.Color231}
+	p.Buffer()
+	if p.Bg != termui.Attribute(ui.Color231) || p.TextBgColor != termui.Attribute(ui.Color231) {
+		t.Errorf("Background color does not follow the theme: %d", p.Bg)
+	}
+	if p.TextFgColor != termui.Attribute(ui.Color241)|termui.AttrReverse {
+		t.Errorf("Text color does not follow the theme: %d", p.TextFgColor)
+	}
+
+	custom := termui.Attribute(ui.Color161)
+	p.TextFgColor = custom
+	*theme = ui.ColorTheme{Fg: ui.Color255, Bg: ui.Color234}
+	p.Buffer()
+	if p.TextFgColor != custom {
+		t.Errorf("A text color not taken from the theme was changed: %d", p.TextFgColor)
+	}
+}
@@ -10,3 +10,9 @@ type SizableBufferer interface {
 	SetX(int)
 	SetY(int)
 }
+
+//Alternating is implemented by grid rows that are rendered differently
+//depending on their position, odd or even, on the grid page
+type Alternating interface {
+	SetOdd(odd bool)
+}
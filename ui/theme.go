@@ -2,8 +2,10 @@ package ui
 
 //ColorTheme represents a color theme
 type ColorTheme struct {
-	Fg           Color
-	Bg           Color
+	Fg Color
+	Bg Color
+	//AltBg is the background color of alternate rows
+	AltBg        Color
 	DarkBg       Color
 	Prompt       Color
 	Key          Color
@@ -15,4 +17,26 @@ type ColorTheme struct {
 	Selected     Color
 	Header       Color
 	Footer       Color
+	//GaugeLevels are the colors of gauges from low to high values
+	GaugeLevels [3]Color
+}
+
+//DefaultPercentThresholds are the percentages above which a value changes
+//from the color of a gauge level to the color of the next level
+var DefaultPercentThresholds = []int{70, 90}
+
+//ColorForPercent returns the color of the given percentage, the gauge level color
+//of the number of the given thresholds that the percentage exceeds. If no
+//thresholds are given, DefaultPercentThresholds are used.
+func (t *ColorTheme) ColorForPercent(n int, thresholds []int) Color {
+	if thresholds == nil {
+		thresholds = DefaultPercentThresholds
+	}
+	level := 0
+	for _, threshold := range thresholds {
+		if n > threshold && level < len(t.GaugeLevels)-1 {
+			level++
+		}
+	}
+	return t.GaugeLevels[level]
 }
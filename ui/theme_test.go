@@ -0,0 +1,26 @@
+package ui
+
+import "testing"
+
+func TestColorForPercent(t *testing.T) {
+	theme := &ColorTheme{GaugeLevels: [3]Color{Color23, Color131, Color161}}
+	var tests = []struct {
+		percent    int
+		thresholds []int
+		expected   Color
+	}{
+		{0, nil, Color23},
+		{70, nil, Color23},
+		{71, nil, Color131},
+		{90, nil, Color131},
+		{91, nil, Color161},
+		{50, []int{40}, Color131},
+		{50, []int{10, 20, 30, 40}, Color161},
+		{5, []int{}, Color23},
+	}
+	for _, test := range tests {
+		if c := theme.ColorForPercent(test.percent, test.thresholds); c != test.expected {
+			t.Errorf("Unexpected color for %d%% with thresholds %v. Expected: %d, got: %d", test.percent, test.thresholds, test.expected, c)
+		}
+	}
+}